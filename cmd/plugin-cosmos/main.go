@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/config"
+)
+
+const usage = `Usage: plugin-cosmos <command> [flags]
+
+Commands:
+  chain-registry  Print the config of a chain from its chain-registry chain.json and assetlist.json
+  unsigned-tx     Print an unsigned tx executing a contract, to be signed offline
+  broadcast       Broadcast an unsigned tx with a signature produced offline
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "chain-registry":
+		err = chainRegistry(args)
+	case "unsigned-tx":
+		err = unsignedTx(args)
+	case "broadcast":
+		err = broadcast(args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n%s", cmd, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// chainRegistry prints a [[Cosmos]] config table, to be added to the node config.
+func chainRegistry(args []string) error {
+	fs := flag.NewFlagSet("chain-registry", flag.ExitOnError)
+	chainPath := fs.String("chain", "chain.json", "path to the chain.json of the chain")
+	assetListPath := fs.String("assetlist", "assetlist.json", "path to the assetlist.json of the chain")
+	_ = fs.Parse(args)
+
+	c, err := config.LoadChainRegistry(*chainPath, *assetListPath)
+	if err != nil {
+		return err
+	}
+	b, err := toml.Marshal(struct{ Cosmos config.TOMLConfigs }{Cosmos: config.TOMLConfigs{c}})
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(b)
+	return err
+}
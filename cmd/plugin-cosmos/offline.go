@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+
+	"github.com/goplugin/plugin-common/pkg/logger"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
+)
+
+// unsignedTx prints an unsigned tx executing a contract, such as an admin operation on an OCR2 contract,
+// to be signed offline by a cold wallet and sent with broadcast.
+func unsignedTx(args []string) error {
+	fs := flag.NewFlagSet("unsigned-tx", flag.ExitOnError)
+	node := fs.String("node", "http://localhost:26657", "tendermint URL of a node")
+	chainID := fs.String("chain-id", "", "ID of the chain")
+	prefix := fs.String("prefix", "wasm", "bech32 prefix of the chain")
+	sender := fs.String("sender", "", "address which signs the tx")
+	contract := fs.String("contract", "", "address of the contract to execute")
+	msg := fs.String("msg", "", `JSON execute msg, e.g. {"withdraw_funds":{"recipient":"...","amount":"1"}}`)
+	funds := fs.String("funds", "", "coins sent to the contract, e.g. 1000ucosm")
+	gasPrice := fs.String("gas-price", "0.015ucosm", "gas price, which determines the fee denom")
+	gasMultiplier := fs.Float64("gas-multiplier", client.DefaultGasLimitMultiplier, "multiplier of the simulated gas")
+	timeoutBlocks := fs.Int64("timeout-blocks", 0, "blocks from the latest block until the tx times out, or 0 for no timeout")
+	memo := fs.String("memo", "", "memo of the tx")
+	out := fs.String("out", "", "file to write the unsigned tx to, instead of stdout")
+	_ = fs.Parse(args)
+
+	if *chainID == "" || *sender == "" || *contract == "" || *msg == "" {
+		return errors.New("-chain-id, -sender, -contract and -msg are required")
+	}
+	if !json.Valid([]byte(*msg)) {
+		return errors.New("-msg must be JSON")
+	}
+	price, err := sdk.ParseDecCoin(*gasPrice)
+	if err != nil {
+		return fmt.Errorf("invalid -gas-price: %w", err)
+	}
+	coins, err := sdk.ParseCoinsNormalized(*funds)
+	if err != nil {
+		return fmt.Errorf("invalid -funds: %w", err)
+	}
+	params.InitCosmosSdk(*prefix, price.Denom)
+	codec := params.NewAddressCodec(*prefix)
+	senderAddr, err := codec.StringToBytes(*sender)
+	if err != nil {
+		return fmt.Errorf("invalid -sender: %w", err)
+	}
+	if _, err = codec.StringToBytes(*contract); err != nil {
+		return fmt.Errorf("invalid -contract: %w", err)
+	}
+
+	c, err := newClient(*chainID, *node, *prefix)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), client.DefaultTimeout)
+	defer cancel()
+	var timeoutHeight uint64
+	if *timeoutBlocks > 0 {
+		lb, err := c.LatestBlock(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get latest block: %w", err)
+		}
+		timeoutHeight = uint64(lb.SdkBlock.Header.Height + *timeoutBlocks)
+	}
+	execute := &wasmtypes.MsgExecuteContract{Sender: *sender, Contract: *contract, Msg: []byte(*msg), Funds: coins}
+	tx, err := c.BuildUnsignedTx(ctx, senderAddr, []sdk.Msg{execute}, *gasMultiplier, price, timeoutHeight, *memo)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(tx, "", "  ")
+	if err != nil {
+		return err
+	}
+	if *out != "" {
+		return os.WriteFile(*out, b, 0600)
+	}
+	_, err = fmt.Println(string(b))
+	return err
+}
+
+// broadcast signs an unsigned tx with a signature of its sign doc, produced offline, and broadcasts it.
+func broadcast(args []string) error {
+	fs := flag.NewFlagSet("broadcast", flag.ExitOnError)
+	node := fs.String("node", "http://localhost:26657", "tendermint URL of a node")
+	prefix := fs.String("prefix", "wasm", "bech32 prefix of the chain")
+	txPath := fs.String("tx", "unsigned-tx.json", "path to the unsigned tx")
+	pubKey := fs.String("pubkey", "", "hex-encoded compressed secp256k1 public key of the sender")
+	signature := fs.String("signature", "", "base64-encoded signature of the sign doc of the tx")
+	gasToken := fs.String("gas-token", "ucosm", "gas token of the chain, if the tx has no fee, e.g. with a zero -gas-price")
+	_ = fs.Parse(args)
+
+	b, err := os.ReadFile(*txPath)
+	if err != nil {
+		return err
+	}
+	var tx client.UnsignedTx
+	if err = json.Unmarshal(b, &tx); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", *txPath, err)
+	}
+	key, err := hex.DecodeString(*pubKey)
+	if err != nil || len(key) != secp256k1.PubKeySize {
+		return fmt.Errorf("-pubkey must be %d hex-encoded bytes", secp256k1.PubKeySize)
+	}
+	sig, err := base64.StdEncoding.DecodeString(*signature)
+	if err != nil {
+		return fmt.Errorf("invalid -signature: %w", err)
+	}
+	token := *gasToken
+	if len(tx.Fee) > 0 {
+		token = tx.Fee[0].Denom
+	}
+	params.InitCosmosSdk(*prefix, token)
+	txBytes, err := tx.Sign(&secp256k1.PubKey{Key: key}, sig)
+	if err != nil {
+		return err
+	}
+
+	c, err := newClient(tx.ChainID, *node, *prefix)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), client.DefaultTimeout)
+	defer cancel()
+	resp, err := c.Broadcast(ctx, txBytes, txtypes.BroadcastMode_BROADCAST_MODE_SYNC)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Println(resp.TxResponse.TxHash)
+	return err
+}
+
+func newClient(chainID, node, prefix string) (*client.Client, error) {
+	lggr, err := logger.New()
+	if err != nil {
+		return nil, err
+	}
+	c, err := client.NewClient(chainID, node, 10*time.Second, lggr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	c.SetAddressCodec(params.NewAddressCodec(prefix))
+	return c, nil
+}
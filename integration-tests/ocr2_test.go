@@ -243,7 +243,9 @@ func validateRounds(t *testing.T, cosmosClient *client.Client, ocrAddress types.
 	// TODO(BCI-1767): this needs to be able to support different readers
 	ocrLogger, err := relaylogger.New()
 	require.NoError(t, err, "Failed to create OCR relay logger")
-	ocrReader := cosmwasm.NewOCR2Reader(ocrAddress, cosmosClient, ocrLogger)
+	ocrCodec := params.NewAddressCodec(types.GetConfig().GetBech32AccountAddrPrefix())
+	ocrDigester := cosmwasm.NewOffchainConfigDigester(commonConfig.ChainId, ocrAddress, ocrCodec)
+	ocrReader := cosmwasm.NewOCR2Reader(ocrAddress, ocrCodec, cosmosClient, ocrDigester, ocrLogger)
 
 	type TransmissionDetails struct {
 		ConfigDigest    ocrtypes.ConfigDigest
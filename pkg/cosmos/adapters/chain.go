@@ -5,6 +5,7 @@ import (
 
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/config"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/denom"
 )
 
 type Chain interface {
@@ -15,4 +16,11 @@ type Chain interface {
 	TxManager() TxManager
 	// Reader returns a new Reader. If nodeName is provided, the underlying client must use that node.
 	Reader(nodeName string) (client.Reader, error)
+	// Subscriber returns a new Subscriber. If nodeName is provided, the underlying client must use that node.
+	Subscriber(nodeName string) (client.Subscriber, error)
+	// ReloadConfig replaces the chain config at runtime. Readers, the TxManager and contract caches
+	// use the new values from their next call. Returns an error, leaving the config unchanged, if cfg is invalid.
+	ReloadConfig(cfg *config.TOMLConfig) error
+	// Denoms returns the units of the denominations of the chain.
+	Denoms() *denom.Registry
 }
@@ -12,6 +12,8 @@ import (
 
 	"github.com/goplugin/plugin-libocr/offchainreporting2/types"
 	"golang.org/x/crypto/blake2s"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
 )
 
 const ConfigDigestPrefixCosmos types.ConfigDigestPrefix = 2
@@ -19,14 +21,18 @@ const ConfigDigestPrefixCosmos types.ConfigDigestPrefix = 2
 var _ types.OffchainConfigDigester = (*OffchainConfigDigester)(nil)
 
 type OffchainConfigDigester struct {
-	chainID  string
-	contract cosmosSDK.AccAddress
+	chainID      string
+	contract     cosmosSDK.AccAddress
+	addressCodec params.AddressCodec
 }
 
-func NewOffchainConfigDigester(chainID string, contract cosmosSDK.AccAddress) OffchainConfigDigester {
+// NewOffchainConfigDigester returns a digester for the contract on chainID. The digest commits to the
+// bech32 contract address, so addressCodec must have the prefix of the chain.
+func NewOffchainConfigDigester(chainID string, contract cosmosSDK.AccAddress, addressCodec params.AddressCodec) OffchainConfigDigester {
 	return OffchainConfigDigester{
-		chainID:  chainID,
-		contract: contract,
+		chainID:      chainID,
+		contract:     contract,
+		addressCodec: addressCodec,
 	}
 }
 
@@ -46,7 +52,11 @@ func (cd OffchainConfigDigester) ConfigDigest(ctx context.Context, cfg types.Con
 		return digest, err
 	}
 
-	if _, err := buf.WriteString(cd.contract.String()); err != nil {
+	contract, err := cd.addressCodec.BytesToString(cd.contract)
+	if err != nil {
+		return digest, err
+	}
+	if _, err := buf.WriteString(contract); err != nil {
 		return digest, err
 	}
 
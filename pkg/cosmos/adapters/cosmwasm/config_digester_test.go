@@ -10,6 +10,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/goplugin/plugin-common/pkg/utils/tests"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
 )
 
 var testConfig = types.ContractConfig{
@@ -36,6 +38,7 @@ func TestConfigDigester(t *testing.T) {
 	d := NewOffchainConfigDigester(
 		"ibiza-808",
 		sdk.MustAccAddressFromBech32("wasm1cd65xyq076dm9cw3xxqtdh4d6ypzug0edd9958"),
+		params.NewAddressCodec("wasm"),
 	)
 
 	digest, err := d.ConfigDigest(tests.Context(t), testConfig)
@@ -43,10 +46,21 @@ func TestConfigDigester(t *testing.T) {
 	assert.Equal(t, "000289b55121341b1ff99cc8e15659fb8de14fca52a695b2b269a7fb94059b9f", digest.Hex())
 }
 
+func TestConfigDigester_Bech32Prefix(t *testing.T) {
+	// The digest commits to the contract address as encoded on its own chain, regardless of the global prefix.
+	contract := sdk.MustAccAddressFromBech32("wasm1cd65xyq076dm9cw3xxqtdh4d6ypzug0edd9958")
+	wasm, err := NewOffchainConfigDigester("ibiza-808", contract, params.NewAddressCodec("wasm")).ConfigDigest(tests.Context(t), testConfig)
+	assert.NoError(t, err)
+	neutron, err := NewOffchainConfigDigester("ibiza-808", contract, params.NewAddressCodec("neutron")).ConfigDigest(tests.Context(t), testConfig)
+	assert.NoError(t, err)
+	assert.NotEqual(t, wasm, neutron)
+}
+
 func TestConfigDigester_InvalidChainID(t *testing.T) {
 	d := NewOffchainConfigDigester(
 		strings.Repeat("a", 256), // chain ID is too long
 		sdk.MustAccAddressFromBech32("wasm1cd65xyq076dm9cw3xxqtdh4d6ypzug0edd9958"),
+		params.NewAddressCodec("wasm"),
 	)
 
 	_, err := d.ConfigDigest(tests.Context(t), testConfig)
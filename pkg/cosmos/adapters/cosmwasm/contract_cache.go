@@ -7,20 +7,26 @@ import (
 	"sync"
 	"time"
 
+	cmttypes "github.com/cometbft/cometbft/types"
+
 	"github.com/goplugin/plugin-common/pkg/logger"
 	"github.com/goplugin/plugin-common/pkg/utils"
 	"github.com/goplugin/plugin-libocr/offchainreporting2/reportingplugin/median"
 	"github.com/goplugin/plugin-libocr/offchainreporting2/types"
 
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/config"
 )
 
 var _ median.MedianContract = (*ContractCache)(nil)
 
+// ContractCache polls the state of the OCR2 contract, and refreshes it as soon as a tx executes the contract,
+// if it has a subscriber.
 type ContractCache struct {
-	cfg    config.Config
-	reader *OCR2Reader
-	lggr   logger.Logger
+	cfg        config.Config
+	reader     *OCR2Reader
+	subscriber client.Subscriber
+	lggr       logger.Logger
 
 	stop, done chan struct{}
 
@@ -38,13 +44,15 @@ type ContractCache struct {
 	latestTimestamp time.Time
 }
 
-func NewContractCache(cfg config.Config, reader *OCR2Reader, lggr logger.Logger) *ContractCache {
+// NewContractCache returns a cache of the contract read by reader. subscriber is optional.
+func NewContractCache(cfg config.Config, reader *OCR2Reader, subscriber client.Subscriber, lggr logger.Logger) *ContractCache {
 	return &ContractCache{
-		cfg:    cfg,
-		reader: reader,
-		lggr:   lggr,
-		stop:   make(chan struct{}),
-		done:   make(chan struct{}),
+		cfg:        cfg,
+		reader:     reader,
+		subscriber: subscriber,
+		lggr:       lggr,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
 	}
 }
 
@@ -73,11 +81,26 @@ func (cc *ContractCache) Close() error {
 
 func (cc *ContractCache) poll() {
 	defer close(cc.done)
+	subCtx, cancelSub := utils.ContextFromChan(cc.stop)
+	defer cancelSub()
+	// subscribe in the background, so that polling starts while the node is unreachable
+	subscribed := make(chan (<-chan cmttypes.EventDataTx), 1)
+	go func() { subscribed <- cc.subscribe(subCtx) }()
+	var txs <-chan cmttypes.EventDataTx
 	tick := time.After(0)
 	for {
 		select {
 		case <-cc.stop:
 			return
+		case txs = <-subscribed:
+		case _, ok := <-txs:
+			if !ok {
+				txs = nil // keep polling
+				continue
+			}
+			// The transmission is read as of the block before the latest, so that it can be verified.
+			// Refresh once the block after the tx has been produced.
+			tick = time.After(cc.cfg.BlockRate())
 		case <-tick:
 			ctx, cancel := utils.ContextFromChan(cc.stop)
 			if err := cc.updateConfig(ctx); err != nil {
@@ -95,6 +118,24 @@ func (cc *ContractCache) poll() {
 	}
 }
 
+// subscribe returns the txs which execute the contract, or nil if there is no subscriber or subscribing fails.
+func (cc *ContractCache) subscribe(ctx context.Context) <-chan cmttypes.EventDataTx {
+	if cc.subscriber == nil {
+		return nil
+	}
+	address, err := cc.reader.addressCodec.BytesToString(cc.reader.address)
+	if err != nil {
+		cc.lggr.Errorf("Failed to encode contract address: %v", err)
+		return nil
+	}
+	txs, err := cc.subscriber.SubscribeTxs(ctx, fmt.Sprintf("wasm._contract_address='%s'", address))
+	if err != nil {
+		cc.lggr.Warnf("Failed to subscribe to contract txs, only polling: %v", err)
+		return nil
+	}
+	return txs
+}
+
 func (cc *ContractCache) updateConfig(ctx context.Context) error {
 	changedInBlock, configDigest, err := cc.reader.LatestConfigDetails(ctx)
 	if err != nil {
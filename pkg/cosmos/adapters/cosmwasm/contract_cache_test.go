@@ -0,0 +1,76 @@
+package cosmwasm
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	cmttypes "github.com/cometbft/cometbft/types"
+	tmtypes "github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	cosmosSDK "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goplugin/plugin-common/pkg/config"
+	"github.com/goplugin/plugin-common/pkg/logger"
+	"github.com/goplugin/plugin-common/pkg/utils/tests"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client/mocks"
+	cosmosconfig "github.com/goplugin/plugin-cosmos/pkg/cosmos/config"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
+)
+
+func TestContractCache_subscription(t *testing.T) {
+	address := cosmosSDK.AccAddress(bytes.Repeat([]byte{0x01}, 20))
+	codec := params.NewAddressCodec("wasm")
+	digest := mustStringToConfigDigest(t, "test config digest 32 chars long")
+
+	cfg := &cosmosconfig.TOMLConfig{Chain: cosmosconfig.Chain{
+		OCR2CachePollPeriod: config.MustNewDuration(time.Hour), // only refresh on txs
+		BlockRate:           config.MustNewDuration(time.Millisecond),
+	}}
+	cfg.SetDefaults()
+
+	chainReader := mocks.NewReaderWriter(t)
+	chainReader.On("ContractRawState", mock.Anything, address, []byte(configKey), int64(0)).
+		Return(contractStateJSON(t, digest, 1), nil)
+	// only the transmission is cached in this test
+	chainReader.On("BlockResults", mock.Anything, int64(42)).Return(nil, errors.New("pruned"))
+	chainReader.On("LatestBlock", mock.Anything).Return(&tmtypes.GetLatestBlockResponse{SdkBlock: &tmtypes.Block{
+		Header: tmtypes.Header{Height: 100},
+	}}, nil)
+	chainReader.On("ContractRawState", mock.Anything, address, []byte(configKey), int64(99)).
+		Return(contractStateJSON(t, digest, 1), nil)
+	chainReader.On("ContractRawState", mock.Anything, address, transmissionKey(1), int64(99)).
+		Return([]byte(`{"answer":"1","transmission_timestamp":1700000000}`), nil).Once()
+	chainReader.On("ContractRawState", mock.Anything, address, transmissionKey(1), int64(99)).
+		Return([]byte(`{"answer":"2","transmission_timestamp":1700000005}`), nil)
+
+	txs := make(chan cmttypes.EventDataTx)
+	subscriber := mocks.NewSubscriber(t)
+	subscriber.On("SubscribeTxs", mock.Anything, "wasm._contract_address='"+codec.MustBytesToString(address)+"'").
+		Return((<-chan cmttypes.EventDataTx)(txs), nil).Once()
+
+	reader := NewOCR2Reader(address, codec, chainReader, nil, logger.Test(t))
+	cache := NewContractCache(cfg, reader, subscriber, logger.Test(t))
+	require.NoError(t, cache.Start())
+	t.Cleanup(func() { assert.NoError(t, cache.Close()) })
+
+	latestAnswer := func() string {
+		_, _, _, answer, _, err := cache.LatestTransmissionDetails(tests.Context(t))
+		if err != nil {
+			return ""
+		}
+		return answer.String()
+	}
+	require.Eventually(t, func() bool { return latestAnswer() == "1" }, tests.WaitTimeout(t), 10*time.Millisecond)
+
+	select {
+	case txs <- cmttypes.EventDataTx{}:
+	case <-time.After(tests.WaitTimeout(t)):
+		t.Fatal("timed out waiting for subscription")
+	}
+	require.Eventually(t, func() bool { return latestAnswer() == "2" }, tests.WaitTimeout(t), 10*time.Millisecond)
+}
@@ -3,11 +3,12 @@ package cosmwasm
 import (
 	"context"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"strconv"
-	"strings"
 	"time"
 
 	cosmosSDK "github.com/cosmos/cosmos-sdk/types"
@@ -17,71 +18,133 @@ import (
 	"github.com/goplugin/plugin-common/pkg/logger"
 
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
 )
 
+// OCR2Reader reads the raw state of the OCR2 contract rather than querying it, so that the state can be verified
+// against merkle proofs when the client has verified reads enabled.
 type OCR2Reader struct {
-	address     cosmosSDK.AccAddress
-	chainReader client.Reader
-	lggr        logger.Logger
+	address      cosmosSDK.AccAddress
+	addressCodec params.AddressCodec
+	chainReader  client.Reader
+	digester     types.OffchainConfigDigester
+	lggr         logger.Logger
 }
 
-func NewOCR2Reader(addess cosmosSDK.AccAddress, chainReader client.Reader, lggr logger.Logger) *OCR2Reader {
+// NewOCR2Reader returns a reader of the OCR2 contract at address. The digester checks that the configs read from
+// set_config events match their digest.
+func NewOCR2Reader(addess cosmosSDK.AccAddress, addressCodec params.AddressCodec, chainReader client.Reader, digester types.OffchainConfigDigester, lggr logger.Logger) *OCR2Reader {
 	return &OCR2Reader{
-		address:     addess,
-		chainReader: chainReader,
-		lggr:        lggr,
+		address:      addess,
+		addressCodec: addressCodec,
+		chainReader:  chainReader,
+		digester:     digester,
+		lggr:         lggr,
 	}
 }
 
-func (r *OCR2Reader) LatestConfigDetails(ctx context.Context) (changedInBlock uint64, configDigest types.ConfigDigest, err error) {
-	resp, err := r.chainReader.ContractState(
-		ctx,
-		r.address,
-		[]byte(`{"latest_config_details":{}}`),
-	)
+// The keys under which the OCR2 contract stores its state with cw-storage-plus, see contracts/ocr2/src/state.rs.
+const (
+	configKey              = "config"
+	transmissionsNamespace = "transmissions"
+)
+
+// transmissionKey returns the key of the transmission of roundID, in the transmissions map.
+func transmissionKey(roundID uint32) []byte {
+	key := binary.BigEndian.AppendUint16(nil, uint16(len(transmissionsNamespace)))
+	key = append(key, transmissionsNamespace...)
+	return binary.BigEndian.AppendUint32(key, roundID)
+}
+
+// readState reads and decodes the JSON value stored under key as of height, returning false if it is not set.
+func (r *OCR2Reader) readState(ctx context.Context, key []byte, height int64, v any) (bool, error) {
+	b, err := r.chainReader.ContractRawState(ctx, r.address, key, height)
 	if err != nil {
-		return
+		return false, err
+	}
+	if b == nil {
+		return false, nil
+	}
+	return true, json.Unmarshal(b, v)
+}
+
+// readConfig reads the config of the contract as of height.
+func (r *OCR2Reader) readConfig(ctx context.Context, height int64) (ContractState, error) {
+	var state ContractState
+	ok, err := r.readState(ctx, []byte(configKey), height, &state)
+	if err != nil {
+		return ContractState{}, fmt.Errorf("failed to read contract config: %w", err)
+	}
+	if !ok {
+		return ContractState{}, fmt.Errorf("contract %s has no config", r.address)
 	}
-	var config ConfigDetails
-	if err = json.Unmarshal(resp, &config); err != nil {
+	return state, nil
+}
+
+func (r *OCR2Reader) LatestConfigDetails(ctx context.Context) (changedInBlock uint64, configDigest types.ConfigDigest, err error) {
+	state, err := r.readConfig(ctx, 0)
+	if err != nil {
 		return
 	}
-	changedInBlock = config.BlockNumber
-	configDigest = config.ConfigDigest
-	return
+	return state.LatestConfigBlockNumber, state.LatestConfigDigest, nil
 }
 
 func (r *OCR2Reader) LatestConfig(ctx context.Context, changedInBlock uint64) (types.ContractConfig, error) {
-	// previously we queried with constraint "wasm-set_config._contract_address='address'" directly, but that does not
-	// work with wasmd 0.41.0, which is at cosmos-sdk v0.47.4, which contains the following regex for each event query string:
-	// https://github.com/cosmos/cosmos-sdk/blob/3b509c187e1643757f5ef8a0b5ae3decca0c7719/x/auth/tx/service.go#L49
-	query := []string{fmt.Sprintf("tx.height=%d", changedInBlock), fmt.Sprintf("wasm._contract_address='%s'", r.address)}
-	res, err := r.chainReader.TxsEvents(ctx, query, nil)
+	// Read the block results rather than searching with TxsEvents, so that we don't depend on the node indexing txs.
+	res, err := r.chainReader.BlockResults(ctx, int64(changedInBlock))
 	if err != nil {
 		return types.ContractConfig{}, err
 	}
-	if len(res.TxResponses) == 0 {
-		return types.ContractConfig{}, fmt.Errorf("No transactions found for block %d, query %v", changedInBlock, query)
+	if len(res.TxsResults) == 0 {
+		return types.ContractConfig{}, fmt.Errorf("No transactions found for block %d", changedInBlock)
 	}
 
-	// Use the first matching tx we find, since results are in descending order.
-	for _, txResponse := range res.TxResponses {
-		if len(txResponse.Logs) == 0 {
+	// Use the last matching tx in the block, since it set the config in effect at the end of the block.
+	address, err := r.addressCodec.BytesToString(r.address)
+	if err != nil {
+		return types.ContractConfig{}, err
+	}
+	for i := len(res.TxsResults) - 1; i >= 0; i-- {
+		txResult := res.TxsResults[i]
+		if !txResult.IsOK() {
 			continue
 		}
-		for _, event := range txResponse.Logs[0].Events {
-			if event.Type == "wasm-set_config" {
+		for _, event := range client.DecodeWasmEvents(txResult.Events) {
+			if event.Type == "set_config" && event.ContractAddress == address {
 				cc, unknown, err := parseAttributes(event.Attributes)
 				if len(unknown) > 0 {
 					r.lggr.Warnf("wasm-set_config event contained unrecognized attributes: %v", unknown)
 				}
-				return cc, err
+				if err != nil {
+					return types.ContractConfig{}, err
+				}
+				return cc, r.checkConfig(ctx, cc, changedInBlock)
 			}
 		}
 	}
 	return types.ContractConfig{}, fmt.Errorf("No set_config event found in block %d", changedInBlock)
 }
 
+// checkConfig checks that cc, read from the unverified set_config event of block changedInBlock, matches its digest,
+// and that the digest is the config in the contract state as of that block, so that newer configs do not fail it.
+func (r *OCR2Reader) checkConfig(ctx context.Context, cc types.ContractConfig, changedInBlock uint64) error {
+	digest, err := r.digester.ConfigDigest(ctx, cc)
+	if err != nil {
+		return fmt.Errorf("failed to compute config digest: %w", err)
+	}
+	if digest != cc.ConfigDigest {
+		return fmt.Errorf("set_config event in block %d has digest %s, but its config has digest %s", changedInBlock, cc.ConfigDigest, digest)
+	}
+	state, err := r.readConfig(ctx, int64(changedInBlock))
+	if err != nil {
+		return err
+	}
+	if state.LatestConfigDigest != cc.ConfigDigest {
+		return fmt.Errorf("config %s set in block %d is not the config %s of the contract state in that block", cc.ConfigDigest, changedInBlock, state.LatestConfigDigest)
+	}
+	return nil
+}
+
 // parseAttributes returns a ContractConfig parsed from attrs.
 // An error will be returned if any of the 8 required attributes are not present, or if any duplicates are found for
 // unique attributes.
@@ -230,44 +293,44 @@ func (r *OCR2Reader) LatestTransmissionDetails(ctx context.Context) (
 	latestTimestamp time.Time,
 	err error,
 ) {
-	resp, err := r.chainReader.ContractState(ctx, r.address, []byte(`{"latest_transmission_details":{}}`))
+	// Read the config and the transmission it refers to at the same height.
+	// Only the state before the latest block can be verified, so read that either way.
+	latest, err := r.chainReader.LatestBlock(ctx)
 	if err != nil {
-		// Handle the 500 error that occurs when there has not been a submission
-		// "rpc error: code = Unknown desc = ocr2::state::Transmission not found: contract query failed: unknown request"
-		// which is thrown if this map lookup fails https://github.com/goplugin/plugin-cosmos/blob/main/contracts/ocr2/src/contract.rs#L759
-		if strings.Contains(fmt.Sprint(err), "ocr2::state::Transmission not found") {
-			r.lggr.Infof("No transmissions found when fetching `latest_transmission_details` attempting with `latest_config_digest_and_epoch`")
-			digest, epoch, err2 := r.LatestConfigDigestAndEpoch(ctx)
-
-			// In the case that there have been no transmissions, we expect the epoch to be zero.
-			// We return just the contract digest here and set the rest of the
-			// transmission details to their zero value.
-			if err2 == nil {
-				if epoch != 0 {
-					r.lggr.Errorf("unexpected non-zero epoch %v and no transmissions found contract %v", epoch, r.address)
-				}
-				return digest, epoch, 0, big.NewInt(0), time.Unix(0, 0), nil
-			}
-			r.lggr.Errorf("error reading latest config digest and epoch err %v contract %v", err2, r.address)
-		}
-
-		// default response if there actually is an error
-		return types.ConfigDigest{}, 0, 0, big.NewInt(0), time.Now(), err
+		return types.ConfigDigest{}, 0, 0, big.NewInt(0), time.Now(), fmt.Errorf("failed to get latest block: %w", err)
+	}
+	if latest.SdkBlock == nil {
+		return types.ConfigDigest{}, 0, 0, big.NewInt(0), time.Now(), errors.New("latest block is missing")
 	}
+	height := latest.SdkBlock.Header.Height - 1
 
-	// unmarshal
-	var details LatestTransmissionDetails
-	if err := json.Unmarshal(resp, &details); err != nil {
+	state, err := r.readConfig(ctx, height)
+	if err != nil {
 		return types.ConfigDigest{}, 0, 0, big.NewInt(0), time.Now(), err
 	}
+	var transmission Transmission
+	ok, err := r.readState(ctx, transmissionKey(state.LatestAggregatorRoundID), height, &transmission)
+	if err != nil {
+		return types.ConfigDigest{}, 0, 0, big.NewInt(0), time.Now(), fmt.Errorf("failed to read transmission: %w", err)
+	}
+	if !ok {
+		// In the case that there have been no transmissions, we expect the epoch to be zero.
+		// We return just the contract digest here and set the rest of the
+		// transmission details to their zero value.
+		r.lggr.Infof("No transmissions found, returning the latest config digest and epoch")
+		if state.Epoch != 0 {
+			r.lggr.Errorf("unexpected non-zero epoch %v and no transmissions found contract %v", state.Epoch, r.address)
+		}
+		return state.LatestConfigDigest, state.Epoch, 0, big.NewInt(0), time.Unix(0, 0), nil
+	}
 
 	// set answer big int
 	ans := new(big.Int)
-	if _, success := ans.SetString(details.LatestAnswer, 10); !success {
-		return types.ConfigDigest{}, 0, 0, big.NewInt(0), time.Now(), fmt.Errorf("Could not create *big.Int from %s", details.LatestAnswer)
+	if _, success := ans.SetString(transmission.Answer, 10); !success {
+		return types.ConfigDigest{}, 0, 0, big.NewInt(0), time.Now(), fmt.Errorf("Could not create *big.Int from %s", transmission.Answer)
 	}
 
-	return details.LatestConfigDigest, details.Epoch, details.Round, ans, time.Unix(details.LatestTimestamp, 0), nil
+	return state.LatestConfigDigest, state.Epoch, state.Round, ans, time.Unix(int64(transmission.TransmissionTimestamp), 0), nil
 }
 
 // LatestRoundRequested fetches the latest round requested by filtering event logs
@@ -339,17 +402,9 @@ func (r *OCR2Reader) LatestConfigDigestAndEpoch(ctx context.Context) (
 	epoch uint32,
 	err error,
 ) {
-	resp, err := r.chainReader.ContractState(
-		ctx, r.address, []byte(`{"latest_config_digest_and_epoch":{}}`),
-	)
+	state, err := r.readConfig(ctx, 0)
 	if err != nil {
 		return types.ConfigDigest{}, 0, err
 	}
-
-	var digest LatestConfigDigestAndEpoch
-	if err := json.Unmarshal(resp, &digest); err != nil {
-		return types.ConfigDigest{}, 0, err
-	}
-
-	return digest.ConfigDigest, digest.Epoch, nil
+	return state.LatestConfigDigest, state.Epoch, nil
 }
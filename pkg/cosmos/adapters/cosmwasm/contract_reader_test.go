@@ -4,13 +4,25 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"strconv"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	abci "github.com/cometbft/cometbft/abci/types"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
 	cosmosSDK "github.com/cosmos/cosmos-sdk/types"
 	"github.com/goplugin/plugin-libocr/offchainreporting2/types"
+
+	"github.com/goplugin/plugin-common/pkg/logger"
+	"github.com/goplugin/plugin-common/pkg/utils/tests"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client/mocks"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
 )
 
 func Test_parseAttributes(t *testing.T) {
@@ -90,6 +102,146 @@ func Test_parseAttributes(t *testing.T) {
 	}
 }
 
+func TestOCR2Reader_LatestConfig(t *testing.T) {
+	ctx := tests.Context(t)
+	address := cosmosSDK.AccAddress(bytes.Repeat([]byte{0x01}, 20))
+	other := cosmosSDK.AccAddress(bytes.Repeat([]byte{0x02}, 20))
+	codec := params.NewAddressCodec("wasm")
+	digester := NewOffchainConfigDigester("ibiza-808", address, codec)
+	digests := map[string]types.ConfigDigest{}
+	setConfig := func(contract cosmosSDK.AccAddress, configCount string) abci.Event {
+		attrs := []abci.EventAttribute{
+			{Key: "_contract_address", Value: contract.String()},
+			{Key: "config_count", Value: configCount},
+			{Key: "f", Value: "1"},
+			{Key: "offchain_config", Value: "AwQ="},
+			{Key: "offchain_config_version", Value: "1"},
+			{Key: "onchain_config", Value: "AQI="},
+			{Key: "signers", Value: "0101010101010101010101010101010101010101010101010101010101010101"},
+			{Key: "transmitters", Value: "account1"},
+		}
+		cc := types.ContractConfig{Signers: []types.OnchainPublicKey{bytes.Repeat([]byte{0x01}, 32)},
+			Transmitters: []types.Account{"account1"}, F: 1, OnchainConfig: []byte{1, 2}, OffchainConfigVersion: 1,
+			OffchainConfig: []byte{3, 4}}
+		var err error
+		cc.ConfigCount, err = strconv.ParseUint(configCount, 10, 64)
+		require.NoError(t, err)
+		digest, err := digester.ConfigDigest(ctx, cc)
+		require.NoError(t, err)
+		digests[configCount] = digest
+		attrs = append(attrs, abci.EventAttribute{Key: "latest_config_digest", Value: digest.Hex()})
+		return abci.Event{Type: "wasm-set_config", Attributes: attrs}
+	}
+
+	chainReader := mocks.NewReaderWriter(t)
+	chainReader.On("BlockResults", mock.Anything, int64(42)).Return(&coretypes.ResultBlockResults{
+		Height: 42,
+		TxsResults: []*abci.ResponseDeliverTx{
+			{Events: []abci.Event{setConfig(address, "1")}},
+			{Events: []abci.Event{setConfig(address, "2")}},
+			{Code: 5, Events: []abci.Event{setConfig(address, "3")}},
+			{Events: []abci.Event{setConfig(other, "4")}},
+		},
+	}, nil).Times(3)
+	chainReader.On("BlockResults", mock.Anything, int64(43)).Return(&coretypes.ResultBlockResults{
+		Height:     43,
+		TxsResults: []*abci.ResponseDeliverTx{{Events: []abci.Event{setConfig(other, "1")}}},
+	}, nil).Once()
+	forged := setConfig(address, "5")
+	forged.Attributes[1].Value = "6" // the digest no longer matches the config
+	chainReader.On("BlockResults", mock.Anything, int64(44)).Return(&coretypes.ResultBlockResults{
+		Height:     44,
+		TxsResults: []*abci.ResponseDeliverTx{{Events: []abci.Event{forged}}},
+	}, nil).Once()
+	chainReader.On("ContractRawState", mock.Anything, address, []byte(configKey), int64(42)).
+		Return(contractStateJSON(t, digests["2"], 0), nil).Twice()
+	chainReader.On("ContractRawState", mock.Anything, address, []byte(configKey), int64(42)).
+		Return(contractStateJSON(t, digests["1"], 0), nil).Once()
+	// the latest state has a newer config, set after block 42
+	setConfig(address, "7")
+	chainReader.On("ContractRawState", mock.Anything, address, []byte(configKey), int64(0)).
+		Return(contractStateJSON(t, digests["7"], 0), nil).Maybe()
+
+	reader := NewOCR2Reader(address, codec, chainReader, digester, logger.Test(t))
+	cc, err := reader.LatestConfig(ctx, 42)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), cc.ConfigCount)
+	require.Equal(t, digests["2"], cc.ConfigDigest)
+
+	cc, err = reader.LatestConfig(ctx, 42)
+	require.NoError(t, err, "a newer config does not fail an older one")
+	require.Equal(t, digests["2"], cc.ConfigDigest)
+
+	_, err = reader.LatestConfig(ctx, 42)
+	require.ErrorContains(t, err, "is not the config "+digests["1"].Hex()+" of the contract state in that block")
+
+	_, err = reader.LatestConfig(ctx, 43)
+	require.ErrorContains(t, err, "No set_config event found in block 43")
+
+	_, err = reader.LatestConfig(ctx, 44)
+	require.ErrorContains(t, err, "set_config event in block 44 has digest "+digests["5"].Hex())
+}
+
+func TestOCR2Reader_LatestTransmissionDetails(t *testing.T) {
+	ctx := tests.Context(t)
+	address := cosmosSDK.AccAddress(bytes.Repeat([]byte{0x01}, 20))
+	digest := mustStringToConfigDigest(t, "test config digest 32 chars long")
+
+	chainReader := mocks.NewReaderWriter(t)
+	chainReader.On("LatestBlock", mock.Anything).Return(&tmtypes.GetLatestBlockResponse{SdkBlock: &tmtypes.Block{
+		Header: tmtypes.Header{Height: 100},
+	}}, nil)
+	chainReader.On("ContractRawState", mock.Anything, address, []byte(configKey), int64(99)).
+		Return(contractStateJSON(t, digest, 7), nil)
+	chainReader.On("ContractRawState", mock.Anything, address, transmissionKey(7), int64(99)).
+		Return([]byte(`{"answer":"-1234","observations_timestamp":1700000000,"transmission_timestamp":1700000005}`), nil).Once()
+	chainReader.On("ContractRawState", mock.Anything, address, transmissionKey(7), int64(99)).
+		Return(nil, nil).Once()
+
+	reader := NewOCR2Reader(address, params.NewAddressCodec("wasm"), chainReader, nil, logger.Test(t))
+	gotDigest, epoch, round, answer, timestamp, err := reader.LatestTransmissionDetails(ctx)
+	require.NoError(t, err)
+	require.Equal(t, digest, gotDigest)
+	require.Equal(t, uint32(3), epoch)
+	require.Equal(t, uint8(2), round)
+	require.Equal(t, "-1234", answer.String())
+	require.Equal(t, time.Unix(1700000005, 0), timestamp)
+
+	gotDigest, epoch, round, answer, timestamp, err = reader.LatestTransmissionDetails(ctx)
+	require.NoError(t, err)
+	require.Equal(t, digest, gotDigest)
+	require.Equal(t, uint32(3), epoch)
+	require.Equal(t, uint8(0), round)
+	require.Equal(t, "0", answer.String())
+	require.Equal(t, time.Unix(0, 0), timestamp)
+}
+
+func TestTransmissionKey(t *testing.T) {
+	require.Equal(t, "000d7472616e736d697373696f6e7300000102", hex.EncodeToString(transmissionKey(258)))
+}
+
+// contractStateJSON returns the config of the contract as stored by it, with digest encoded as an array of bytes.
+func contractStateJSON(t *testing.T, digest types.ConfigDigest, roundID uint32) []byte {
+	b, err := json.Marshal(map[string]any{
+		"config_count":               2,
+		"latest_config_digest":       digestBytes(digest),
+		"latest_config_block_number": 42,
+		"latest_aggregator_round_id": roundID,
+		"epoch":                      3,
+		"round":                      2,
+	})
+	require.NoError(t, err)
+	return b
+}
+
+func digestBytes(digest types.ConfigDigest) []int {
+	b := make([]int, len(digest))
+	for i := range digest {
+		b[i] = int(digest[i])
+	}
+	return b
+}
+
 func mustStringToConfigDigest(t *testing.T, s string) types.ConfigDigest {
 	d, err := types.BytesToConfigDigest([]byte(s))
 	require.NoError(t, err)
@@ -11,6 +11,7 @@ import (
 
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/adapters"
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/config"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
 
 	"github.com/goplugin/plugin-libocr/offchainreporting2/chains/evmutil"
 	"github.com/goplugin/plugin-libocr/offchainreporting2/types"
@@ -26,6 +27,10 @@ type ContractTransmitter struct {
 	contract    cosmosSDK.AccAddress
 	sender      cosmosSDK.AccAddress
 	cfg         config.Config
+	// addressCodec encodes the contract and sender with the prefix of the chain.
+	addressCodec params.AddressCodec
+	// opts are the options of each transmitted msg.
+	opts []adapters.EnqueueOption
 }
 
 func NewContractTransmitter(
@@ -36,6 +41,7 @@ func NewContractTransmitter(
 	msgEnqueuer adapters.MsgEnqueuer,
 	lggr logger.Logger,
 	cfg config.Config,
+	opts ...adapters.EnqueueOption,
 ) *ContractTransmitter {
 	return &ContractTransmitter{
 		OCR2Reader:  reader,
@@ -45,6 +51,9 @@ func NewContractTransmitter(
 		sender:      sender,
 		lggr:        lggr,
 		cfg:         cfg,
+
+		addressCodec: params.NewAddressCodec(cfg.Bech32Prefix()),
+		opts:         opts,
 	}
 }
 
@@ -55,7 +64,15 @@ func (ct *ContractTransmitter) Transmit(
 	report types.Report,
 	sigs []types.AttributedOnchainSignature,
 ) error {
-	ct.lggr.Infof("[%s] Sending TX to %s", ct.jobID, ct.contract.String())
+	contract, err := ct.addressCodec.BytesToString(ct.contract)
+	if err != nil {
+		return err
+	}
+	sender, err := ct.addressCodec.BytesToString(ct.sender)
+	if err != nil {
+		return err
+	}
+	ct.lggr.Infof("[%s] Sending TX to %s", ct.jobID, contract)
 	msgStruct := TransmitMsg{}
 	reportContext := evmutil.RawReportContext(reportCtx)
 	for _, r := range reportContext {
@@ -70,15 +87,16 @@ func (ct *ContractTransmitter) Transmit(
 		return err
 	}
 	m := &wasmtypes.MsgExecuteContract{
-		Sender:   ct.sender.String(),
-		Contract: ct.contract.String(),
+		Sender:   sender,
+		Contract: contract,
 		Msg:      msgBytes,
 		Funds:    cosmosSDK.Coins{},
 	}
-	_, err = ct.msgEnqueuer.Enqueue(ctx, ct.contract.String(), m)
+	_, err = ct.msgEnqueuer.Enqueue(ctx, contract, m, ct.opts...)
 	return err
 }
 
 func (ct *ContractTransmitter) FromAccount(ctx context.Context) (types.Account, error) {
-	return types.Account(ct.sender.String()), nil
+	sender, err := ct.addressCodec.BytesToString(ct.sender)
+	return types.Account(sender), err
 }
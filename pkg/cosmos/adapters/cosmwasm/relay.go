@@ -17,6 +17,18 @@ import (
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
 )
 
+// Adapter creates the providers for the OCR2 cosmwasm contract.
+var Adapter = adapters.Adapter{
+	NewConfigProvider: func(ctx context.Context, lggr logger.Logger, chain adapters.Chain, args relaytypes.RelayArgs) (relaytypes.ConfigProvider, error) {
+		cp, err := NewConfigProvider(ctx, lggr, chain, args)
+		if err != nil {
+			return nil, err
+		}
+		return cp, nil
+	},
+	NewMedianProvider: NewMedianProvider,
+}
+
 var _ relaytypes.ConfigProvider = &configProvider{}
 
 type configProvider struct {
@@ -30,6 +42,7 @@ type configProvider struct {
 	contractCache *ContractCache
 	reader        *OCR2Reader
 	contractAddr  cosmosSDK.AccAddress
+	relayConfig   adapters.RelayConfig
 }
 
 func NewConfigProvider(ctx context.Context, lggr logger.Logger, chain adapters.Chain, args relaytypes.RelayArgs) (*configProvider, error) {
@@ -38,7 +51,8 @@ func NewConfigProvider(ctx context.Context, lggr logger.Logger, chain adapters.C
 	if err != nil {
 		return nil, err
 	}
-	contractAddr, err := cosmosSDK.AccAddressFromBech32(args.ContractID)
+	addressCodec := params.NewAddressCodec(chain.Config().Bech32Prefix())
+	contractAddr, err := addressCodec.StringToBytes(args.ContractID)
 	if err != nil {
 		return nil, err
 	}
@@ -47,10 +61,14 @@ func NewConfigProvider(ctx context.Context, lggr logger.Logger, chain adapters.C
 	if err != nil {
 		return nil, err
 	}
-	reader := NewOCR2Reader(contractAddr, chainReader, lggr)
-	contract := NewContractCache(chain.Config(), reader, lggr)
+	subscriber, err := chain.Subscriber(relayConfig.NodeName)
+	if err != nil {
+		return nil, err
+	}
+	digester := NewOffchainConfigDigester(relayConfig.ChainID, contractAddr, addressCodec)
+	reader := NewOCR2Reader(contractAddr, addressCodec, chainReader, digester, lggr)
+	contract := NewContractCache(chain.Config(), reader, subscriber, lggr)
 	tracker := NewContractTracker(chainReader, contract)
-	digester := NewOffchainConfigDigester(relayConfig.ChainID, contractAddr)
 	return &configProvider{
 		digester:      digester,
 		tracker:       tracker,
@@ -59,6 +77,7 @@ func NewConfigProvider(ctx context.Context, lggr logger.Logger, chain adapters.C
 		reader:        reader,
 		chain:         chain,
 		contractAddr:  contractAddr,
+		relayConfig:   relayConfig,
 	}, nil
 }
 
@@ -109,7 +128,11 @@ func NewMedianProvider(ctx context.Context, lggr logger.Logger, chain adapters.C
 	if err != nil {
 		return nil, err
 	}
-	senderAddr, err := cosmosSDK.AccAddressFromBech32(bech32Addr)
+	senderAddr, err := params.NewAddressCodec(configProvider.chain.Config().Bech32Prefix()).StringToBytes(bech32Addr)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := configProvider.relayConfig.TransmitOptions(rargs.ExternalJobID.String(), rargs.ContractID)
 	if err != nil {
 		return nil, err
 	}
@@ -126,6 +149,7 @@ func NewMedianProvider(ctx context.Context, lggr logger.Logger, chain adapters.C
 			configProvider.chain.TxManager(),
 			lggr,
 			configProvider.chain.Config(),
+			opts...,
 		),
 	}, nil
 }
@@ -31,3 +31,20 @@ type LatestConfigDigestAndEpoch struct {
 	ConfigDigest types.ConfigDigest `json:"config_digest"`
 	Epoch        uint32             `json:"epoch"`
 }
+
+// ContractState is the part of the config stored by the OCR2 contract which is read by the OCR2Reader.
+type ContractState struct {
+	ConfigCount             uint32             `json:"config_count"`
+	LatestConfigDigest      types.ConfigDigest `json:"latest_config_digest"`
+	LatestConfigBlockNumber uint64             `json:"latest_config_block_number"`
+	LatestAggregatorRoundID uint32             `json:"latest_aggregator_round_id"`
+	Epoch                   uint32             `json:"epoch"`
+	Round                   uint8              `json:"round"`
+}
+
+// Transmission is the answer of a round stored by the OCR2 contract.
+type Transmission struct {
+	Answer                string `json:"answer"`
+	ObservationsTimestamp uint32 `json:"observations_timestamp"`
+	TransmissionTimestamp uint32 `json:"transmission_timestamp"`
+}
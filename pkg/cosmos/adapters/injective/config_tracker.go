@@ -4,12 +4,11 @@ import (
 	"context"
 	"fmt"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
-
 	tmtypes "github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
 	"github.com/goplugin/plugin-libocr/offchainreporting2/types"
 
 	chaintypes "github.com/goplugin/plugin-cosmos/pkg/cosmos/adapters/injective/types"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
 )
 
 var _ types.ContractConfigTracker = &CosmosModuleConfigTracker{}
@@ -18,13 +17,15 @@ type CosmosModuleConfigTracker struct {
 	feedID                  string
 	injectiveClient         chaintypes.QueryClient
 	tendermintServiceClient tmtypes.ServiceClient
+	addressCodec            params.AddressCodec
 }
 
-func NewCosmosModuleConfigTracker(feedID string, queryClient chaintypes.QueryClient, serviceClient tmtypes.ServiceClient) *CosmosModuleConfigTracker {
+func NewCosmosModuleConfigTracker(feedID string, queryClient chaintypes.QueryClient, serviceClient tmtypes.ServiceClient, addressCodec params.AddressCodec) *CosmosModuleConfigTracker {
 	return &CosmosModuleConfigTracker{
 		feedID:                  feedID,
 		injectiveClient:         queryClient,
 		tendermintServiceClient: serviceClient,
+		addressCodec:            addressCodec,
 	}
 }
 
@@ -79,14 +80,19 @@ func (c *CosmosModuleConfigTracker) LatestConfig(
 
 	signers := make([]types.OnchainPublicKey, 0, len(resp.FeedConfig.Signers))
 	for _, addr := range resp.FeedConfig.Signers {
-		acc := sdk.MustAccAddressFromBech32(addr)
+		acc, err := c.addressCodec.StringToBytes(addr)
+		if err != nil {
+			return types.ContractConfig{}, fmt.Errorf("invalid signer %s: %w", addr, err)
+		}
 		signers = append(signers, types.OnchainPublicKey(acc.Bytes()))
 	}
 
 	transmitters := make([]types.Account, 0, len(resp.FeedConfig.Transmitters))
 	for _, addr := range resp.FeedConfig.Transmitters {
-		acc := sdk.MustAccAddressFromBech32(addr)
-		transmitters = append(transmitters, types.Account(acc.String()))
+		if _, err := c.addressCodec.StringToBytes(addr); err != nil {
+			return types.ContractConfig{}, fmt.Errorf("invalid transmitter %s: %w", addr, err)
+		}
+		transmitters = append(transmitters, types.Account(addr))
 	}
 
 	config := types.ContractConfig{
@@ -7,6 +7,7 @@ import (
 	"github.com/goplugin/plugin-libocr/offchainreporting2/types"
 
 	chaintypes "github.com/goplugin/plugin-cosmos/pkg/cosmos/adapters/injective/types"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
 )
 
 const ConfigDigestPrefixCosmos types.ConfigDigestPrefix = 2
@@ -14,31 +15,41 @@ const ConfigDigestPrefixCosmos types.ConfigDigestPrefix = 2
 var _ types.OffchainConfigDigester = CosmosOffchainConfigDigester{}
 
 type CosmosOffchainConfigDigester struct {
-	chainID string
-	feedID  string
+	chainID      string
+	feedID       string
+	addressCodec params.AddressCodec
 }
 
-func NewCosmosOffchainConfigDigester(chainID string, feedID string) *CosmosOffchainConfigDigester {
+func NewCosmosOffchainConfigDigester(chainID string, feedID string, addressCodec params.AddressCodec) *CosmosOffchainConfigDigester {
 	return &CosmosOffchainConfigDigester{
-		chainID: chainID,
-		feedID:  feedID,
+		chainID:      chainID,
+		feedID:       feedID,
+		addressCodec: addressCodec,
 	}
 }
 
 func (d CosmosOffchainConfigDigester) ConfigDigest(ctx context.Context, cc types.ContractConfig) (types.ConfigDigest, error) {
 	signers := make([]string, 0, len(cc.Signers))
 	for _, acc := range cc.Signers {
-		signers = append(signers, sdk.AccAddress(acc).String())
+		signer, err := d.addressCodec.BytesToString(sdk.AccAddress(acc))
+		if err != nil {
+			return types.ConfigDigest{}, err
+		}
+		signers = append(signers, signer)
 	}
 
 	transmitters := make([]string, 0, len(cc.Transmitters))
 	for _, acc := range cc.Transmitters {
-		addr, err := sdk.AccAddressFromBech32(string(acc))
+		addr, err := d.addressCodec.StringToBytes(string(acc))
 		if err != nil {
 			return types.ConfigDigest{}, err
 		}
 
-		transmitters = append(transmitters, addr.String())
+		transmitter, err := d.addressCodec.BytesToString(addr)
+		if err != nil {
+			return types.ConfigDigest{}, err
+		}
+		transmitters = append(transmitters, transmitter)
 	}
 
 	chainContractConfig := &chaintypes.ContractConfig{
@@ -3,9 +3,10 @@ package injective
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 
 	tmtypes "github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
-	cosmosSDK "github.com/cosmos/cosmos-sdk/types"
 	"github.com/goplugin/plugin-libocr/offchainreporting2/reportingplugin/median"
 	"github.com/goplugin/plugin-libocr/offchainreporting2/types"
 
@@ -17,8 +18,21 @@ import (
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/adapters/injective/medianreport"
 	injectivetypes "github.com/goplugin/plugin-cosmos/pkg/cosmos/adapters/injective/types"
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
 )
 
+// Adapter creates the providers for the injective exchange OCR module.
+var Adapter = adapters.Adapter{
+	NewConfigProvider: func(ctx context.Context, lggr logger.Logger, chain adapters.Chain, args relaytypes.RelayArgs) (relaytypes.ConfigProvider, error) {
+		cp, err := NewConfigProvider(ctx, lggr, chain, args)
+		if err != nil {
+			return nil, err
+		}
+		return cp, nil
+	},
+	NewMedianProvider: NewMedianProvider,
+}
+
 var _ relaytypes.ConfigProvider = &configProvider{}
 
 type configProvider struct {
@@ -32,6 +46,7 @@ type configProvider struct {
 	reader          client.Reader
 	injectiveClient injectivetypes.QueryClient
 	feedID          string
+	relayConfig     adapters.RelayConfig
 }
 
 func NewConfigProvider(ctx context.Context, lggr logger.Logger, chain adapters.Chain, args relaytypes.RelayArgs) (*configProvider, error) {
@@ -41,6 +56,14 @@ func NewConfigProvider(ctx context.Context, lggr logger.Logger, chain adapters.C
 		return nil, err
 	}
 	feedID := args.ContractID // TODO: probably not bech32
+	if _, ok := chain.Config().TrustOptions(); ok {
+		// module queries execute module code and have no proof
+		return nil, errors.New("verified reads are not supported by the injective-module adapter")
+	}
+	if prefix := chain.Config().Bech32Prefix(); prefix != injectivetypes.Bech32Prefix {
+		// the signers of the module msgs are decoded with the injective prefix
+		return nil, fmt.Errorf("the injective-module adapter requires Bech32Prefix %s, but the chain has %s", injectivetypes.Bech32Prefix, prefix)
+	}
 
 	// TODO: share cosmos.Client or extract the inner clientCtx
 	reader, err := chain.Reader(relayConfig.NodeName)
@@ -51,8 +74,9 @@ func NewConfigProvider(ctx context.Context, lggr logger.Logger, chain adapters.C
 	injectiveClient := injectivetypes.NewQueryClient(clientCtx)
 	tendermintServiceClient := tmtypes.NewServiceClient(clientCtx)
 
-	tracker := NewCosmosModuleConfigTracker(feedID, injectiveClient, tendermintServiceClient)
-	digester := NewCosmosOffchainConfigDigester(relayConfig.ChainID, feedID)
+	addressCodec := params.NewAddressCodec(chain.Config().Bech32Prefix())
+	tracker := NewCosmosModuleConfigTracker(feedID, injectiveClient, tendermintServiceClient, addressCodec)
+	digester := NewCosmosOffchainConfigDigester(relayConfig.ChainID, feedID, addressCodec)
 	return &configProvider{
 		// TODO:
 		digester:        digester,
@@ -62,6 +86,7 @@ func NewConfigProvider(ctx context.Context, lggr logger.Logger, chain adapters.C
 		injectiveClient: injectiveClient,
 		chain:           chain,
 		feedID:          feedID,
+		relayConfig:     relayConfig,
 	}, nil
 }
 func (c *configProvider) Name() string {
@@ -111,11 +136,16 @@ func NewMedianProvider(ctx context.Context, lggr logger.Logger, chain adapters.C
 	reportCodec := medianreport.ReportCodec{}
 	injectiveClient := configProvider.injectiveClient
 	contract := NewCosmosMedianReporter(configProvider.feedID, injectiveClient)
-	senderAddr, err := cosmosSDK.AccAddressFromBech32(pargs.TransmitterID)
+	addressCodec := params.NewAddressCodec(configProvider.chain.Config().Bech32Prefix())
+	senderAddr, err := addressCodec.StringToBytes(pargs.TransmitterID)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := configProvider.relayConfig.TransmitOptions(rargs.ExternalJobID.String(), configProvider.feedID)
 	if err != nil {
 		return nil, err
 	}
-	transmitter := NewCosmosModuleTransmitter(injectiveClient, configProvider.feedID, senderAddr, configProvider.chain.TxManager(), lggr)
+	transmitter := NewCosmosModuleTransmitter(injectiveClient, configProvider.feedID, senderAddr, addressCodec, configProvider.chain.TxManager(), lggr, opts...)
 	return &medianProvider{
 		configProvider: configProvider,
 		reportCodec:    reportCodec,
@@ -11,6 +11,7 @@ import (
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/adapters"
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/adapters/injective/medianreport"
 	chaintypes "github.com/goplugin/plugin-cosmos/pkg/cosmos/adapters/injective/types"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
 )
 
 var _ types.ContractTransmitter = &CosmosModuleTransmitter{}
@@ -21,26 +22,35 @@ type CosmosModuleTransmitter struct {
 	msgEnqueuer adapters.MsgEnqueuer
 	feedID      string
 	sender      cosmosSDK.AccAddress
+	// addressCodec encodes the sender with the prefix of the chain.
+	addressCodec params.AddressCodec
+	// opts are the options of each transmitted msg.
+	opts []adapters.EnqueueOption
 }
 
 func NewCosmosModuleTransmitter(
 	queryClient chaintypes.QueryClient,
 	feedID string,
 	sender cosmosSDK.AccAddress,
+	addressCodec params.AddressCodec,
 	msgEnqueuer adapters.MsgEnqueuer,
 	lggr logger.Logger,
+	opts ...adapters.EnqueueOption,
 ) *CosmosModuleTransmitter {
 	return &CosmosModuleTransmitter{
-		lggr:        lggr,
-		feedID:      feedID,
-		queryClient: queryClient,
-		msgEnqueuer: msgEnqueuer,
-		sender:      sender,
+		lggr:         lggr,
+		feedID:       feedID,
+		queryClient:  queryClient,
+		msgEnqueuer:  msgEnqueuer,
+		sender:       sender,
+		addressCodec: addressCodec,
+		opts:         opts,
 	}
 }
 
 func (c *CosmosModuleTransmitter) FromAccount(ctx context.Context) (types.Account, error) {
-	return types.Account(c.sender.String()), nil
+	sender, err := c.addressCodec.BytesToString(c.sender)
+	return types.Account(sender), err
 }
 
 // Transmit sends the report to the on-chain OCR2Aggregator smart contract's Transmit method
@@ -56,9 +66,13 @@ func (c *CosmosModuleTransmitter) Transmit(
 	if err != nil {
 		return err
 	}
+	sender, err := c.addressCodec.BytesToString(c.sender)
+	if err != nil {
+		return err
+	}
 
 	msgTransmit := &chaintypes.MsgTransmit{
-		Transmitter:  c.sender.String(),
+		Transmitter:  sender,
 		ConfigDigest: reportCtx.ConfigDigest[:],
 		FeedId:       c.feedID,
 		Epoch:        uint64(reportCtx.Epoch),
@@ -72,7 +86,7 @@ func (c *CosmosModuleTransmitter) Transmit(
 		msgTransmit.Signatures = append(msgTransmit.Signatures, sig.Signature)
 	}
 
-	_, err = c.msgEnqueuer.Enqueue(ctx, c.feedID, msgTransmit)
+	_, err = c.msgEnqueuer.Enqueue(ctx, c.feedID, msgTransmit, c.opts...)
 	return err
 }
 
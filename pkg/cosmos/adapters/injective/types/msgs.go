@@ -4,8 +4,26 @@ import (
 	errors "cosmossdk.io/errors"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
 )
 
+// Bech32Prefix is the prefix of Injective account addresses.
+const Bech32Prefix = "inj"
+
+// AddressCodec decodes the signers of msgs with the Injective prefix, regardless of the global sdk config,
+// which may be set up for another chain.
+var AddressCodec = params.NewAddressCodec(Bech32Prefix)
+
+// mustSigners returns the account address of signer, which must be valid.
+func mustSigners(signer string) []sdk.AccAddress {
+	addr, err := AddressCodec.StringToBytes(signer)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{addr}
+}
+
 const (
 	TypeMsgCreateFeed             = "createFeed"
 	TypeMsgUpdateFeed             = "updateFeed"
@@ -50,8 +68,7 @@ func (msg *MsgCreateFeed) GetSignBytes() []byte {
 
 // GetSigners implements the sdk.Msg interface. It defines whose signature is required
 func (msg MsgCreateFeed) GetSigners() []sdk.AccAddress {
-	sender := sdk.MustAccAddressFromBech32(msg.Sender)
-	return []sdk.AccAddress{sender}
+	return mustSigners(msg.Sender)
 }
 
 // Route implements the sdk.Msg interface. It should return the name of the module
@@ -134,8 +151,7 @@ func (msg *MsgUpdateFeed) GetSignBytes() []byte {
 
 // GetSigners implements the sdk.Msg interface. It defines whose signature is required
 func (msg MsgUpdateFeed) GetSigners() []sdk.AccAddress {
-	sender := sdk.MustAccAddressFromBech32(msg.Sender)
-	return []sdk.AccAddress{sender}
+	return mustSigners(msg.Sender)
 }
 
 // Route implements the sdk.Msg interface. It should return the name of the module
@@ -184,8 +200,7 @@ func (msg *MsgTransmit) GetSignBytes() []byte {
 
 // GetSigners implements the sdk.Msg interface. It defines whose signature is required
 func (msg MsgTransmit) GetSigners() []sdk.AccAddress {
-	transmitter := sdk.MustAccAddressFromBech32(msg.Transmitter)
-	return []sdk.AccAddress{transmitter}
+	return mustSigners(msg.Transmitter)
 }
 
 // Route implements the sdk.Msg interface. It should return the name of the module
@@ -218,8 +233,7 @@ func (msg *MsgFundFeedRewardPool) GetSignBytes() []byte {
 
 // GetSigners implements the sdk.Msg interface. It defines whose signature is required
 func (msg MsgFundFeedRewardPool) GetSigners() []sdk.AccAddress {
-	sender := sdk.MustAccAddressFromBech32(msg.Sender)
-	return []sdk.AccAddress{sender}
+	return mustSigners(msg.Sender)
 }
 
 // Route implements the sdk.Msg interface. It should return the name of the module
@@ -252,8 +266,7 @@ func (msg *MsgWithdrawFeedRewardPool) GetSignBytes() []byte {
 
 // GetSigners implements the sdk.Msg interface. It defines whose signature is required
 func (msg MsgWithdrawFeedRewardPool) GetSigners() []sdk.AccAddress {
-	sender := sdk.MustAccAddressFromBech32(msg.Sender)
-	return []sdk.AccAddress{sender}
+	return mustSigners(msg.Sender)
 }
 
 // Route implements the sdk.Msg interface. It should return the name of the module
@@ -312,8 +325,7 @@ func (msg *MsgSetPayees) GetSignBytes() []byte {
 
 // GetSigners implements the sdk.Msg interface. It defines whose signature is required
 func (msg MsgSetPayees) GetSigners() []sdk.AccAddress {
-	sender := sdk.MustAccAddressFromBech32(msg.Sender)
-	return []sdk.AccAddress{sender}
+	return mustSigners(msg.Sender)
 }
 
 // Route implements the sdk.Msg interface. It should return the name of the module
@@ -354,8 +366,7 @@ func (msg *MsgTransferPayeeship) GetSignBytes() []byte {
 
 // GetSigners implements the sdk.Msg interface. It defines whose signature is required
 func (msg MsgTransferPayeeship) GetSigners() []sdk.AccAddress {
-	sender := sdk.MustAccAddressFromBech32(msg.Sender)
-	return []sdk.AccAddress{sender}
+	return mustSigners(msg.Sender)
 }
 
 // Route implements the sdk.Msg interface. It should return the name of the module
@@ -388,6 +399,5 @@ func (msg *MsgAcceptPayeeship) GetSignBytes() []byte {
 
 // GetSigners implements the sdk.Msg interface. It defines whose signature is required
 func (msg MsgAcceptPayeeship) GetSigners() []sdk.AccAddress {
-	sender := sdk.MustAccAddressFromBech32(msg.Payee)
-	return []sdk.AccAddress{sender}
+	return mustSigners(msg.Payee)
 }
@@ -0,0 +1,95 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/goplugin/plugin-common/pkg/logger"
+	"github.com/goplugin/plugin-common/pkg/types"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/config"
+)
+
+// Names of the built-in adapters.
+const (
+	// AdapterCosmWasm is the OCR2 cosmwasm contract.
+	AdapterCosmWasm = "cosmwasm"
+	// AdapterInjectiveModule is the injective exchange OCR module.
+	AdapterInjectiveModule = "injective-module"
+)
+
+// legacyInjectivePrefix selects the injective module adapter, when no adapter is configured.
+const legacyInjectivePrefix = "inj"
+
+// Adapter creates the providers for an on-chain OCR2 implementation.
+// A nil constructor means that the adapter does not support the plugin.
+type Adapter struct {
+	NewConfigProvider func(ctx context.Context, lggr logger.Logger, chain Chain, args types.RelayArgs) (types.ConfigProvider, error)
+	NewMedianProvider func(ctx context.Context, lggr logger.Logger, chain Chain, rargs types.RelayArgs, pargs types.PluginArgs) (types.MedianProvider, error)
+}
+
+// Registry holds the adapters available to relayers, by name.
+type Registry struct {
+	mu       sync.RWMutex
+	adapters map[string]Adapter
+}
+
+func NewRegistry() *Registry {
+	return &Registry{adapters: map[string]Adapter{}}
+}
+
+// Register adds the adapter called name, which must not already be registered.
+func (r *Registry) Register(name string, adapter Adapter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.adapters[name]; ok {
+		return fmt.Errorf("adapter %s is already registered", name)
+	}
+	r.adapters[name] = adapter
+	return nil
+}
+
+// Get returns the adapter called name.
+func (r *Registry) Get(name string) (Adapter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	adapter, ok := r.adapters[name]
+	if !ok {
+		return Adapter{}, fmt.Errorf("unknown adapter %q, must be one of %v", name, r.names())
+	}
+	return adapter, nil
+}
+
+func (r *Registry) names() []string {
+	names := make([]string, 0, len(r.adapters))
+	for name := range r.adapters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ForRelay returns the adapter for a job, which is the Adapter of its RelayConfig if set, otherwise the Adapter
+// of the chain config. If neither is set, chains with the injective bech32 prefix default to the injective
+// module adapter, and others to the cosmwasm adapter.
+func (r *Registry) ForRelay(cfg config.Config, args types.RelayArgs) (string, Adapter, error) {
+	var relayConfig RelayConfig
+	if err := json.Unmarshal(args.RelayConfig, &relayConfig); err != nil {
+		return "", Adapter{}, err
+	}
+	name := relayConfig.Adapter
+	if name == "" {
+		name = cfg.Adapter()
+	}
+	if name == "" {
+		name = AdapterCosmWasm
+		if cfg.Bech32Prefix() == legacyInjectivePrefix {
+			name = AdapterInjectiveModule
+		}
+	}
+	adapter, err := r.Get(name)
+	return name, adapter, err
+}
@@ -0,0 +1,60 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goplugin/plugin-common/pkg/logger"
+	"github.com/goplugin/plugin-common/pkg/types"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/config"
+)
+
+func TestRegistry_ForRelay(t *testing.T) {
+	r := NewRegistry()
+	for _, name := range []string{AdapterCosmWasm, AdapterInjectiveModule, "custom"} {
+		require.NoError(t, r.Register(name, Adapter{
+			NewConfigProvider: func(context.Context, logger.Logger, Chain, types.RelayArgs) (types.ConfigProvider, error) {
+				return nil, nil
+			},
+		}))
+	}
+	require.ErrorContains(t, r.Register("custom", Adapter{}), "adapter custom is already registered")
+
+	newConfig := func(bech32Prefix, adapter string) *config.TOMLConfig {
+		c := &config.TOMLConfig{Chain: config.Chain{Bech32Prefix: &bech32Prefix}}
+		if adapter != "" {
+			c.Chain.Adapter = &adapter
+		}
+		c.SetDefaults()
+		return c
+	}
+	for _, tt := range []struct {
+		name        string
+		cfg         *config.TOMLConfig
+		relayConfig string
+		exp         string
+		errStr      string
+	}{
+		{name: "default", cfg: newConfig("wasm", ""), relayConfig: `{"chainID": "chain"}`, exp: AdapterCosmWasm},
+		{name: "injective prefix", cfg: newConfig("inj", ""), relayConfig: `{}`, exp: AdapterInjectiveModule},
+		{name: "chain", cfg: newConfig("inj", AdapterCosmWasm), relayConfig: `{}`, exp: AdapterCosmWasm},
+		{name: "relay config", cfg: newConfig("inj", AdapterCosmWasm), relayConfig: `{"adapter": "custom"}`, exp: "custom"},
+		{name: "unknown", cfg: newConfig("wasm", "evm"), relayConfig: `{}`,
+			errStr: `unknown adapter "evm", must be one of [cosmwasm custom injective-module]`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			name, adapter, err := r.ForRelay(tt.cfg, types.RelayArgs{RelayConfig: []byte(tt.relayConfig)})
+			if tt.errStr != "" {
+				require.ErrorContains(t, err, tt.errStr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, name)
+			assert.NotNil(t, adapter.NewConfigProvider)
+		})
+	}
+}
@@ -1,7 +1,39 @@
 package adapters
 
+import "fmt"
+
 // CL Core OCR2 job spec RelayConfig member for Cosmos
 type RelayConfig struct {
 	ChainID  string `json:"chainID"`  // required
 	NodeName string `json:"nodeName"` // optional, defaults to a random node with ChainID
+	Adapter  string `json:"adapter"`  // optional, defaults to the Adapter of the chain
+	// MemoTemplate is the memo of transmit txs, rendered with MemoData.
+	MemoTemplate string `json:"memoTemplate"` // optional, defaults to no memo
+	// SupersedePolicy determines whether a transmit cancels the pending transmits of the job.
+	SupersedePolicy SupersedePolicy `json:"supersedePolicy"` // optional, supersede or append, defaults to supersede
+	// SenderPool are keystore accounts which send transmits in turn, on behalf of the transmitter,
+	// which must grant them authz authorization to execute the contract.
+	SenderPool []string `json:"senderPool"` // optional, defaults to sending from the transmitter
+}
+
+// TransmitOptions returns the options of the msgs transmitted by a job, with its MemoTemplate rendered for jobID and feedID.
+func (c RelayConfig) TransmitOptions(jobID, feedID string) (opts []EnqueueOption, err error) {
+	if c.MemoTemplate != "" {
+		memo, err := RenderMemo(c.MemoTemplate, MemoData{JobID: jobID, FeedID: feedID, NodeName: c.NodeName})
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithMemo(memo))
+	}
+	switch c.SupersedePolicy {
+	case "":
+	case SupersedePolicySupersede, SupersedePolicyAppend:
+		opts = append(opts, WithSupersedePolicy(c.SupersedePolicy))
+	default:
+		return nil, fmt.Errorf("invalid supersede policy %q: must be %s or %s", c.SupersedePolicy, SupersedePolicySupersede, SupersedePolicyAppend)
+	}
+	if len(c.SenderPool) > 0 {
+		opts = append(opts, WithSenderPool(c.SenderPool...))
+	}
+	return opts, nil
 }
@@ -1,7 +1,12 @@
 package adapters
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
 
 	cosmosSDK "github.com/cosmos/cosmos-sdk/types"
 
@@ -37,10 +42,165 @@ func (tms Msgs) GetIDs() []int64 {
 	return ids
 }
 
+// MaxMemoLength is the maximum length of a tx memo, per the default auth params.
+const MaxMemoLength = 256
+
+// SupersedePolicy determines which Unstarted msgs of the same contract are cancelled by an enqueued msg.
+type SupersedePolicy string
+
+const (
+	// SupersedePolicySupersede cancels all Unstarted msgs of the contract. This is the default.
+	SupersedePolicySupersede SupersedePolicy = "supersede"
+	// SupersedePolicyAppend keeps all msgs.
+	SupersedePolicyAppend SupersedePolicy = "append"
+	// SupersedePolicyCoalesce cancels the Unstarted msgs of the contract with the same coalesce key.
+	SupersedePolicyCoalesce SupersedePolicy = "coalesce"
+)
+
+// EnqueueOptions are the options of an enqueued msg, which are persisted with it.
+type EnqueueOptions struct {
+	// Memo is the memo of the tx including the msg. Msgs with different memos are sent in separate txs.
+	Memo string
+	// Deadline is when the msg expires, in place of the TxMsgTimeout. Zero means unset.
+	Deadline time.Time
+	// Priority orders msgs, so that those with higher priority are sent first. Defaults to 0.
+	Priority int32
+	// IdempotencyKey identifies the msg. Enqueueing a msg with the key of a msg which has not errored
+	// returns the id of that msg instead.
+	IdempotencyKey string
+	// SupersedePolicy determines which msgs are cancelled by the msg. Defaults to SupersedePolicySupersede.
+	SupersedePolicy SupersedePolicy
+	// CoalesceKey is the key of SupersedePolicyCoalesce.
+	CoalesceKey string
+	// AllowLargeSend exempts a MsgSend from the MaxSend of its sender's tx policy.
+	AllowLargeSend bool
+	// SenderPool are keystore accounts which send the msg on behalf of its sender, via an authz MsgExec.
+	// Each must be granted authorization by the sender, and be able to pay the fee of a small batch. The msg must
+	// satisfy the TxPolicy of both the sender and the account. Empty means the sender sends the msg itself.
+	SenderPool []string
+}
+
+type EnqueueOption func(*EnqueueOptions)
+
+// WithMemo sets the memo of the tx including the msg.
+func WithMemo(memo string) EnqueueOption {
+	return func(o *EnqueueOptions) { o.Memo = memo }
+}
+
+// WithDeadline sets when the msg expires, in place of the TxMsgTimeout.
+func WithDeadline(deadline time.Time) EnqueueOption {
+	return func(o *EnqueueOptions) { o.Deadline = deadline }
+}
+
+// WithPriority sets the priority of the msg. Msgs with higher priority are sent first.
+func WithPriority(priority int32) EnqueueOption {
+	return func(o *EnqueueOptions) { o.Priority = priority }
+}
+
+// WithIdempotencyKey sets the key which identifies the msg, so that enqueueing it again has no effect.
+func WithIdempotencyKey(key string) EnqueueOption {
+	return func(o *EnqueueOptions) { o.IdempotencyKey = key }
+}
+
+// WithSupersedePolicy sets which Unstarted msgs of the same contract are cancelled by the msg.
+// Use WithCoalesceKey for SupersedePolicyCoalesce.
+func WithSupersedePolicy(policy SupersedePolicy) EnqueueOption {
+	return func(o *EnqueueOptions) { o.SupersedePolicy = policy }
+}
+
+// WithCoalesceKey sets SupersedePolicyCoalesce, so that the msg only cancels Unstarted msgs of the same contract
+// with the same key.
+func WithCoalesceKey(key string) EnqueueOption {
+	return func(o *EnqueueOptions) {
+		o.SupersedePolicy = SupersedePolicyCoalesce
+		o.CoalesceKey = key
+	}
+}
+
+// WithLargeSend exempts a MsgSend from the MaxSend of its sender's tx policy.
+func WithLargeSend() EnqueueOption {
+	return func(o *EnqueueOptions) { o.AllowLargeSend = true }
+}
+
+// WithSenderPool sets the accounts which send the msg on behalf of its sender, via an authz MsgExec.
+func WithSenderPool(senders ...string) EnqueueOption {
+	return func(o *EnqueueOptions) { o.SenderPool = senders }
+}
+
+// NewEnqueueOptions applies opts to the default options.
+func NewEnqueueOptions(opts ...EnqueueOption) EnqueueOptions {
+	o := EnqueueOptions{SupersedePolicy: SupersedePolicySupersede}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Validate returns an error if the options cannot be used in a tx.
+func (o EnqueueOptions) Validate() error {
+	if len(o.Memo) > MaxMemoLength {
+		return fmt.Errorf("memo is %d characters, which exceeds the maximum of %d", len(o.Memo), MaxMemoLength)
+	}
+	switch o.SupersedePolicy {
+	case SupersedePolicySupersede, SupersedePolicyAppend:
+		if o.CoalesceKey != "" {
+			return fmt.Errorf("coalesce key requires supersede policy %s, got %s", SupersedePolicyCoalesce, o.SupersedePolicy)
+		}
+	case SupersedePolicyCoalesce:
+		if o.CoalesceKey == "" {
+			return fmt.Errorf("supersede policy %s requires a coalesce key", SupersedePolicyCoalesce)
+		}
+	default:
+		return fmt.Errorf("unknown supersede policy %q", o.SupersedePolicy)
+	}
+	for _, s := range o.SenderPool {
+		if s == "" || strings.Contains(s, senderPoolSeparator) {
+			return fmt.Errorf("invalid sender pool account %q", s)
+		}
+	}
+	return nil
+}
+
+// senderPoolSeparator joins the accounts of a sender pool, as persisted.
+const senderPoolSeparator = ","
+
+// JoinSenderPool returns pool as persisted.
+func JoinSenderPool(pool []string) string {
+	return strings.Join(pool, senderPoolSeparator)
+}
+
+// SplitSenderPool returns the accounts of a pool persisted by JoinSenderPool.
+func SplitSenderPool(pool string) []string {
+	return strings.Split(pool, senderPoolSeparator)
+}
+
+// MemoData are the fields available to a memo template, e.g. "job {{.JobID}} feed {{.FeedID}}".
+type MemoData struct {
+	JobID    string
+	FeedID   string
+	NodeName string
+}
+
+// RenderMemo renders the text/template tmpl with data.
+func RenderMemo(tmpl string, data MemoData) (string, error) {
+	t, err := template.New("memo").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid memo template: %w", err)
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render memo template: %w", err)
+	}
+	if b.Len() > MaxMemoLength {
+		return "", fmt.Errorf("memo is %d characters, which exceeds the maximum of %d", b.Len(), MaxMemoLength)
+	}
+	return b.String(), nil
+}
+
 type MsgEnqueuer interface {
 	// Enqueue enqueues msg for broadcast and returns its id.
 	// Returns ErrMsgUnsupported for unsupported message types.
-	Enqueue(ctx context.Context, contractID string, msg cosmosSDK.Msg) (int64, error)
+	Enqueue(ctx context.Context, contractID string, msg cosmosSDK.Msg, opts ...EnqueueOption) (int64, error)
 }
 
 // TxManager manages txs composed of batches of queued messages.
@@ -0,0 +1,75 @@
+package adapters
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEnqueueOptions(t *testing.T) {
+	deadline := time.Unix(1700000000, 0)
+	o := NewEnqueueOptions(WithMemo("memo"), WithDeadline(deadline), WithPriority(2), WithIdempotencyKey("key"))
+	assert.Equal(t, EnqueueOptions{Memo: "memo", Deadline: deadline, Priority: 2, IdempotencyKey: "key", SupersedePolicy: SupersedePolicySupersede}, o)
+	require.NoError(t, o.Validate())
+
+	o = NewEnqueueOptions(WithCoalesceKey("round"))
+	assert.Equal(t, EnqueueOptions{SupersedePolicy: SupersedePolicyCoalesce, CoalesceKey: "round"}, o)
+	require.NoError(t, o.Validate())
+
+	for _, tt := range []struct {
+		name   string
+		opts   []EnqueueOption
+		errStr string
+	}{
+		{name: "memo", opts: []EnqueueOption{WithMemo(strings.Repeat("a", MaxMemoLength+1))}, errStr: "memo is 257 characters, which exceeds the maximum of 256"},
+		{name: "policy", opts: []EnqueueOption{WithSupersedePolicy("replace")}, errStr: `unknown supersede policy "replace"`},
+		{name: "coalesce key", opts: []EnqueueOption{WithSupersedePolicy(SupersedePolicyCoalesce)}, errStr: "supersede policy coalesce requires a coalesce key"},
+		{name: "sender pool", opts: []EnqueueOption{WithSenderPool("wasm1a,wasm1b")}, errStr: `invalid sender pool account "wasm1a,wasm1b"`},
+		{name: "append key", opts: []EnqueueOption{WithCoalesceKey("round"), WithSupersedePolicy(SupersedePolicyAppend)},
+			errStr: "coalesce key requires supersede policy coalesce, got append"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.EqualError(t, NewEnqueueOptions(tt.opts...).Validate(), tt.errStr)
+		})
+	}
+}
+
+func TestRelayConfig_TransmitOptions(t *testing.T) {
+	opts, err := RelayConfig{}.TransmitOptions("job", "feed")
+	require.NoError(t, err)
+	assert.Empty(t, opts)
+
+	rc := RelayConfig{NodeName: "primary", MemoTemplate: "job {{.JobID}} feed {{.FeedID}} via {{.NodeName}}"}
+	opts, err = rc.TransmitOptions("c3a7", "wasm1feed")
+	require.NoError(t, err)
+	assert.Equal(t, "job c3a7 feed wasm1feed via primary", NewEnqueueOptions(opts...).Memo)
+
+	opts, err = RelayConfig{SupersedePolicy: SupersedePolicyAppend}.TransmitOptions("job", "feed")
+	require.NoError(t, err)
+	assert.Equal(t, SupersedePolicyAppend, NewEnqueueOptions(opts...).SupersedePolicy)
+	opts, err = RelayConfig{SenderPool: []string{"wasm1a", "wasm1b"}}.TransmitOptions("job", "feed")
+	require.NoError(t, err)
+	pool := NewEnqueueOptions(opts...).SenderPool
+	assert.Equal(t, []string{"wasm1a", "wasm1b"}, pool)
+	assert.Equal(t, pool, SplitSenderPool(JoinSenderPool(pool)))
+	_, err = RelayConfig{SupersedePolicy: SupersedePolicyCoalesce}.TransmitOptions("job", "feed")
+	require.EqualError(t, err, `invalid supersede policy "coalesce": must be supersede or append`)
+
+	for _, tt := range []struct {
+		name   string
+		tmpl   string
+		errStr string
+	}{
+		{name: "parse", tmpl: "{{.JobID", errStr: "invalid memo template"},
+		{name: "field", tmpl: "{{.Contract}}", errStr: "failed to render memo template"},
+		{name: "length", tmpl: strings.Repeat("{{.FeedID}}", 300), errStr: "exceeds the maximum of 256"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RelayConfig{MemoTemplate: tt.tmpl}.TransmitOptions("job", "f")
+			require.ErrorContains(t, err, tt.errStr)
+		})
+	}
+}
@@ -2,12 +2,13 @@ package cosmos
 
 import (
 	"context"
-	"crypto/rand"
 	"errors"
 	"fmt"
 	"math/big"
+	"reflect"
+	"slices"
 	"strconv"
-	"time"
+	"sync"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	bank "github.com/cosmos/cosmos-sdk/x/bank/types"
@@ -23,26 +24,22 @@ import (
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/adapters"
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/config"
-	"github.com/goplugin/plugin-cosmos/pkg/cosmos/db"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/denom"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/txm"
 )
 
-// defaultRequestTimeout is the default Cosmos client timeout.
-// Note that while the cosmos node is processing a heavy block,
-// requests can be delayed significantly (https://github.com/tendermint/tendermint/issues/6899),
-// however there's nothing we can do but wait until the block is processed.
-// So we set a fairly high timeout here.
-// TODO(BCI-979): Remove this, or make this configurable with the updated client.
-const defaultRequestTimeout = 30 * time.Second
-
 // Chain is a wrap for easy use in other places in the core node
 type Chain = adapters.Chain
 
 // ChainOpts holds options for configuring a Chain.
 type ChainOpts struct {
-	Logger   logger.Logger
-	DS       sqlutil.DataSource
+	Logger logger.Logger
+	DS     sqlutil.DataSource
+	// KeyStore must be a txm.DigestKeystore if the KeyAlgorithm is an eth_secp256k1 algorithm.
 	KeyStore loop.Keystore
+	// Cosigner collects the signatures of the cosigners of Multisigs which are not in the KeyStore. Optional.
+	Cosigner txm.Cosigner
 }
 
 func (o *ChainOpts) Validate() (err error) {
@@ -65,10 +62,16 @@ func NewChain(cfg *config.TOMLConfig, opts ChainOpts) (adapters.Chain, error) {
 	if !cfg.IsEnabled() {
 		return nil, fmt.Errorf("cannot create new chain with ID %s, the chain is disabled", *cfg.ChainID)
 	}
+	if _, ok := opts.KeyStore.(txm.DigestKeystore); !ok && cfg.KeyAlgorithm().IsEthereum() {
+		return nil, fmt.Errorf("cannot create new chain with ID %s, the KeyStore must be a txm.DigestKeystore for %s keys", *cfg.ChainID, cfg.KeyAlgorithm())
+	}
 	c, err := newChain(*cfg.ChainID, cfg, opts.DS, opts.KeyStore, opts.Logger)
 	if err != nil {
 		return nil, err
 	}
+	if opts.Cosigner != nil {
+		c.txm.SetCosigner(opts.Cosigner)
+	}
 	return c, nil
 }
 
@@ -76,34 +79,80 @@ var _ adapters.Chain = (*chain)(nil)
 
 type chain struct {
 	services.StateMachine
-	id   string
-	cfg  *config.TOMLConfig
-	txm  *txm.Txm
-	lggr logger.Logger
+	id     string
+	cfg    *config.Reloadable
+	txm    *txm.Txm
+	denoms *denom.Registry
+	// denomsMu serializes updates of cfg and denoms, and guards bankMetadata.
+	denomsMu sync.Mutex
+	// bankMetadata is the bank metadata of the fee tokens, loaded at startup.
+	bankMetadata []bank.Metadata
+	// broadcastClientsMu guards broadcastClients and broadcastCfg.
+	broadcastClientsMu sync.Mutex
+	// broadcastClients are the clients of getBroadcastClients, created from broadcastCfg.
+	broadcastClients map[string]client.Writer
+	broadcastCfg     *config.TOMLConfig
+	// verifier verifies the contract state read by clients, if VerifiedReads are enabled.
+	verifier *lightVerifier
+	lggr     logger.Logger
 }
 
 func newChain(id string, cfg *config.TOMLConfig, ds sqlutil.DataSource, ks loop.Keystore, lggr logger.Logger) (*chain, error) {
 	lggr = logger.With(lggr, "cosmosChainID", id)
 	var ch = chain{
-		id:   id,
-		cfg:  cfg,
-		lggr: logger.Named(lggr, "Chain"),
+		id:     id,
+		cfg:    config.NewReloadable(cfg),
+		denoms: denom.NewRegistry(),
+		lggr:   logger.Named(lggr, "Chain"),
+	}
+	ch.verifier = &lightVerifier{chainID: id, cfg: ch.cfg}
+	denoms, err := ch.buildDenoms(cfg)
+	if err != nil {
+		return nil, err
 	}
+	ch.denoms.Replace(denoms)
 	tc := func() (client.ReaderWriter, error) {
 		return ch.getClient("")
 	}
+	nodeGpe := client.NewCachingGasPriceEstimator(client.NewNodeGasPriceEstimator(func() (client.Reader, error) {
+		return ch.getClient("")
+	}, client.DefaultTimeout), lggr)
 	gpe := client.NewMustGasPriceEstimator([]client.GasPricesEstimator{
 		client.NewClosureGasPriceEstimator(func() (map[string]sdk.DecCoin, error) {
-			return map[string]sdk.DecCoin{
-				cfg.GasToken(): sdk.NewDecCoinFromDec(cfg.GasToken(), cfg.FallbackGasPrice()),
-			}, nil
+			return feeTokenGasPrices(ch.cfg.FeeTokens(), nodeGpe, lggr), nil
 		}),
 	}, lggr)
-	ch.txm = txm.NewTxm(ds, tc, *gpe, ch.id, cfg, ks, lggr)
+	ch.txm = txm.NewTxm(ds, tc, *gpe, ch.id, ch.cfg, ks, lggr)
+	ch.txm.SetBroadcastClients(ch.getBroadcastClients)
+	ch.txm.SetDenoms(ch.denoms)
 
 	return &ch, nil
 }
 
+// feeTokenGasPrices returns the gas price of each of tokens, which is the price from node for tokens with
+// PriceSourceNode, if it has one, otherwise the FallbackGasPrice.
+func feeTokenGasPrices(tokens []config.FeeToken, node client.GasPricesEstimator, lggr logger.Logger) map[string]sdk.DecCoin {
+	prices := make(map[string]sdk.DecCoin, len(tokens))
+	var nodePrices map[string]sdk.DecCoin
+	for _, t := range tokens {
+		price := t.FallbackGasPrice
+		if t.PriceSource == config.PriceSourceNode {
+			if nodePrices == nil {
+				var err error
+				if nodePrices, err = node.GasPrices(); err != nil {
+					lggr.Warnw("Failed to get gas prices from node, using fallback gas prices", "err", err)
+					nodePrices = map[string]sdk.DecCoin{}
+				}
+			}
+			if p, ok := nodePrices[t.Denom]; ok {
+				price = p.Amount
+			}
+		}
+		prices[t.Denom] = sdk.NewDecCoinFromDec(t.Denom, price)
+	}
+	return prices
+}
+
 func (c *chain) Name() string {
 	return c.lggr.Name()
 }
@@ -120,6 +169,102 @@ func (c *chain) Config() config.Config {
 	return c.cfg
 }
 
+// ReloadConfig replaces the chain config with cfg. See config.Reloadable.Reload.
+// The Denoms of cfg are validated first, so that an invalid config is not applied.
+func (c *chain) ReloadConfig(cfg *config.TOMLConfig) error {
+	c.denomsMu.Lock()
+	defer c.denomsMu.Unlock()
+	candidate := &config.TOMLConfig{}
+	candidate.SetFrom(cfg)
+	candidate.SetDefaults()
+	denoms, err := c.buildDenoms(candidate)
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	prev := c.cfg.Get()
+	next, err := c.cfg.Reload(cfg)
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	c.denoms.Replace(denoms)
+	if !reflect.DeepEqual(prev.VerifiedReads, next.VerifiedReads) || !reflect.DeepEqual(prev.Nodes, next.Nodes) {
+		c.verifier.reset()
+	}
+	c.lggr.Infow("Reloaded config", "nodes", len(next.Nodes))
+	return nil
+}
+
+// Denoms returns the units of the denominations of the chain, from the config and from the bank metadata
+// loaded at startup.
+func (c *chain) Denoms() *denom.Registry {
+	return c.denoms
+}
+
+// buildDenoms returns a Registry of the Denoms of cfg, and of the bank metadata loaded at startup for
+// denominations which are not configured. If the units of a fee token are not known, it is registered on its
+// own. c.denomsMu must be held, except by newChain.
+func (c *chain) buildDenoms(cfg *config.TOMLConfig) (*denom.Registry, error) {
+	denoms := denom.NewRegistry()
+	for i, d := range cfg.Denoms {
+		if err := denoms.Register(d.Metadata()); err != nil {
+			return nil, fmt.Errorf("invalid Denoms.%d: %w", i, err)
+		}
+	}
+	for _, md := range c.bankMetadata {
+		if _, ok := denoms.Unit(md.Base); ok {
+			continue // configured
+		}
+		if err := denoms.Register(md); err != nil {
+			c.lggr.Warnw("Bank metadata conflicts with Denoms, ignoring it", "base", md.Base, "err", err)
+		}
+	}
+	for _, t := range cfg.FeeTokens() {
+		if _, ok := denoms.Unit(t.Denom); !ok {
+			if err := denoms.Register(bank.Metadata{Base: t.Denom}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return denoms, nil
+}
+
+// loadDenomMetadata loads the bank metadata of the fee tokens which are not configured in Denoms.
+// The chain can still be used without it, so failures are only logged.
+func (c *chain) loadDenomMetadata(ctx context.Context) {
+	cfg := c.cfg.Get()
+	var feeDenoms []string
+	for _, t := range cfg.FeeTokens() {
+		if !slices.ContainsFunc(cfg.Denoms, func(d *config.Denom) bool { return *d.Base == t.Denom || (d.Display != nil && *d.Display == t.Denom) }) {
+			feeDenoms = append(feeDenoms, t.Denom)
+		}
+	}
+	if len(feeDenoms) == 0 {
+		return
+	}
+	reader, err := c.getClient("")
+	if err != nil {
+		c.lggr.Warnw("Failed to load denom metadata", "err", err)
+		return
+	}
+	loaded, err := denom.NewRegistry().LoadMetadata(ctx, reader, feeDenoms...)
+	if err != nil {
+		c.lggr.Warnw("Failed to load denom metadata, amounts are displayed in base units", "err", err)
+	}
+	if len(loaded) == 0 {
+		return
+	}
+	c.denomsMu.Lock()
+	defer c.denomsMu.Unlock()
+	c.bankMetadata = loaded
+	denoms, err := c.buildDenoms(c.cfg.Get())
+	if err != nil {
+		// Should be impossible, since the config was already registered
+		c.lggr.Errorw("Failed to register denom metadata", "err", err)
+		return
+	}
+	c.denoms.Replace(denoms)
+}
+
 func (c *chain) TxManager() adapters.TxManager {
 	return c.txm
 }
@@ -128,37 +273,68 @@ func (c *chain) Reader(name string) (client.Reader, error) {
 	return c.getClient(name)
 }
 
-// getClient returns a client, optionally requiring a specific node by name.
-func (c *chain) getClient(name string) (client.ReaderWriter, error) {
-	var node db.Node
-	if name == "" { // Any node
-		nodes, err := c.cfg.ListNodes()
+func (c *chain) Subscriber(name string) (client.Subscriber, error) {
+	return c.getClient(name)
+}
+
+// getBroadcastClients returns a client for each node, including sendonly nodes, keyed by name.
+// The clients are reused until the config is reloaded.
+func (c *chain) getBroadcastClients() (map[string]client.Writer, error) {
+	c.broadcastClientsMu.Lock()
+	defer c.broadcastClientsMu.Unlock()
+	cfg := c.cfg.Get()
+	if c.broadcastCfg == cfg {
+		return c.broadcastClients, nil
+	}
+	clients := make(map[string]client.Writer, len(cfg.Nodes))
+	for _, n := range cfg.Nodes {
+		cl, err := c.getClientFor(cfg, *n.Name)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list nodes: %w", err)
+			return nil, err
 		}
-		if len(nodes) == 0 {
-			return nil, errors.New("no nodes available")
-		}
-		nodeIndex, err := rand.Int(rand.Reader, big.NewInt(int64(len(nodes))))
+		clients[*n.Name] = cl
+	}
+	c.broadcastClients, c.broadcastCfg = clients, cfg
+	return clients, nil
+}
+
+// getClient returns a client, optionally requiring a specific node by name.
+func (c *chain) getClient(name string) (*client.Client, error) {
+	return c.getClientFor(c.cfg.Get(), name)
+}
+
+// getClientFor is like getClient, for the nodes of cfg.
+func (c *chain) getClientFor(cfg *config.TOMLConfig, name string) (*client.Client, error) {
+	var node *config.Node
+	if name == "" { // Any primary node
+		var err error
+		node, err = cfg.PickNode()
 		if err != nil {
-			return nil, fmt.Errorf("could not generate a random node index: %w", err)
+			return nil, err
 		}
-		node = nodes[nodeIndex.Int64()]
 	} else { // Named node
 		var err error
-		node, err = c.cfg.GetNode(name)
+		node, err = cfg.GetNodeConfig(name)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get node named %s: %w", name, err)
 		}
-		if node.CosmosChainID != c.id {
-			return nil, fmt.Errorf("failed to create client for chain %s with node %s: wrong chain id %s", c.id, name, node.CosmosChainID)
-		}
 	}
-	client, err := client.NewClient(c.id, node.TendermintURL, defaultRequestTimeout, logger.Named(c.lggr, "Client."+name))
+	httpOpts, err := node.HTTPOptions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure node %s: %w", *node.Name, err)
+	}
+	tendermintURL := node.TendermintURL.String()
+	client, err := client.NewClientWithHTTPOptions(c.id, tendermintURL, node.GetRequestTimeout(), httpOpts, logger.Named(c.lggr, "Client."+name))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create client: %w", err)
 	}
-	c.lggr.Debugw("Created client", "name", node.Name, "tendermint-url", node.TendermintURL)
+	client.SetAddressCodec(params.NewAddressCodec(cfg.Bech32Prefix()))
+	client.SetSimulationPubKey(cfg.KeyAlgorithm().PubKey(nil))
+	client.SetSignMode(cfg.SignMode().Proto())
+	if _, ok := cfg.TrustOptions(); ok {
+		client.EnableVerifiedReads(c.verifier)
+	}
+	c.lggr.Debugw("Created client", "name", *node.Name, "tendermint-url", tendermintURL)
 	return client, nil
 }
 
@@ -166,10 +342,63 @@ func (c *chain) getClient(name string) (client.ReaderWriter, error) {
 func (c *chain) Start(ctx context.Context) error {
 	return c.StartOnce("Chain", func() error {
 		c.lggr.Debug("Starting")
+		if err := c.detectParams(ctx); err != nil {
+			return err
+		}
+		c.loadDenomMetadata(ctx)
 		return c.txm.Start(ctx)
 	})
 }
 
+// detectBlocks is the number of recent blocks over which the block rate is measured.
+const detectBlocks = 20
+
+// detectParams checks the config against the chain params reported by a node, according to the DetectMode.
+func (c *chain) detectParams(ctx context.Context) error {
+	cfg := c.cfg.Get()
+	mode := *cfg.Chain.DetectMode
+	if mode == config.DetectModeOff {
+		return nil
+	}
+	reader, err := c.getClient("")
+	if err != nil {
+		return c.detectFailed(mode, err)
+	}
+	p, err := client.DetectChainParams(ctx, reader, detectBlocks)
+	if err != nil {
+		return c.detectFailed(mode, err)
+	}
+	c.lggr.Infow("Detected chain params", "params", p)
+
+	next, err := cfg.ReconcileChainParams(p)
+	if next == nil {
+		return fmt.Errorf("config disagrees with node: %w", err)
+	}
+	if *cfg.Chain.DetectAutoFill {
+		if rerr := c.ReloadConfig(next); rerr != nil {
+			return fmt.Errorf("failed to fill config from detected chain params: %w", rerr)
+		}
+		if prefix := c.cfg.Bech32Prefix(); prefix != cfg.Bech32Prefix() {
+			c.txm.SetAddressCodec(params.NewAddressCodec(prefix))
+		}
+	}
+	if err != nil {
+		if mode == config.DetectModeRefuse {
+			return fmt.Errorf("config disagrees with node: %w", err)
+		}
+		c.lggr.Warnw("Config disagrees with node", "err", err)
+	}
+	return nil
+}
+
+func (c *chain) detectFailed(mode config.DetectMode, err error) error {
+	if mode == config.DetectModeRefuse {
+		return fmt.Errorf("failed to detect chain params: %w", err)
+	}
+	c.lggr.Warnw("Failed to detect chain params", "err", err)
+	return nil
+}
+
 func (c *chain) Close() error {
 	return c.StopOnce("Chain", func() error {
 		c.lggr.Debug("Stopping")
@@ -210,13 +439,14 @@ func (c *chain) LatestHead(ctx context.Context) (types.Head, error) {
 
 // ChainService interface
 func (c *chain) GetChainStatus(ctx context.Context) (types.ChainStatus, error) {
-	toml, err := c.cfg.TOMLString()
+	cfg := c.cfg.Get()
+	toml, err := cfg.TOMLString()
 	if err != nil {
 		return types.ChainStatus{}, err
 	}
 	return types.ChainStatus{
 		ID:      c.id,
-		Enabled: *c.cfg.Enabled,
+		Enabled: cfg.IsEnabled(),
 		Config:  toml,
 	}, nil
 }
@@ -226,13 +456,13 @@ func (c *chain) ListNodeStatuses(ctx context.Context, pageSize int32, pageToken
 }
 
 func (c *chain) Transact(ctx context.Context, from, to string, amount *big.Int, balanceCheck bool) error {
-	fromAcc, err := sdk.AccAddressFromBech32(from)
+	addressCodec := params.NewAddressCodec(c.Config().Bech32Prefix())
+	fromAcc, err := addressCodec.StringToBytes(from)
 	if err != nil {
-		return fmt.Errorf("failed to parse from account: %s", fromAcc)
+		return fmt.Errorf("failed to parse from account %s: %w", from, err)
 	}
-	toAcc, err := sdk.AccAddressFromBech32(to)
-	if err != nil {
-		return fmt.Errorf("failed to parse from account: %s", toAcc)
+	if _, err = addressCodec.StringToBytes(to); err != nil {
+		return fmt.Errorf("failed to parse to account %s: %w", to, err)
 	}
 	coin := sdk.Coin{Amount: sdk.NewIntFromBigInt(amount), Denom: c.Config().GasToken()}
 
@@ -249,14 +479,16 @@ func (c *chain) Transact(ctx context.Context, from, to string, amount *big.Int,
 			return fmt.Errorf("gas price unavailable: %v", err2)
 		}
 
-		err = validateBalance(ctx, reader, gasPrice, fromAcc, coin)
+		err = validateBalance(ctx, reader, c.denoms, gasPrice, fromAcc, coin)
 		if err != nil {
 			return fmt.Errorf("failed to validate balance: %v", err)
 		}
 	}
 
-	sendMsg := bank.NewMsgSend(fromAcc, toAcc, sdk.Coins{coin})
-	_, err = txm.Enqueue(ctx, "", sendMsg)
+	// bank.NewMsgSend would encode the addresses with the global sdk prefix
+	sendMsg := &bank.MsgSend{FromAddress: from, ToAddress: to, Amount: sdk.Coins{coin}}
+	// Transfers are independent, so must not cancel each other.
+	_, err = txm.Enqueue(ctx, "", sendMsg, adapters.WithSupersedePolicy(adapters.SupersedePolicyAppend))
 	if err != nil {
 		return fmt.Errorf("failed to enqueue tx: %w", err)
 	}
@@ -266,14 +498,15 @@ func (c *chain) Transact(ctx context.Context, from, to string, amount *big.Int,
 // TODO BCF-2602 statuses are static for non-evm chain and should be dynamic
 func (c *chain) listNodeStatuses(start, end int) ([]types.NodeStatus, int, error) {
 	stats := make([]types.NodeStatus, 0)
-	total := len(c.cfg.Nodes)
+	cfg := c.cfg.Get()
+	total := len(cfg.Nodes)
 	if start >= total {
 		return stats, total, chains.ErrOutOfRange
 	}
 	if end > total {
 		end = total
 	}
-	nodes := c.cfg.Nodes[start:end]
+	nodes := cfg.Nodes[start:end]
 	for _, node := range nodes {
 		stat, err := nodeStatus(node, c.ChainID())
 		if err != nil {
@@ -288,7 +521,7 @@ func nodeStatus(n *config.Node, id string) (types.NodeStatus, error) {
 	var s types.NodeStatus
 	s.ChainID = id
 	s.Name = *n.Name
-	b, err := toml.Marshal(n)
+	b, err := toml.Marshal(n.Redacted())
 	if err != nil {
 		return types.NodeStatus{}, err
 	}
@@ -300,7 +533,8 @@ func nodeStatus(n *config.Node, id string) (types.NodeStatus, error) {
 const maxGasUsedTransfer = 100_000
 
 // validateBalance validates that fromAddr's balance can cover coin, including fees at gasPrice.
-func validateBalance(ctx context.Context, reader client.Reader, gasPrice sdk.DecCoin, fromAddr sdk.AccAddress, coin sdk.Coin) error {
+// Amounts in the error are formatted in the display units of denoms.
+func validateBalance(ctx context.Context, reader client.Reader, denoms *denom.Registry, gasPrice sdk.DecCoin, fromAddr sdk.AccAddress, coin sdk.Coin) error {
 	balance, err := reader.Balance(ctx, fromAddr, coin.GetDenom())
 	if err != nil {
 		return err
@@ -310,7 +544,8 @@ func validateBalance(ctx context.Context, reader client.Reader, gasPrice sdk.Dec
 	need := coin.Amount.Add(fee)
 
 	if balance.Amount.LT(need) {
-		return fmt.Errorf("balance %q is too low for this transaction to be executed: need %s total, including %s fee", balance, need, fee)
+		return fmt.Errorf("balance %s is too low for this transaction to be executed: need %s total, including %s fee",
+			denoms.FormatCoin(*balance), denoms.FormatCoin(sdk.NewCoin(coin.Denom, need)), denoms.FormatCoin(sdk.NewCoin(coin.Denom, fee)))
 	}
 	return nil
 }
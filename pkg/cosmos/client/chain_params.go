@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	nodetypes "github.com/cosmos/cosmos-sdk/client/grpc/node"
+	tmtypes "github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
+)
+
+// NodeInfo returns the node info.
+func (c *Client) NodeInfo(ctx context.Context) (*tmtypes.GetNodeInfoResponse, error) {
+	return c.tendermintServiceClient.GetNodeInfo(ctx, &tmtypes.GetNodeInfoRequest{})
+}
+
+// Bech32Prefix returns the bech32 prefix of account addresses.
+func (c *Client) Bech32Prefix(ctx context.Context) (string, error) {
+	r, err := c.authClient.Bech32Prefix(ctx, &authtypes.Bech32PrefixRequest{})
+	if err != nil {
+		return "", err
+	}
+	return r.Bech32Prefix, nil
+}
+
+// BondDenom returns the staking denom.
+func (c *Client) BondDenom(ctx context.Context) (string, error) {
+	r, err := c.stakingClient.Params(ctx, &stakingtypes.QueryParamsRequest{})
+	if err != nil {
+		return "", err
+	}
+	return r.Params.BondDenom, nil
+}
+
+// MinimumGasPrices returns the minimum gas prices accepted by the node.
+func (c *Client) MinimumGasPrices(ctx context.Context) (sdk.DecCoins, error) {
+	r, err := c.nodeClient.Config(ctx, &nodetypes.ConfigRequest{})
+	if err != nil {
+		return nil, err
+	}
+	if r.MinimumGasPrice == "" {
+		return nil, nil
+	}
+	return sdk.ParseDecCoins(r.MinimumGasPrice)
+}
+
+// DetectChainParams reads the chain params from reader, measuring the block rate over the latest blocks.
+func DetectChainParams(ctx context.Context, reader Reader, blocks int64) (p params.ChainParams, err error) {
+	info, err := reader.NodeInfo(ctx)
+	if err != nil {
+		return p, fmt.Errorf("failed to get node info: %w", err)
+	}
+	if info.DefaultNodeInfo == nil {
+		return p, errors.New("node info is missing")
+	}
+	p.ChainID = info.DefaultNodeInfo.Network
+
+	if p.Bech32Prefix, err = reader.Bech32Prefix(ctx); err != nil {
+		return p, fmt.Errorf("failed to get bech32 prefix: %w", err)
+	}
+	if p.BondDenom, err = reader.BondDenom(ctx); err != nil {
+		return p, fmt.Errorf("failed to get bond denom: %w", err)
+	}
+	if p.MinGasPrices, err = reader.MinimumGasPrices(ctx); err != nil {
+		return p, fmt.Errorf("failed to get minimum gas prices: %w", err)
+	}
+
+	latest, err := reader.LatestBlock(ctx)
+	if err != nil {
+		return p, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	if latest.SdkBlock == nil {
+		return p, errors.New("latest block is missing")
+	}
+	latestHeader := latest.SdkBlock.Header
+	from := max(latestHeader.Height-blocks, 1)
+	if from == latestHeader.Height {
+		return p, fmt.Errorf("not enough blocks to measure the block rate at height %d", latestHeader.Height)
+	}
+	earlier, err := reader.BlockByHeight(ctx, from)
+	if err != nil {
+		return p, fmt.Errorf("failed to get block %d: %w", from, err)
+	}
+	if earlier.SdkBlock == nil {
+		return p, fmt.Errorf("block %d is missing", from)
+	}
+	elapsed := latestHeader.Time.Sub(earlier.SdkBlock.Header.Time)
+	p.BlockRate = elapsed / time.Duration(latestHeader.Height-from)
+	return p, nil
+}
+
+// sdkVersionRe matches the major and minor version of the cosmos-sdk reported by a node, e.g. v0.53.0 or v0.50.9-inj.
+var sdkVersionRe = regexp.MustCompile(`^v?(\d+)\.(\d+)\.`)
+
+// SupportsUnorderedTxs returns whether the node runs a version of the cosmos-sdk which accepts unordered txs,
+// which is v0.53 or later.
+func SupportsUnorderedTxs(ctx context.Context, reader Reader) (bool, error) {
+	info, err := reader.NodeInfo(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get node info: %w", err)
+	}
+	if info.ApplicationVersion == nil {
+		return false, errors.New("application version is missing")
+	}
+	v := info.ApplicationVersion.CosmosSdkVersion
+	m := sdkVersionRe.FindStringSubmatch(v)
+	if m == nil {
+		return false, fmt.Errorf("invalid cosmos-sdk version %q", v)
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	return major > 0 || minor >= 53, nil
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	p2p "github.com/cometbft/cometbft/proto/tendermint/p2p"
+	tmtypes "github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
+)
+
+// paramsReader serves chain params, and blocks produced every blockRate up to latest.
+type paramsReader struct {
+	Reader
+	latest    int64
+	blockRate time.Duration
+}
+
+func (r *paramsReader) NodeInfo(context.Context) (*tmtypes.GetNodeInfoResponse, error) {
+	return &tmtypes.GetNodeInfoResponse{DefaultNodeInfo: &p2p.DefaultNodeInfo{Network: "osmosis-1"}}, nil
+}
+
+func (r *paramsReader) Bech32Prefix(context.Context) (string, error) { return "osmo", nil }
+
+func (r *paramsReader) BondDenom(context.Context) (string, error) { return "uosmo", nil }
+
+func (r *paramsReader) MinimumGasPrices(context.Context) (sdk.DecCoins, error) {
+	return sdk.ParseDecCoins("0.0025uosmo")
+}
+
+func (r *paramsReader) block(height int64) *tmtypes.Block {
+	return &tmtypes.Block{Header: tmtypes.Header{Height: height, Time: time.Unix(0, 0).Add(time.Duration(height) * r.blockRate)}}
+}
+
+func (r *paramsReader) LatestBlock(context.Context) (*tmtypes.GetLatestBlockResponse, error) {
+	return &tmtypes.GetLatestBlockResponse{SdkBlock: r.block(r.latest)}, nil
+}
+
+func (r *paramsReader) BlockByHeight(_ context.Context, height int64) (*tmtypes.GetBlockByHeightResponse, error) {
+	if height < 1 || height > r.latest {
+		return nil, fmt.Errorf("no block at height %d", height)
+	}
+	return &tmtypes.GetBlockByHeightResponse{SdkBlock: r.block(height)}, nil
+}
+
+func TestDetectChainParams(t *testing.T) {
+	ctx := context.Background()
+	p, err := DetectChainParams(ctx, &paramsReader{latest: 1000, blockRate: 5500 * time.Millisecond}, 20)
+	require.NoError(t, err)
+	assert.Equal(t, params.ChainParams{
+		ChainID:      "osmosis-1",
+		Bech32Prefix: "osmo",
+		BondDenom:    "uosmo",
+		MinGasPrices: sdk.NewDecCoins(sdk.NewDecCoinFromDec("uosmo", sdk.MustNewDecFromStr("0.0025"))),
+		BlockRate:    5500 * time.Millisecond,
+	}, p)
+
+	t.Run("young chain", func(t *testing.T) {
+		p, err := DetectChainParams(ctx, &paramsReader{latest: 5, blockRate: time.Second}, 20)
+		require.NoError(t, err)
+		assert.Equal(t, time.Second, p.BlockRate)
+	})
+
+	t.Run("genesis", func(t *testing.T) {
+		_, err := DetectChainParams(ctx, &paramsReader{latest: 1, blockRate: time.Second}, 20)
+		require.ErrorContains(t, err, "not enough blocks to measure the block rate at height 1")
+	})
+}
+
+// versionReader reports the cosmos-sdk version of a node.
+type versionReader struct {
+	Reader
+	version string
+}
+
+func (r *versionReader) NodeInfo(context.Context) (*tmtypes.GetNodeInfoResponse, error) {
+	return &tmtypes.GetNodeInfoResponse{ApplicationVersion: &tmtypes.VersionInfo{CosmosSdkVersion: r.version}}, nil
+}
+
+func TestSupportsUnorderedTxs(t *testing.T) {
+	ctx := context.Background()
+	for version, supported := range map[string]bool{
+		"v0.47.11":       false,
+		"v0.50.13-inj.1": false,
+		"v0.53.0":        true,
+		"0.53.4":         true,
+		"v0.54.0-rc.1":   true,
+		"v1.0.0":         true,
+	} {
+		got, err := SupportsUnorderedTxs(ctx, &versionReader{version: version})
+		require.NoError(t, err, version)
+		assert.Equal(t, supported, got, version)
+	}
+
+	_, err := SupportsUnorderedTxs(ctx, &versionReader{version: "(devel)"})
+	require.EqualError(t, err, `invalid cosmos-sdk version "(devel)"`)
+}
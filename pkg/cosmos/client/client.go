@@ -2,9 +2,11 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"regexp"
+	"slices"
 	"strconv"
 	"time"
 
@@ -16,18 +18,25 @@ import (
 
 	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
 	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
 	libclient "github.com/cometbft/cometbft/rpc/jsonrpc/client"
 	cosmosclient "github.com/cosmos/cosmos-sdk/client"
+	nodetypes "github.com/cosmos/cosmos-sdk/client/grpc/node"
 	tmtypes "github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
 	"github.com/cosmos/cosmos-sdk/client/tx"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
 	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"google.golang.org/grpc/metadata"
 )
 
 //go:generate mockery --name ReaderWriter --output ./mocks/
@@ -39,12 +48,35 @@ type ReaderWriter interface {
 // Reader provides methods for reading from a cosmos chain.
 type Reader interface {
 	Account(ctx context.Context, address sdk.AccAddress) (uint64, uint64, error)
+	// AccountAtHeight is like Account, but reads the state as of the given block height.
+	AccountAtHeight(ctx context.Context, address sdk.AccAddress, height int64) (uint64, uint64, error)
 	ContractState(ctx context.Context, contractAddress sdk.AccAddress, queryMsg []byte) ([]byte, error)
+	// ContractStateAtHeight is like ContractState, but reads the state as of the given block height.
+	ContractStateAtHeight(ctx context.Context, contractAddress sdk.AccAddress, queryMsg []byte, height int64) ([]byte, error)
+	// ContractRawState reads the value stored under key by a contract as of the given block height, or the latest if 0.
+	ContractRawState(ctx context.Context, contractAddress sdk.AccAddress, key []byte, height int64) ([]byte, error)
 	TxsEvents(ctx context.Context, events []string, paginationParams *query.PageRequest) (*txtypes.GetTxsEventResponse, error)
 	Tx(ctx context.Context, hash string) (*txtypes.GetTxResponse, error)
 	LatestBlock(context.Context) (*tmtypes.GetLatestBlockResponse, error)
 	BlockByHeight(ctx context.Context, height int64) (*tmtypes.GetBlockByHeightResponse, error)
+	// BlockResults returns the result of each tx in the block at height, along with the begin and end block events.
+	// Unlike TxsEvents, it does not require the node to index txs.
+	BlockResults(ctx context.Context, height int64) (*coretypes.ResultBlockResults, error)
+	// BlockSearch returns the blocks whose begin or end block events match query.
+	BlockSearch(ctx context.Context, query string, page, perPage int, orderBy string) (*coretypes.ResultBlockSearch, error)
 	Balance(ctx context.Context, addr sdk.AccAddress, denom string) (*sdk.Coin, error)
+	// BalanceAtHeight is like Balance, but reads the state as of the given block height.
+	BalanceAtHeight(ctx context.Context, addr sdk.AccAddress, denom string, height int64) (*sdk.Coin, error)
+	// DenomMetadata returns the bank metadata of denom, which describes its units.
+	DenomMetadata(ctx context.Context, denom string) (*banktypes.Metadata, error)
+	// NodeInfo returns the node info, including the chain ID in DefaultNodeInfo.Network.
+	NodeInfo(ctx context.Context) (*tmtypes.GetNodeInfoResponse, error)
+	// Bech32Prefix returns the bech32 prefix of account addresses.
+	Bech32Prefix(ctx context.Context) (string, error)
+	// BondDenom returns the staking denom.
+	BondDenom(ctx context.Context) (string, error)
+	// MinimumGasPrices returns the minimum gas prices accepted by the node, which are empty if any price is accepted.
+	MinimumGasPrices(ctx context.Context) (sdk.DecCoins, error)
 	// TODO: escape hatch for injective client
 	Context() *cosmosclient.Context
 }
@@ -59,7 +91,24 @@ type Writer interface {
 	Simulate(ctx context.Context, txBytes []byte) (*txtypes.SimulateResponse, error)
 	BatchSimulateUnsigned(ctx context.Context, msgs SimMsgs, sequence uint64) (*BatchSimResults, error)
 	SimulateUnsigned(ctx context.Context, msgs []sdk.Msg, sequence uint64) (*txtypes.SimulateResponse, error)
-	CreateAndSign(msgs []sdk.Msg, account uint64, sequence uint64, gasLimit uint64, gasLimitMultiplier float64, gasPrice sdk.DecCoin, signer cryptotypes.PrivKey, timeoutHeight uint64) ([]byte, error)
+	CreateAndSign(msgs []sdk.Msg, account uint64, sequence uint64, gasLimit uint64, gasLimitMultiplier float64, gasPrice sdk.DecCoin, signer cryptotypes.PrivKey, timeoutHeight uint64, memo string) ([]byte, error)
+	// CreateAndSignMultisig is like CreateAndSign, but signs for a multisig account.
+	CreateAndSignMultisig(msgs []sdk.Msg, account uint64, sequence uint64, gasLimit uint64, gasLimitMultiplier float64, gasPrice sdk.DecCoin, signer MultisigSigner, timeoutHeight uint64, memo string) ([]byte, error)
+	// BatchSimulateUnordered is like BatchSimulateUnsigned, but simulates unordered txs.
+	BatchSimulateUnordered(ctx context.Context, msgs SimMsgs, timeoutTimestamp time.Time) (*BatchSimResults, error)
+	// SimulateUnordered is like SimulateUnsigned, but simulates an unordered tx.
+	SimulateUnordered(ctx context.Context, msgs []sdk.Msg, timeoutTimestamp time.Time) (*txtypes.SimulateResponse, error)
+	// CreateAndSignUnordered is like CreateAndSign, but creates an unordered tx, which times out at timeoutTimestamp.
+	CreateAndSignUnordered(msgs []sdk.Msg, account uint64, gasLimit uint64, gasLimitMultiplier float64, gasPrice sdk.DecCoin, signer cryptotypes.PrivKey, timeoutTimestamp time.Time, memo string) ([]byte, error)
+}
+
+// MultisigSigner signs for a multisig account with the keys of its cosigners.
+type MultisigSigner interface {
+	// PubKey returns the key of the multisig account.
+	PubKey() *kmultisig.LegacyAminoPubKey
+	// Sign returns the signature of signBytes by each cosigner, in the order of their keys in PubKey,
+	// or nil for each cosigner which did not sign.
+	Sign(signBytes []byte) ([][]byte, error)
 }
 
 var _ ReaderWriter = (*Client)(nil)
@@ -85,12 +134,21 @@ const (
 // Client is a cosmos client
 type Client struct {
 	chainID                 string
+	tendermintURL           string
+	httpOpts                HTTPOptions
 	clientCtx               cosmosclient.Context
 	cosmosServiceClient     txtypes.ServiceClient
 	authClient              authtypes.QueryClient
 	wasmClient              wasmtypes.QueryClient
 	bankClient              banktypes.QueryClient
+	stakingClient           stakingtypes.QueryClient
+	nodeClient              nodetypes.ServiceClient
 	tendermintServiceClient tmtypes.ServiceClient
+	tmClient                *rpchttp.HTTP
+	verifier                HeaderVerifier
+	addressCodec            *params.AddressCodec
+	simPubKey               cryptotypes.PubKey
+	signMode                signing.SignMode
 	log                     logger.Logger
 }
 
@@ -100,16 +158,17 @@ func NewClient(chainID string,
 	requestTimeout time.Duration,
 	lggr logger.Logger,
 ) (*Client, error) {
-	if requestTimeout <= 0 {
-		requestTimeout = DefaultTimeout
-	}
+	return NewClientWithHTTPOptions(chainID, tendermintURL, requestTimeout, HTTPOptions{}, lggr)
+}
 
-	httpClient, err := libclient.DefaultHTTPClient(tendermintURL)
-	if err != nil {
-		return nil, err
-	}
-	httpClient.Timeout = requestTimeout
-	tmClient, err := rpchttp.NewWithClient(tendermintURL, "/websocket", httpClient)
+// NewClientWithHTTPOptions creates a new cosmos client, which applies httpOpts to each request.
+func NewClientWithHTTPOptions(chainID string,
+	tendermintURL string,
+	requestTimeout time.Duration,
+	httpOpts HTTPOptions,
+	lggr logger.Logger,
+) (*Client, error) {
+	tmClient, err := newTendermintClient(tendermintURL, requestTimeout, httpOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -127,27 +186,89 @@ func NewClient(chainID string,
 	wasmClient := wasmtypes.NewQueryClient(clientCtx)
 	tendermintServiceClient := tmtypes.NewServiceClient(clientCtx)
 	bankClient := banktypes.NewQueryClient(clientCtx)
+	stakingClient := stakingtypes.NewQueryClient(clientCtx)
+	nodeClient := nodetypes.NewServiceClient(clientCtx)
 
 	return &Client{
 		chainID:                 chainID,
+		tendermintURL:           tendermintURL,
+		httpOpts:                httpOpts,
 		cosmosServiceClient:     cosmosServiceClient,
 		authClient:              authClient,
 		wasmClient:              wasmClient,
 		tendermintServiceClient: tendermintServiceClient,
 		bankClient:              bankClient,
+		stakingClient:           stakingClient,
+		nodeClient:              nodeClient,
+		tmClient:                tmClient,
 		clientCtx:               clientCtx,
+		signMode:                signing.SignMode_SIGN_MODE_DIRECT,
 		log:                     lggr,
 	}, nil
 }
 
+// newTendermintClient returns an RPC client of the node at tendermintURL.
+func newTendermintClient(tendermintURL string, requestTimeout time.Duration, httpOpts HTTPOptions) (*rpchttp.HTTP, error) {
+	if requestTimeout <= 0 {
+		requestTimeout = DefaultTimeout
+	}
+	httpClient, err := libclient.DefaultHTTPClient(tendermintURL)
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Timeout = requestTimeout
+	httpOpts.apply(httpClient)
+	return rpchttp.NewWithClient(tendermintURL, "/websocket", httpClient)
+}
+
 func (c *Client) Context() *cosmosclient.Context {
 	return &c.clientCtx
 }
 
+// SetSimulationPubKey makes simulations use an empty pubKey of the same type as pubKey, instead of secp256k1,
+// so that the gas of verifying the signatures of a chain with other keys is estimated.
+// It must be called before the client is used.
+func (c *Client) SetSimulationPubKey(pubKey cryptotypes.PubKey) {
+	c.simPubKey = pubKey
+}
+
+// SetSignMode makes CreateAndSign sign in mode, instead of SIGN_MODE_DIRECT, for chains and signers which
+// require another mode, such as SIGN_MODE_LEGACY_AMINO_JSON. mode must be enabled by params.ClientTxConfig.
+// It must be called before the client is used.
+func (c *Client) SetSignMode(mode signing.SignMode) {
+	c.signMode = mode
+}
+
+// SetAddressCodec makes the client encode the addresses in its queries with codec, instead of
+// the global sdk config, so that it can be used for a chain with any bech32 prefix.
+// It must be called before the client is used.
+func (c *Client) SetAddressCodec(codec params.AddressCodec) {
+	c.addressCodec = &codec
+}
+
+// address returns addr as a bech32 string, with the prefix of the chain.
+func (c *Client) address(addr sdk.AccAddress) string {
+	if c.addressCodec == nil {
+		return addr.String()
+	}
+	return c.addressCodec.MustBytesToString(addr)
+}
+
+// ContextWithHeight returns a copy of ctx which pins queries to the state at height.
+// The height is sent via the x-cosmos-block-height header, which the client context
+// translates into the ABCI query height. A height of 0 means the latest state.
+// Note that nodes only serve heights which have not been pruned.
+func ContextWithHeight(ctx context.Context, height int64) context.Context {
+	if height == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, grpctypes.GRPCBlockHeightHeader, strconv.FormatInt(height, 10))
+}
+
 // Account read the account address for the account number and sequence number.
 // !!Note only one sequence number can be used per account per block!!
 func (c *Client) Account(ctx context.Context, addr sdk.AccAddress) (uint64, uint64, error) {
-	r, err := c.authClient.Account(ctx, &authtypes.QueryAccountRequest{Address: addr.String()})
+	r, err := c.authClient.Account(ctx, &authtypes.QueryAccountRequest{Address: c.address(addr)})
 	if err != nil {
 		return 0, 0, err
 	}
@@ -159,10 +280,18 @@ func (c *Client) Account(ctx context.Context, addr sdk.AccAddress) (uint64, uint
 	return a.GetAccountNumber(), a.GetSequence(), nil
 }
 
+// AccountAtHeight reads the account number and sequence number as of height.
+func (c *Client) AccountAtHeight(ctx context.Context, addr sdk.AccAddress, height int64) (uint64, uint64, error) {
+	return c.Account(ContextWithHeight(ctx, height), addr)
+}
+
 // ContractState reads from a WASM contract store
 func (c *Client) ContractState(ctx context.Context, contractAddress sdk.AccAddress, queryMsg []byte) ([]byte, error) {
+	if c.verifier != nil {
+		return nil, fmt.Errorf("%w: smart contract queries have no proof", ErrUnverifiable)
+	}
 	s, err := c.wasmClient.SmartContractState(ctx, &wasmtypes.QuerySmartContractStateRequest{
-		Address:   contractAddress.String(),
+		Address:   c.address(contractAddress),
 		QueryData: queryMsg,
 	})
 	if err != nil {
@@ -172,16 +301,32 @@ func (c *Client) ContractState(ctx context.Context, contractAddress sdk.AccAddre
 	return s.Data, err
 }
 
+// ContractStateAtHeight reads from a WASM contract store as of height.
+// Pinning several queries to the same height gives a consistent snapshot of the contract.
+func (c *Client) ContractStateAtHeight(ctx context.Context, contractAddress sdk.AccAddress, queryMsg []byte, height int64) ([]byte, error) {
+	return c.ContractState(ContextWithHeight(ctx, height), contractAddress, queryMsg)
+}
+
 // TxsEvents returns in tx events in descending order (latest txes first).
 // Each event is ANDed together and follows the query language defined
 // https://docs.cosmos.network/master/core/events.html
 // Note one current issue https://github.com/cosmos/cosmos-sdk/issues/10448
+// Only the Offset and Limit of paginationParams are used, and Offset must be a multiple of Limit.
 func (c *Client) TxsEvents(ctx context.Context, events []string, paginationParams *query.PageRequest) (*txtypes.GetTxsEventResponse, error) {
-	e, err := c.cosmosServiceClient.GetTxsEvent(ctx, &txtypes.GetTxsEventRequest{
+	req := &txtypes.GetTxsEventRequest{
 		Events:     events,
 		Pagination: paginationParams,
 		OrderBy:    txtypes.OrderBy_ORDER_BY_DESC,
-	})
+	}
+	// Since cosmos-sdk v0.47 the node ignores Pagination in favour of Page and Limit.
+	if paginationParams != nil && paginationParams.Limit > 0 {
+		if paginationParams.Offset%paginationParams.Limit != 0 {
+			return nil, fmt.Errorf("offset %d is not a multiple of limit %d", paginationParams.Offset, paginationParams.Limit)
+		}
+		req.Limit = paginationParams.Limit
+		req.Page = paginationParams.Offset/paginationParams.Limit + 1
+	}
+	e, err := c.cosmosServiceClient.GetTxsEvent(ctx, req)
 	return e, err
 }
 
@@ -203,35 +348,53 @@ func (c *Client) BlockByHeight(ctx context.Context, height int64) (*tmtypes.GetB
 	return c.tendermintServiceClient.GetBlockByHeight(ctx, &tmtypes.GetBlockByHeightRequest{Height: height})
 }
 
+// BlockResults gets the results of executing the block at height.
+// A height of 0 means the latest block.
+func (c *Client) BlockResults(ctx context.Context, height int64) (*coretypes.ResultBlockResults, error) {
+	var h *int64
+	if height != 0 {
+		h = &height
+	}
+	return c.tmClient.BlockResults(ctx, h)
+}
+
+// BlockSearch searches for blocks by their begin and end block events.
+// The query follows the tendermint query language, e.g. "block.height > 100".
+// orderBy is either "asc" or "desc", and page starts at 1.
+func (c *Client) BlockSearch(ctx context.Context, query string, page, perPage int, orderBy string) (*coretypes.ResultBlockSearch, error) {
+	return c.tmClient.BlockSearch(ctx, query, &page, &perPage, orderBy)
+}
+
+// GasFee returns the gas limit of a tx, which is gasLimit buffered by gasLimitMultiplier, and its fee at gasPrice.
+func GasFee(gasLimit uint64, gasLimitMultiplier float64, gasPrice sdk.DecCoin) (uint64, sdk.Coin) {
+	gasLimitBuffered := uint64(math.Ceil(float64(gasLimit) * gasLimitMultiplier))
+	return gasLimitBuffered, sdk.NewCoin(gasPrice.Denom, gasPrice.Amount.MulInt64(int64(gasLimitBuffered)).Ceil().RoundInt())
+}
+
 // CreateAndSign creates and signs a transaction
-func (c *Client) CreateAndSign(msgs []sdk.Msg, account uint64, sequence uint64, gasLimit uint64, gasLimitMultiplier float64, gasPrice sdk.DecCoin, signer cryptotypes.PrivKey, timeoutHeight uint64) ([]byte, error) {
+func (c *Client) CreateAndSign(msgs []sdk.Msg, account uint64, sequence uint64, gasLimit uint64, gasLimitMultiplier float64, gasPrice sdk.DecCoin, signer cryptotypes.PrivKey, timeoutHeight uint64, memo string) ([]byte, error) {
 	// https://github.com/cosmos/cosmos-sdk/blob/a785bf5af602525cf7a5c5ea097056597e2eb7ef/client/tx/tx.go#L63-L117
 	// https://docs.cosmos.network/main/run-node/txs#signing-a-transaction-1
 	txConfig := params.ClientTxConfig()
-	txBuilder := txConfig.NewTxBuilder()
-	err := txBuilder.SetMsgs(msgs...)
+	txBuilder, err := newTxBuilder(txConfig, msgs, gasLimit, gasLimitMultiplier, gasPrice, timeoutHeight, memo)
 	if err != nil {
 		return nil, err
 	}
-	gasLimitBuffered := uint64(math.Ceil(float64(gasLimit) * gasLimitMultiplier))
-	txBuilder.SetGasLimit(gasLimitBuffered)
-	gasFee := sdk.NewCoin(gasPrice.Denom, gasPrice.Amount.MulInt64(int64(gasLimitBuffered)).Ceil().RoundInt())
-	txBuilder.SetFeeAmount(sdk.NewCoins(gasFee))
-	// 0 timeout height means unset.
-	txBuilder.SetTimeoutHeight(timeoutHeight)
 
 	// Sign
 	// https://github.com/cosmos/cosmos-sdk/blob/a785bf5af602525cf7a5c5ea097056597e2eb7ef/client/tx/tx.go#L230-L337
 
 	pubKey := signer.PubKey()
 
-	// signMode := txConfig.SignModeHandler().DefaultMode()
-	signMode := signing.SignMode_SIGN_MODE_DIRECT
+	signMode := c.signMode
 
+	// the address and pubKey are only part of the sign bytes of some modes, but are required by their handlers
 	signerData := authsigning.SignerData{
+		Address:       c.address(sdk.AccAddress(pubKey.Address())),
 		AccountNumber: account,
 		ChainID:       c.chainID,
 		Sequence:      sequence,
+		PubKey:        pubKey,
 	}
 
 	// For SIGN_MODE_DIRECT, calling SetSignatures calls setSignerInfos on
@@ -277,10 +440,82 @@ func (c *Client) CreateAndSign(msgs []sdk.Msg, account uint64, sequence uint64,
 	return txConfig.TxEncoder()(txBuilder.GetTx())
 }
 
+// CreateAndSignMultisig creates a transaction, and signs it with the signatures of the first threshold of the
+// cosigners of signer which sign. Each cosigner signs the legacy amino JSON sign bytes, which unlike the direct
+// sign bytes do not depend on which cosigners sign, so that they can sign independently.
+func (c *Client) CreateAndSignMultisig(msgs []sdk.Msg, account uint64, sequence uint64, gasLimit uint64, gasLimitMultiplier float64, gasPrice sdk.DecCoin, signer MultisigSigner, timeoutHeight uint64, memo string) ([]byte, error) {
+	txConfig := params.ClientTxConfig()
+	txBuilder, err := newTxBuilder(txConfig, msgs, gasLimit, gasLimitMultiplier, gasPrice, timeoutHeight, memo)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey := signer.PubKey()
+	signMode := signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON
+	signerData := authsigning.SignerData{
+		Address:       c.address(sdk.AccAddress(pubKey.Address())),
+		AccountNumber: account,
+		ChainID:       c.chainID,
+		Sequence:      sequence,
+		PubKey:        pubKey,
+	}
+	signBytes, err := txConfig.SignModeHandler().GetSignBytes(signMode, signerData, txBuilder.GetTx())
+	if err != nil {
+		return nil, err
+	}
+	sigs, err := signer.Sign(signBytes)
+	if err != nil {
+		return nil, err
+	}
+	keys := pubKey.GetPubKeys()
+	if len(sigs) != len(keys) {
+		return nil, fmt.Errorf("got %d signatures for %d cosigners", len(sigs), len(keys))
+	}
+
+	data := multisig.NewMultisig(len(keys))
+	var signed uint32
+	for i, sig := range sigs {
+		if sig == nil || signed == pubKey.Threshold {
+			continue
+		}
+		multisig.AddSignature(data, &signing.SingleSignatureData{SignMode: signMode, Signature: sig}, i)
+		signed++
+	}
+	if signed < pubKey.Threshold {
+		return nil, fmt.Errorf("%d of %d cosigners signed, but the threshold is %d", signed, len(keys), pubKey.Threshold)
+	}
+	if err = txBuilder.SetSignatures(signing.SignatureV2{
+		PubKey:   pubKey,
+		Data:     data,
+		Sequence: sequence,
+	}); err != nil {
+		return nil, err
+	}
+	return txConfig.TxEncoder()(txBuilder.GetTx())
+}
+
+// newTxBuilder returns a builder of an unsigned tx of msgs.
+func newTxBuilder(txConfig cosmosclient.TxConfig, msgs []sdk.Msg, gasLimit uint64, gasLimitMultiplier float64, gasPrice sdk.DecCoin, timeoutHeight uint64, memo string) (cosmosclient.TxBuilder, error) {
+	txBuilder := txConfig.NewTxBuilder()
+	err := txBuilder.SetMsgs(msgs...)
+	if err != nil {
+		return nil, err
+	}
+	gasLimitBuffered, gasFee := GasFee(gasLimit, gasLimitMultiplier, gasPrice)
+	txBuilder.SetGasLimit(gasLimitBuffered)
+	txBuilder.SetFeeAmount(sdk.NewCoins(gasFee))
+	// 0 timeout height means unset.
+	txBuilder.SetTimeoutHeight(timeoutHeight)
+	txBuilder.SetMemo(memo)
+	return txBuilder, nil
+}
+
 // SimMsg binds an ID to a msg
 type SimMsg struct {
 	ID  int64
 	Msg sdk.Msg
+	// GasUsed is the share of the msg of the gas used by the tx of the msgs which succeeded in BatchSimulateUnsigned.
+	GasUsed uint64
 }
 
 // SimMsgs is a slice of SimMsg
@@ -308,6 +543,8 @@ func (simMsgs SimMsgs) GetSimMsgsIDs() []int64 {
 type BatchSimResults struct {
 	Failed    SimMsgs
 	Succeeded SimMsgs
+	// GasUsed is the gas used by a tx of the Succeeded msgs.
+	GasUsed uint64
 }
 
 var failedMsgIndexRe = regexp.MustCompile(`^.*failed to execute message; message index: (?P<Index>\d+):.*$`)
@@ -328,49 +565,79 @@ func (c *Client) failedMsgIndex(err error) (bool, int) {
 	return true, int(index)
 }
 
-// BatchSimulateUnsigned simulates a group of msgs.
+// BatchSimulateUnsigned simulates a group of msgs, and returns those which succeed together with the gas they use.
 // Assumes at least one msg is present.
-// If we fail to simulate the batch, remove the offending tx
-// and try again. Repeat until we have a successful batch.
+// If we fail to simulate the batch, remove the offending msg, which is indicated by the error,
+// and simulate the rest again. Repeat until we have a successful batch, so that a batch without failures
+// takes a single round trip.
 // Keep track of failures so we can mark them as errored.
-// Note that the error from simulating indicates the first
-// msg in the slice which failed (it simply loops over the msgs
-// and simulates them one by one, breaking at the first failure).
 func (c *Client) BatchSimulateUnsigned(ctx context.Context, msgs SimMsgs, sequence uint64) (*BatchSimResults, error) {
-	var succeeded []SimMsg
-	var failed []SimMsg
-	toSim := msgs
+	return c.batchSimulate(ctx, msgs, func(ctx context.Context, msgs []sdk.Msg) (uint64, error) {
+		return gasUsed(c.SimulateUnsigned(ctx, msgs, sequence))
+	})
+}
+
+// simulateFn simulates a tx of msgs, and returns the gas it uses.
+type simulateFn func(ctx context.Context, msgs []sdk.Msg) (uint64, error)
+
+func gasUsed(resp *txtypes.SimulateResponse, err error) (uint64, error) {
+	if err != nil {
+		return 0, err
+	}
+	if resp.GasInfo == nil {
+		return 0, errors.New("simulation is missing gas info")
+	}
+	return resp.GasInfo.GasUsed, nil
+}
+
+func (c *Client) batchSimulate(ctx context.Context, msgs SimMsgs, simulate simulateFn) (*BatchSimResults, error) {
+	var failed SimMsgs
+	toSim := slices.Clone(msgs)
 	for {
-		_, err := c.SimulateUnsigned(ctx, toSim.GetMsgs(), sequence)
+		gas, err := simulate(ctx, toSim.GetMsgs())
+		if err == nil {
+			attributeGas(toSim, gas)
+			return &BatchSimResults{
+				Failed:    failed,
+				Succeeded: toSim,
+				GasUsed:   gas,
+			}, nil
+		}
 		containsFailure, failureIndex := c.failedMsgIndex(err)
-		if err != nil && !containsFailure {
+		if !containsFailure || failureIndex >= len(toSim) {
 			return nil, err
 		}
-		if !containsFailure {
-			// we're done they all succeeded
-			succeeded = append(succeeded, toSim...)
-			break
-		}
+		// Only the msg the batch failed at is removed, since later msgs may depend on the msgs before it.
+		c.log.Warnf("simulation error found in a msg, failure %v, index %v, err %v", toSim[failureIndex], failureIndex, err)
 		failed = append(failed, toSim[failureIndex])
-		succeeded = append(succeeded, toSim[:failureIndex]...)
-		// remove offending msg and retry
-		if failureIndex == len(toSim)-1 {
-			// we're done, last one failed
-			c.log.Warnf("simulation error found in last msg, failure %v, index %v, err %v", toSim[failureIndex], failureIndex, err)
-			break
+		toSim = slices.Delete(toSim, failureIndex, failureIndex+1)
+		if len(toSim) == 0 {
+			return &BatchSimResults{Failed: failed}, nil
 		}
-		// otherwise there may be more to sim
-		c.log.Warnf("simulation error found in a msg, retrying with %v, failure %v, index %v, err %v", toSim[failureIndex+1:], toSim[failureIndex], failureIndex, err)
-		toSim = toSim[failureIndex+1:]
 	}
-	return &BatchSimResults{
-		Failed:    failed,
-		Succeeded: succeeded,
-	}, nil
+}
+
+// attributeGas sets the GasUsed of each of msgs, which used gasUsed together. Simulating each msg alone would cost
+// a round trip per msg, so the gas is split evenly among them, with the remainder attributed to the first.
+func attributeGas(msgs SimMsgs, gasUsed uint64) {
+	share := gasUsed / uint64(len(msgs))
+	for i := range msgs {
+		msgs[i].GasUsed = share
+	}
+	msgs[0].GasUsed += gasUsed % uint64(len(msgs))
 }
 
 // SimulateUnsigned simulates an unsigned msg
 func (c *Client) SimulateUnsigned(ctx context.Context, msgs []sdk.Msg, sequence uint64) (*txtypes.SimulateResponse, error) {
+	txBytes, err := c.encodeUnsigned(msgs, sequence)
+	if err != nil {
+		return nil, err
+	}
+	return c.Simulate(ctx, txBytes)
+}
+
+// encodeUnsigned encodes a tx of msgs with an empty signature, for simulation.
+func (c *Client) encodeUnsigned(msgs []sdk.Msg, sequence uint64) ([]byte, error) {
 	txConfig := params.ClientTxConfig()
 	txBuilder := txConfig.NewTxBuilder()
 	if err := txBuilder.SetMsgs(msgs...); err != nil {
@@ -379,24 +646,21 @@ func (c *Client) SimulateUnsigned(ctx context.Context, msgs []sdk.Msg, sequence
 	// Create an empty signature literal as the ante handler will populate with a
 	// sentinel pubkey.
 	// Note the simulation actually won't work without this
+	var pubKey cryptotypes.PubKey = &secp256k1.PubKey{}
+	if c.simPubKey != nil {
+		pubKey = c.simPubKey
+	}
 	sig := signing.SignatureV2{
-		PubKey: &secp256k1.PubKey{},
+		PubKey: pubKey,
 		Data: &signing.SingleSignatureData{
-			SignMode: signing.SignMode_SIGN_MODE_DIRECT,
+			SignMode: c.signMode,
 		},
 		Sequence: sequence,
 	}
 	if err := txBuilder.SetSignatures(sig); err != nil {
 		return nil, err
 	}
-	txBytes, err := txConfig.TxEncoder()(txBuilder.GetTx())
-	if err != nil {
-		return nil, err
-	}
-	s, err := c.cosmosServiceClient.Simulate(ctx, &txtypes.SimulateRequest{
-		TxBytes: txBytes,
-	})
-	return s, err
+	return txConfig.TxEncoder()(txBuilder.GetTx())
 }
 
 // Simulate simulates a signed transaction
@@ -432,7 +696,7 @@ func (c *Client) SignAndBroadcast(ctx context.Context, msgs []sdk.Msg, account u
 		return nil, err
 	}
 	// TODO: replace with BroadcastTx()?
-	txBytes, err := c.CreateAndSign(msgs, account, sequence, sim.GasInfo.GasUsed, DefaultGasLimitMultiplier, gasPrice, signer, 0)
+	txBytes, err := c.CreateAndSign(msgs, account, sequence, sim.GasInfo.GasUsed, DefaultGasLimitMultiplier, gasPrice, signer, 0, "")
 	if err != nil {
 		return nil, err
 	}
@@ -441,9 +705,23 @@ func (c *Client) SignAndBroadcast(ctx context.Context, msgs []sdk.Msg, account u
 
 // Balance returns the balance of an address
 func (c *Client) Balance(ctx context.Context, addr sdk.AccAddress, denom string) (*sdk.Coin, error) {
-	b, err := c.bankClient.Balance(ctx, &banktypes.QueryBalanceRequest{Address: addr.String(), Denom: denom})
+	b, err := c.bankClient.Balance(ctx, &banktypes.QueryBalanceRequest{Address: c.address(addr), Denom: denom})
 	if err != nil {
 		return nil, err
 	}
 	return b.Balance, nil
 }
+
+// BalanceAtHeight returns the balance of an address as of height
+func (c *Client) BalanceAtHeight(ctx context.Context, addr sdk.AccAddress, denom string, height int64) (*sdk.Coin, error) {
+	return c.Balance(ContextWithHeight(ctx, height), addr, denom)
+}
+
+// DenomMetadata returns the bank metadata of denom
+func (c *Client) DenomMetadata(ctx context.Context, denom string) (*banktypes.Metadata, error) {
+	r, err := c.bankClient.DenomMetadata(ctx, &banktypes.QueryDenomMetadataRequest{Denom: denom})
+	if err != nil {
+		return nil, err
+	}
+	return &r.Metadata, nil
+}
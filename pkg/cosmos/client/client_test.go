@@ -1,15 +1,25 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"sync/atomic"
 	"testing"
 
 	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
 	"github.com/cometbft/cometbft/abci/types"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/query"
 	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	"github.com/cosmos/cosmos-sdk/x/authz"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -48,6 +58,229 @@ func TestErrMatch(t *testing.T) {
 	assert.Equal(t, m[1], "10000")
 }
 
+// testMultisigSigner signs with the keys of the cosigners which are set.
+type testMultisigSigner struct {
+	pubKey *kmultisig.LegacyAminoPubKey
+	keys   []cryptotypes.PrivKey
+}
+
+func (s *testMultisigSigner) PubKey() *kmultisig.LegacyAminoPubKey { return s.pubKey }
+
+func (s *testMultisigSigner) Sign(signBytes []byte) ([][]byte, error) {
+	sigs := make([][]byte, len(s.keys))
+	for i, key := range s.keys {
+		if key != nil {
+			sig, err := key.Sign(signBytes)
+			if err != nil {
+				return nil, err
+			}
+			sigs[i] = sig
+		}
+	}
+	return sigs, nil
+}
+
+func TestClient_CreateAndSignMultisig(t *testing.T) {
+	keys := []cryptotypes.PrivKey{secp256k1.GenPrivKey(), secp256k1.GenPrivKey(), secp256k1.GenPrivKey()}
+	pubKey := kmultisig.NewLegacyAminoPubKey(2, []cryptotypes.PubKey{keys[0].PubKey(), keys[1].PubKey(), keys[2].PubKey()})
+	from := sdk.AccAddress(pubKey.Address())
+	revoke := authz.NewMsgRevoke(from, sdk.AccAddress(keys[0].PubKey().Address()), sdk.MsgTypeURL(&banktypes.MsgSend{}))
+	msgs := []sdk.Msg{&revoke}
+	gasPrice := sdk.NewDecCoinFromDec("ucosm", sdk.MustNewDecFromStr("0.01"))
+	c := &Client{chainID: "test-chain"}
+
+	t.Run("threshold", func(t *testing.T) {
+		// the first two cosigners which sign
+		signer := &testMultisigSigner{pubKey: pubKey, keys: []cryptotypes.PrivKey{keys[0], nil, keys[2]}}
+		txBytes, err := c.CreateAndSignMultisig(msgs, 3, 5, 100_000, 1.5, gasPrice, signer, 0, "memo")
+		require.NoError(t, err)
+
+		txConfig := params.ClientTxConfig()
+		decoded, err := txConfig.TxDecoder()(txBytes)
+		require.NoError(t, err)
+		tx := decoded.(authsigning.Tx)
+		sigs, err := tx.GetSignaturesV2()
+		require.NoError(t, err)
+		require.Len(t, sigs, 1)
+		assert.True(t, pubKey.Equals(sigs[0].PubKey))
+		assert.Equal(t, uint64(5), sigs[0].Sequence)
+		data, ok := sigs[0].Data.(*signing.MultiSignatureData)
+		require.True(t, ok)
+		assert.Len(t, data.Signatures, 2)
+		assert.True(t, data.BitArray.GetIndex(0))
+		assert.False(t, data.BitArray.GetIndex(1))
+		assert.True(t, data.BitArray.GetIndex(2))
+
+		signerData := authsigning.SignerData{Address: from.String(), ChainID: "test-chain", AccountNumber: 3, Sequence: 5, PubKey: pubKey}
+		require.NoError(t, authsigning.VerifySignature(pubKey, signerData, sigs[0].Data, txConfig.SignModeHandler(), tx))
+		signerData.AccountNumber = 4
+		require.Error(t, authsigning.VerifySignature(pubKey, signerData, sigs[0].Data, txConfig.SignModeHandler(), tx))
+	})
+
+	t.Run("below threshold", func(t *testing.T) {
+		signer := &testMultisigSigner{pubKey: pubKey, keys: []cryptotypes.PrivKey{nil, keys[1], nil}}
+		_, err := c.CreateAndSignMultisig(msgs, 3, 5, 100_000, 1.5, gasPrice, signer, 0, "")
+		require.EqualError(t, err, "1 of 3 cosigners signed, but the threshold is 2")
+	})
+}
+
+func TestClient_CreateAndSign(t *testing.T) {
+	key := secp256k1.GenPrivKeyFromSecret([]byte("sign modes"))
+	from := sdk.AccAddress(key.PubKey().Address())
+	send := banktypes.NewMsgSend(from, from, sdk.NewCoins(sdk.NewInt64Coin("ucosm", 1)))
+	gasPrice := sdk.NewDecCoinFromDec("ucosm", sdk.MustNewDecFromStr("0.01"))
+
+	for _, tt := range []struct {
+		name      string
+		mode      signing.SignMode
+		signBytes func(t *testing.T, raw *txtypes.TxRaw) []byte
+	}{
+		{name: "direct", mode: signing.SignMode_SIGN_MODE_DIRECT, signBytes: func(t *testing.T, raw *txtypes.TxRaw) []byte {
+			signDoc := txtypes.SignDoc{BodyBytes: raw.BodyBytes, AuthInfoBytes: raw.AuthInfoBytes, ChainId: "test-chain", AccountNumber: 3}
+			b, err := signDoc.Marshal()
+			require.NoError(t, err)
+			return b
+		}},
+		{name: "amino json", mode: signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON, signBytes: func(t *testing.T, raw *txtypes.TxRaw) []byte {
+			// the StdSignDoc, with sorted keys
+			return []byte(`{"account_number":"3","chain_id":"test-chain","fee":{"amount":[{"amount":"1500","denom":"ucosm"}],"gas":"150000"},` +
+				`"memo":"memo","msgs":[{"type":"cosmos-sdk/MsgSend","value":{"amount":[{"amount":"1","denom":"ucosm"}],` +
+				`"from_address":"wasm1wq0jtz3hju0zng23at3z0ly6cuf77kwa7hp8h0","to_address":"wasm1wq0jtz3hju0zng23at3z0ly6cuf77kwa7hp8h0"}}],` +
+				`"sequence":"5","timeout_height":"1234"}`)
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{chainID: "test-chain"}
+			c.SetSignMode(tt.mode)
+			txBytes, err := c.CreateAndSign([]sdk.Msg{send}, 3, 5, 100_000, 1.5, gasPrice, key, 1234, "memo")
+			require.NoError(t, err)
+
+			var raw txtypes.TxRaw
+			require.NoError(t, raw.Unmarshal(txBytes))
+			require.Len(t, raw.Signatures, 1)
+			assert.True(t, key.PubKey().VerifySignature(tt.signBytes(t, &raw), raw.Signatures[0]))
+
+			txConfig := params.ClientTxConfig()
+			decoded, err := txConfig.TxDecoder()(txBytes)
+			require.NoError(t, err)
+			tx := decoded.(authsigning.Tx)
+			sigs, err := tx.GetSignaturesV2()
+			require.NoError(t, err)
+			require.Len(t, sigs, 1)
+			assert.Equal(t, tt.mode, sigs[0].Data.(*signing.SingleSignatureData).SignMode)
+			signerData := authsigning.SignerData{Address: from.String(), ChainID: "test-chain", AccountNumber: 3, Sequence: 5, PubKey: key.PubKey()}
+			require.NoError(t, authsigning.VerifySignature(key.PubKey(), signerData, sigs[0].Data, txConfig.SignModeHandler(), tx))
+			signerData.AccountNumber = 4
+			require.Error(t, authsigning.VerifySignature(key.PubKey(), signerData, sigs[0].Data, txConfig.SignModeHandler(), tx))
+		})
+	}
+}
+
+// fakeSimulator simulates msgs whose Msg is an int64 ID: each uses 100*ID gas, and the tx 1000 more.
+type fakeSimulator struct {
+	fail map[int64]bool // fail in any tx
+	// needsOthers fail in a tx alone
+	needsOthers map[int64]bool
+	calls       atomic.Int32
+}
+
+func (f *fakeSimulator) simulate(_ context.Context, msgs []sdk.Msg) (uint64, error) {
+	f.calls.Add(1)
+	gas := uint64(1000)
+	for i, msg := range msgs {
+		id := msg.(*fakeMsg).id
+		if f.fail[id] || len(msgs) == 1 && f.needsOthers[id] {
+			return 0, fmt.Errorf("rpc error: code = Unknown desc = failed to execute message; message index: %d: invalid request", i)
+		}
+		gas += 100 * uint64(id)
+	}
+	return gas, nil
+}
+
+type fakeMsg struct {
+	sdk.Msg
+	id int64
+}
+
+func fakeSimMsgs(ids ...int64) (msgs SimMsgs) {
+	for _, id := range ids {
+		msgs = append(msgs, SimMsg{ID: id, Msg: &fakeMsg{id: id}})
+	}
+	return
+}
+
+func TestClient_TxsEvents(t *testing.T) {
+	c := &Client{}
+	_, err := c.TxsEvents(tests.Context(t), []string{"tx.height>=1"}, &query.PageRequest{Offset: 5, Limit: 2})
+	require.EqualError(t, err, "offset 5 is not a multiple of limit 2")
+}
+
+func TestClient_batchSimulate(t *testing.T) {
+	ctx := tests.Context(t)
+	c := &Client{log: logger.Test(t)}
+
+	t.Run("all succeed", func(t *testing.T) {
+		f := &fakeSimulator{}
+		res, err := c.batchSimulate(ctx, fakeSimMsgs(1, 2, 3), f.simulate)
+		require.NoError(t, err)
+		assert.Empty(t, res.Failed)
+		assert.Equal(t, uint64(1600), res.GasUsed)
+		// only the batch is simulated
+		assert.Equal(t, int32(1), f.calls.Load())
+		// split evenly, with the remainder attributed to the first msg
+		assert.Equal(t, []uint64{534, 533, 533}, []uint64{res.Succeeded[0].GasUsed, res.Succeeded[1].GasUsed, res.Succeeded[2].GasUsed})
+	})
+
+	t.Run("single msg", func(t *testing.T) {
+		f := &fakeSimulator{}
+		res, err := c.batchSimulate(ctx, fakeSimMsgs(4), f.simulate)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1400), res.GasUsed)
+		assert.Equal(t, uint64(1400), res.Succeeded[0].GasUsed)
+		assert.Equal(t, int32(1), f.calls.Load())
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		f := &fakeSimulator{fail: map[int64]bool{2: true, 3: true}}
+		res, err := c.batchSimulate(ctx, fakeSimMsgs(1, 2, 3, 4), f.simulate)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{2, 3}, res.Failed.GetSimMsgsIDs())
+		assert.Equal(t, []int64{1, 4}, res.Succeeded.GetSimMsgsIDs())
+		assert.Equal(t, uint64(1500), res.GasUsed)
+		assert.Equal(t, uint64(750), res.Succeeded[0].GasUsed)
+		assert.Equal(t, uint64(750), res.Succeeded[1].GasUsed)
+		// each failure is removed in turn
+		assert.Equal(t, int32(3), f.calls.Load())
+	})
+
+	t.Run("all fail", func(t *testing.T) {
+		f := &fakeSimulator{fail: map[int64]bool{1: true, 2: true}}
+		res, err := c.batchSimulate(ctx, fakeSimMsgs(1, 2), f.simulate)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 2}, res.Failed.GetSimMsgsIDs())
+		assert.Empty(t, res.Succeeded)
+		assert.Equal(t, int32(2), f.calls.Load())
+	})
+
+	t.Run("depends on earlier msg", func(t *testing.T) {
+		// 5 fails alone, but succeeds after 1, so only the failure before it is removed
+		f := &fakeSimulator{fail: map[int64]bool{2: true}, needsOthers: map[int64]bool{5: true}}
+		res, err := c.batchSimulate(ctx, fakeSimMsgs(1, 2, 5), f.simulate)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{2}, res.Failed.GetSimMsgsIDs())
+		assert.Equal(t, []int64{1, 5}, res.Succeeded.GetSimMsgsIDs())
+		assert.Equal(t, uint64(1600), res.GasUsed)
+		assert.Equal(t, int32(2), f.calls.Load())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := c.batchSimulate(ctx, fakeSimMsgs(1, 2), func(context.Context, []sdk.Msg) (uint64, error) {
+			return 0, errors.New("connection refused")
+		})
+		require.EqualError(t, err, "connection refused")
+	})
+}
+
 func TestBatchSim(t *testing.T) {
 	accounts, testdir, tendermintURL := SetupLocalCosmosNode(t, "42", "ucosm")
 
@@ -167,7 +400,7 @@ func TestCosmosClient(t *testing.T) {
 		require.NoError(t, err)
 		gasPrices, err := gpe.GasPrices()
 		require.NoError(t, err)
-		txBytes, err := tc.CreateAndSign([]sdk.Msg{fund}, an, sn, gasLimit.GasInfo.GasUsed, DefaultGasLimitMultiplier, gasPrices["ucosm"], accounts[0].PrivateKey, 0)
+		txBytes, err := tc.CreateAndSign([]sdk.Msg{fund}, an, sn, gasLimit.GasInfo.GasUsed, DefaultGasLimitMultiplier, gasPrices["ucosm"], accounts[0].PrivateKey, 0, "")
 		require.NoError(t, err)
 		_, err = tc.Simulate(ctx, txBytes)
 		require.NoError(t, err)
@@ -182,6 +415,11 @@ func TestCosmosClient(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "100000001", b.Amount.String())
 
+		// Balance before the tx height should be unchanged
+		b, err = tc.BalanceAtHeight(ctx, accounts[1].Address, "ucosm", tx.TxResponse.Height-1)
+		require.NoError(t, err)
+		assert.Equal(t, "100000000", b.Amount.String())
+
 		// Invalid tx should error
 		_, err = tc.Tx(ctx, "1234")
 		require.Error(t, err)
@@ -264,6 +502,16 @@ func TestCosmosClient(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, `{"count":4}`, string(count))
 
+		// Observe the contract state as of the first execution
+		count, err = tc.ContractStateAtHeight(
+			ctx,
+			contract,
+			[]byte(`{"get_count":{}}`),
+			tx1.TxResponse.Height,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, `{"count":5}`, string(count))
+
 		// Check events querying works
 		// TxEvents sorts in a descending manner, so latest txes are first
 		ev, err := tc.TxsEvents(ctx, []string{"wasm.action='reset'", fmt.Sprintf("wasm._contract_address='%s'", contract.String())}, nil)
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	abci "github.com/cometbft/cometbft/abci/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+)
+
+const (
+	// legacyContractAddrKey is the contract address attribute key used by wasmd before v0.16.
+	legacyContractAddrKey = "contract_address"
+	// msgIndexKey is the attribute which cosmos-sdk v0.50 adds to each msg event.
+	msgIndexKey = "msg_index"
+)
+
+// TxEvents returns the events emitted by the msgs of tx, for both log formats.
+// Before cosmos-sdk v0.50, nodes report events in the ABCI log of each msg,
+// merging events of the same type. Later nodes leave Logs empty and report
+// each event in Events, with plain (non-base64) attributes.
+// Use DecodeWasmEvents to split merged wasm events back apart.
+func TxEvents(tx *sdk.TxResponse) []abci.Event {
+	if len(tx.Logs) == 0 {
+		return tx.Events
+	}
+	var out []abci.Event
+	for _, log := range tx.Logs {
+		for _, event := range log.Events {
+			out = append(out, StringEventToABCI(event))
+		}
+	}
+	return out
+}
+
+// StringEventToABCI converts an event from an ABCI log into an abci.Event.
+func StringEventToABCI(event sdk.StringEvent) abci.Event {
+	out := abci.Event{Type: event.Type, Attributes: make([]abci.EventAttribute, len(event.Attributes))}
+	for i, attr := range event.Attributes {
+		out.Attributes[i] = abci.EventAttribute{Key: attr.Key, Value: attr.Value}
+	}
+	return out
+}
+
+// WasmEvent is a custom event emitted by a CosmWasm contract, i.e. one whose type has the "wasm-" prefix.
+type WasmEvent struct {
+	// Type is the event type without the "wasm-" prefix, e.g. "set_config".
+	Type string
+	// ContractAddress is the bech32 address of the emitting contract.
+	ContractAddress string
+	// Attributes are the attributes set by the contract, in order. Keys may repeat.
+	Attributes []sdk.Attribute
+}
+
+// DecodeWasmEvents decodes the custom wasm events in events, skipping all others.
+// Since every wasm event starts with the contract address, an event which was merged
+// with others of the same type in an ABCI log is split at each contract address.
+func DecodeWasmEvents(events []abci.Event) []WasmEvent {
+	var out []WasmEvent
+	for _, event := range events {
+		typ, ok := strings.CutPrefix(event.Type, wasmtypes.CustomContractEventPrefix)
+		if !ok {
+			continue
+		}
+		addrKey := wasmtypes.AttributeKeyContractAddr
+		if !hasAttribute(event, addrKey) && hasAttribute(event, legacyContractAddrKey) {
+			addrKey = legacyContractAddrKey
+		}
+		first := len(out)
+		for _, attr := range event.Attributes {
+			switch {
+			case attr.Key == addrKey:
+				out = append(out, WasmEvent{Type: typ, ContractAddress: attr.Value})
+			case attr.Key == msgIndexKey:
+				// set by the sdk, not the contract
+			default:
+				if len(out) == first {
+					out = append(out, WasmEvent{Type: typ})
+				}
+				last := &out[len(out)-1]
+				last.Attributes = append(last.Attributes, sdk.Attribute{Key: attr.Key, Value: attr.Value})
+			}
+		}
+		if len(out) == first {
+			out = append(out, WasmEvent{Type: typ})
+		}
+	}
+	return out
+}
+
+func hasAttribute(event abci.Event, key string) bool {
+	for _, attr := range event.Attributes {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// TxsEventsIterator pages through the results of a TxsEvents query, latest txs first.
+// Txs included while iterating shift the pages, so the same tx may be returned twice.
+type TxsEventsIterator struct {
+	reader Reader
+	events []string
+	limit  uint64
+
+	offset  uint64
+	total   uint64
+	started bool
+	txs     []*sdk.TxResponse
+	err     error
+}
+
+// NewTxsEventsIterator returns an iterator over the txs matching events, fetching limit txs per page.
+// A limit of 0 means the default page size.
+func NewTxsEventsIterator(reader Reader, events []string, limit uint64) *TxsEventsIterator {
+	if limit == 0 {
+		limit = query.DefaultLimit
+	}
+	return &TxsEventsIterator{reader: reader, events: events, limit: limit}
+}
+
+// Next fetches the next page, and returns false once there are no more pages or an error occurred.
+func (it *TxsEventsIterator) Next(ctx context.Context) bool {
+	if it.err != nil || (it.started && it.offset >= it.total) {
+		return false
+	}
+	res, err := it.reader.TxsEvents(ctx, it.events, &query.PageRequest{Offset: it.offset, Limit: it.limit})
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.started = true
+	it.total = res.Total
+	it.txs = res.TxResponses
+	if len(it.txs) == 0 {
+		return false
+	}
+	// TxsEvents requires the offset to be a multiple of the limit.
+	it.offset += it.limit
+	return true
+}
+
+// TxResponses returns the txs of the current page.
+func (it *TxsEventsIterator) TxResponses() []*sdk.TxResponse {
+	return it.txs
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *TxsEventsIterator) Err() error {
+	return it.err
+}
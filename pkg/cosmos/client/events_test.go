@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeWasmEvents(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		events []abci.Event
+		exp    []WasmEvent
+	}{
+		{
+			name: "skips other events",
+			events: []abci.Event{
+				{Type: "message", Attributes: []abci.EventAttribute{{Key: "action", Value: "/cosmwasm.wasm.v1.MsgExecuteContract"}}},
+				{Type: "wasm", Attributes: []abci.EventAttribute{{Key: "_contract_address", Value: "wasm1contract"}}},
+			},
+		},
+		{
+			name: "single",
+			events: []abci.Event{{Type: "wasm-set_config", Attributes: []abci.EventAttribute{
+				{Key: "_contract_address", Value: "wasm1contract"},
+				{Key: "signers", Value: "01"},
+				{Key: "signers", Value: "02"},
+				{Key: "f", Value: "1"},
+			}}},
+			exp: []WasmEvent{{Type: "set_config", ContractAddress: "wasm1contract", Attributes: []sdk.Attribute{
+				{Key: "signers", Value: "01"},
+				{Key: "signers", Value: "02"},
+				{Key: "f", Value: "1"},
+			}}},
+		},
+		{
+			name: "merged in abci log",
+			events: []abci.Event{{Type: "wasm-transfer", Attributes: []abci.EventAttribute{
+				{Key: "_contract_address", Value: "wasm1a"},
+				{Key: "amount", Value: "1"},
+				{Key: "_contract_address", Value: "wasm1b"},
+				{Key: "amount", Value: "2"},
+			}}},
+			exp: []WasmEvent{
+				{Type: "transfer", ContractAddress: "wasm1a", Attributes: []sdk.Attribute{{Key: "amount", Value: "1"}}},
+				{Type: "transfer", ContractAddress: "wasm1b", Attributes: []sdk.Attribute{{Key: "amount", Value: "2"}}},
+			},
+		},
+		{
+			name: "legacy contract address",
+			events: []abci.Event{{Type: "wasm-transmitted", Attributes: []abci.EventAttribute{
+				{Key: "contract_address", Value: "wasm1contract"},
+				{Key: "epoch", Value: "3"},
+			}}},
+			exp: []WasmEvent{{Type: "transmitted", ContractAddress: "wasm1contract", Attributes: []sdk.Attribute{{Key: "epoch", Value: "3"}}}},
+		},
+		{
+			name: "msg index",
+			events: []abci.Event{{Type: "wasm-transmitted", Attributes: []abci.EventAttribute{
+				{Key: "_contract_address", Value: "wasm1contract"},
+				{Key: "epoch", Value: "3"},
+				{Key: "msg_index", Value: "0"},
+			}}},
+			exp: []WasmEvent{{Type: "transmitted", ContractAddress: "wasm1contract", Attributes: []sdk.Attribute{{Key: "epoch", Value: "3"}}}},
+		},
+		{
+			name:   "no attributes",
+			events: []abci.Event{{Type: "wasm-ping"}},
+			exp:    []WasmEvent{{Type: "ping"}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.exp, DecodeWasmEvents(tt.events))
+		})
+	}
+}
+
+func TestTxEvents(t *testing.T) {
+	event := abci.Event{Type: "wasm-set_config", Attributes: []abci.EventAttribute{
+		{Key: "_contract_address", Value: "wasm1contract"},
+		{Key: "f", Value: "1"},
+	}}
+
+	t.Run("abci logs", func(t *testing.T) {
+		tx := &sdk.TxResponse{
+			Logs: sdk.ABCIMessageLogs{{Events: sdk.StringEvents{{
+				Type:       "wasm-set_config",
+				Attributes: []sdk.Attribute{{Key: "_contract_address", Value: "wasm1contract"}, {Key: "f", Value: "1"}},
+			}}}},
+			// Events are ignored if logs are present
+			Events: []abci.Event{{Type: "tx"}},
+		}
+		assert.Equal(t, []abci.Event{event}, TxEvents(tx))
+	})
+
+	t.Run("events", func(t *testing.T) {
+		tx := &sdk.TxResponse{Events: []abci.Event{event}}
+		assert.Equal(t, []abci.Event{event}, TxEvents(tx))
+	})
+}
+
+// pagedReader serves TxsEvents from a fixed list of txs.
+type pagedReader struct {
+	Reader
+	txs      []*sdk.TxResponse
+	requests []query.PageRequest
+}
+
+func (r *pagedReader) TxsEvents(_ context.Context, _ []string, p *query.PageRequest) (*txtypes.GetTxsEventResponse, error) {
+	r.requests = append(r.requests, *p)
+	start := min(p.Offset, uint64(len(r.txs)))
+	end := min(p.Offset+p.Limit, uint64(len(r.txs)))
+	return &txtypes.GetTxsEventResponse{TxResponses: r.txs[start:end], Total: uint64(len(r.txs))}, nil
+}
+
+func TestTxsEventsIterator(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name  string
+		txs   int
+		limit uint64
+		pages int
+	}{
+		{name: "empty", txs: 0, limit: 2, pages: 0},
+		{name: "single page", txs: 2, limit: 5, pages: 1},
+		{name: "exact pages", txs: 4, limit: 2, pages: 2},
+		{name: "partial last page", txs: 5, limit: 2, pages: 3},
+		{name: "default limit", txs: 3, limit: 0, pages: 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &pagedReader{}
+			for i := 0; i < tt.txs; i++ {
+				reader.txs = append(reader.txs, &sdk.TxResponse{Height: int64(i)})
+			}
+			it := NewTxsEventsIterator(reader, []string{"tx.height>=1"}, tt.limit)
+			var got []*sdk.TxResponse
+			pages := 0
+			for it.Next(ctx) {
+				pages++
+				got = append(got, it.TxResponses()...)
+			}
+			require.NoError(t, it.Err())
+			assert.Equal(t, tt.pages, pages)
+			assert.Equal(t, reader.txs, got)
+			for _, r := range reader.requests {
+				assert.Zero(t, r.Offset%r.Limit, "offset is a multiple of the limit")
+			}
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/goplugin/plugin-common/pkg/fee"
 	"github.com/goplugin/plugin-common/pkg/logger"
@@ -84,6 +86,36 @@ func (gpe *ClosureGasPriceEstimator) GasPrices() (map[string]sdk.DecCoin, error)
 	return gpe.gasPrices()
 }
 
+var _ GasPricesEstimator = (*NodeGasPriceEstimator)(nil)
+
+// NodeGasPriceEstimator estimates gas prices as the minimum gas prices accepted by a node.
+type NodeGasPriceEstimator struct {
+	reader  func() (Reader, error)
+	timeout time.Duration
+}
+
+func NewNodeGasPriceEstimator(reader func() (Reader, error), timeout time.Duration) *NodeGasPriceEstimator {
+	return &NodeGasPriceEstimator{reader: reader, timeout: timeout}
+}
+
+func (gpe *NodeGasPriceEstimator) GasPrices() (map[string]sdk.DecCoin, error) {
+	reader, err := gpe.reader()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), gpe.timeout)
+	defer cancel()
+	minPrices, err := reader.MinimumGasPrices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get minimum gas prices: %w", err)
+	}
+	prices := make(map[string]sdk.DecCoin, len(minPrices))
+	for _, price := range minPrices {
+		prices[price.Denom] = price
+	}
+	return prices, nil
+}
+
 var _ GasPricesEstimator = (*CachingGasPriceEstimator)(nil)
 
 type CachingGasPriceEstimator struct {
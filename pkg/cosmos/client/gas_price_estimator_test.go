@@ -3,6 +3,7 @@ package client
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/goplugin/plugin-common/pkg/logger"
 
@@ -74,6 +75,19 @@ func TestGasPriceEstimators(t *testing.T) {
 		assert.Equal(t, "10.000000000000000000", price.Amount.String())
 	})
 
+	t.Run("node", func(t *testing.T) {
+		gpe := NewNodeGasPriceEstimator(func() (Reader, error) { return &paramsReader{}, nil }, time.Second)
+		p, err := gpe.GasPrices()
+		require.NoError(t, err)
+		price, ok := p["uosmo"]
+		require.True(t, ok)
+		assert.Equal(t, "0.002500000000000000", price.Amount.String())
+
+		gpe = NewNodeGasPriceEstimator(func() (Reader, error) { return nil, errors.New("no nodes") }, time.Second)
+		_, err = gpe.GasPrices()
+		require.EqualError(t, err, "no nodes")
+	})
+
 	t.Run("composed", func(t *testing.T) {
 		responses := []sdk.DecCoin{}
 		closureGpe := NewClosureGasPriceEstimator(func() (map[string]sdk.DecCoin, error) {
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+)
+
+// HTTPOptions configures the HTTP requests made to a node, e.g. to authenticate with an RPC provider.
+// They apply to websocket subscriptions too.
+type HTTPOptions struct {
+	// Headers are set on each request.
+	Headers http.Header
+	// Username and Password are sent with basic auth, if Username is set.
+	Username string
+	Password string
+	// RootCAs verify the TLS certificate of the node instead of the system roots, if set.
+	RootCAs *x509.CertPool
+}
+
+func (o HTTPOptions) apply(httpClient *http.Client) {
+	if o.RootCAs != nil {
+		if t, ok := httpClient.Transport.(*http.Transport); ok {
+			t.TLSClientConfig = o.tlsConfig()
+		}
+	}
+	if len(o.Headers) > 0 || o.Username != "" {
+		httpClient.Transport = &optionsTransport{opts: o, base: httpClient.Transport}
+	}
+}
+
+// tlsConfig returns the TLS config verifying the node with RootCAs, or nil to use the system roots.
+func (o HTTPOptions) tlsConfig() *tls.Config {
+	if o.RootCAs == nil {
+		return nil
+	}
+	return &tls.Config{RootCAs: o.RootCAs, MinVersion: tls.VersionTLS12}
+}
+
+// setHeaders sets the headers and basic auth on req.
+func (o HTTPOptions) setHeaders(req *http.Request) {
+	for key, values := range o.Headers {
+		req.Header.Del(key)
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if o.Username != "" {
+		req.SetBasicAuth(o.Username, o.Password)
+	}
+}
+
+// optionsTransport sets the headers and basic auth of each request.
+type optionsTransport struct {
+	opts HTTPOptions
+	base http.RoundTripper
+}
+
+func (t *optionsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrip must not modify the original request
+	req = req.Clone(req.Context())
+	t.opts.setHeaders(req)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goplugin/plugin-common/pkg/logger"
+	"github.com/goplugin/plugin-common/pkg/utils/tests"
+)
+
+// healthHandler answers JSON-RPC health requests, and records the request headers.
+func healthHandler(t *testing.T, headers chan<- http.Header) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&req)) {
+			return
+		}
+		headers <- r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":{}}`))
+		assert.NoError(t, err)
+	}
+}
+
+func TestNewClientWithHTTPOptions(t *testing.T) {
+	ctx := tests.Context(t)
+
+	t.Run("headers and basic auth", func(t *testing.T) {
+		headers := make(chan http.Header, 1)
+		srv := httptest.NewServer(healthHandler(t, headers))
+		t.Cleanup(srv.Close)
+
+		c, err := NewClientWithHTTPOptions("chain", srv.URL, 0, HTTPOptions{
+			Headers:  http.Header{"X-Api-Key": []string{"secret-key"}},
+			Username: "user",
+			Password: "pass",
+		}, logger.Test(t))
+		require.NoError(t, err)
+		_, err = c.tmClient.Health(ctx)
+		require.NoError(t, err)
+
+		h := <-headers
+		assert.Equal(t, "secret-key", h.Get("X-Api-Key"))
+		req := http.Request{Header: h}
+		user, pass, ok := req.BasicAuth()
+		require.True(t, ok)
+		assert.Equal(t, "user", user)
+		assert.Equal(t, "pass", pass)
+	})
+
+	t.Run("tls root cas", func(t *testing.T) {
+		headers := make(chan http.Header, 1)
+		srv := httptest.NewTLSServer(healthHandler(t, headers))
+		t.Cleanup(srv.Close)
+
+		c, err := NewClient("chain", srv.URL, 0, logger.Test(t))
+		require.NoError(t, err)
+		_, err = c.tmClient.Health(ctx)
+		require.ErrorContains(t, err, "certificate", "the test server is not trusted by default")
+
+		rootCAs := x509.NewCertPool()
+		rootCAs.AddCert(srv.Certificate())
+		c, err = NewClientWithHTTPOptions("chain", srv.URL, 0, HTTPOptions{RootCAs: rootCAs}, logger.Test(t))
+		require.NoError(t, err)
+		_, err = c.tmClient.Health(ctx)
+		require.NoError(t, err)
+		<-headers
+	})
+}
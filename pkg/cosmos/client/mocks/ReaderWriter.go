@@ -3,9 +3,12 @@
 package mocks
 
 import (
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	client "github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
+
 	context "context"
 
-	client "github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
 
 	cosmos_sdkclient "github.com/cosmos/cosmos-sdk/client"
 
@@ -15,6 +18,8 @@ import (
 
 	query "github.com/cosmos/cosmos-sdk/types/query"
 
+	time "time"
+
 	tmservice "github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
 
 	tx "github.com/cosmos/cosmos-sdk/types/tx"
@@ -62,6 +67,41 @@ func (_m *ReaderWriter) Account(ctx context.Context, address types.AccAddress) (
 	return r0, r1, r2
 }
 
+// AccountAtHeight provides a mock function with given fields: ctx, address, height
+func (_m *ReaderWriter) AccountAtHeight(ctx context.Context, address types.AccAddress, height int64) (uint64, uint64, error) {
+	ret := _m.Called(ctx, address, height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AccountAtHeight")
+	}
+
+	var r0 uint64
+	var r1 uint64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.AccAddress, int64) (uint64, uint64, error)); ok {
+		return rf(ctx, address, height)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.AccAddress, int64) uint64); ok {
+		r0 = rf(ctx, address, height)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.AccAddress, int64) uint64); ok {
+		r1 = rf(ctx, address, height)
+	} else {
+		r1 = ret.Get(1).(uint64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, types.AccAddress, int64) error); ok {
+		r2 = rf(ctx, address, height)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Balance provides a mock function with given fields: ctx, addr, denom
 func (_m *ReaderWriter) Balance(ctx context.Context, addr types.AccAddress, denom string) (*types.Coin, error) {
 	ret := _m.Called(ctx, addr, denom)
@@ -92,6 +132,66 @@ func (_m *ReaderWriter) Balance(ctx context.Context, addr types.AccAddress, deno
 	return r0, r1
 }
 
+// BalanceAtHeight provides a mock function with given fields: ctx, addr, denom, height
+func (_m *ReaderWriter) BalanceAtHeight(ctx context.Context, addr types.AccAddress, denom string, height int64) (*types.Coin, error) {
+	ret := _m.Called(ctx, addr, denom, height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BalanceAtHeight")
+	}
+
+	var r0 *types.Coin
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.AccAddress, string, int64) (*types.Coin, error)); ok {
+		return rf(ctx, addr, denom, height)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.AccAddress, string, int64) *types.Coin); ok {
+		r0 = rf(ctx, addr, denom, height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Coin)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.AccAddress, string, int64) error); ok {
+		r1 = rf(ctx, addr, denom, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BatchSimulateUnordered provides a mock function with given fields: ctx, msgs, timeoutTimestamp
+func (_m *ReaderWriter) BatchSimulateUnordered(ctx context.Context, msgs client.SimMsgs, timeoutTimestamp time.Time) (*client.BatchSimResults, error) {
+	ret := _m.Called(ctx, msgs, timeoutTimestamp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BatchSimulateUnordered")
+	}
+
+	var r0 *client.BatchSimResults
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, client.SimMsgs, time.Time) (*client.BatchSimResults, error)); ok {
+		return rf(ctx, msgs, timeoutTimestamp)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, client.SimMsgs, time.Time) *client.BatchSimResults); ok {
+		r0 = rf(ctx, msgs, timeoutTimestamp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.BatchSimResults)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, client.SimMsgs, time.Time) error); ok {
+		r1 = rf(ctx, msgs, timeoutTimestamp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // BatchSimulateUnsigned provides a mock function with given fields: ctx, msgs, sequence
 func (_m *ReaderWriter) BatchSimulateUnsigned(ctx context.Context, msgs client.SimMsgs, sequence uint64) (*client.BatchSimResults, error) {
 	ret := _m.Called(ctx, msgs, sequence)
@@ -122,6 +222,34 @@ func (_m *ReaderWriter) BatchSimulateUnsigned(ctx context.Context, msgs client.S
 	return r0, r1
 }
 
+// Bech32Prefix provides a mock function with given fields: ctx
+func (_m *ReaderWriter) Bech32Prefix(ctx context.Context) (string, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Bech32Prefix")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (string, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) string); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // BlockByHeight provides a mock function with given fields: ctx, height
 func (_m *ReaderWriter) BlockByHeight(ctx context.Context, height int64) (*tmservice.GetBlockByHeightResponse, error) {
 	ret := _m.Called(ctx, height)
@@ -152,6 +280,94 @@ func (_m *ReaderWriter) BlockByHeight(ctx context.Context, height int64) (*tmser
 	return r0, r1
 }
 
+// BlockResults provides a mock function with given fields: ctx, height
+func (_m *ReaderWriter) BlockResults(ctx context.Context, height int64) (*coretypes.ResultBlockResults, error) {
+	ret := _m.Called(ctx, height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BlockResults")
+	}
+
+	var r0 *coretypes.ResultBlockResults
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*coretypes.ResultBlockResults, error)); ok {
+		return rf(ctx, height)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *coretypes.ResultBlockResults); ok {
+		r0 = rf(ctx, height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coretypes.ResultBlockResults)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BlockSearch provides a mock function with given fields: ctx, _a1, page, perPage, orderBy
+func (_m *ReaderWriter) BlockSearch(ctx context.Context, _a1 string, page int, perPage int, orderBy string) (*coretypes.ResultBlockSearch, error) {
+	ret := _m.Called(ctx, _a1, page, perPage, orderBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BlockSearch")
+	}
+
+	var r0 *coretypes.ResultBlockSearch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int, string) (*coretypes.ResultBlockSearch, error)); ok {
+		return rf(ctx, _a1, page, perPage, orderBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int, string) *coretypes.ResultBlockSearch); ok {
+		r0 = rf(ctx, _a1, page, perPage, orderBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coretypes.ResultBlockSearch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int, string) error); ok {
+		r1 = rf(ctx, _a1, page, perPage, orderBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BondDenom provides a mock function with given fields: ctx
+func (_m *ReaderWriter) BondDenom(ctx context.Context) (string, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BondDenom")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (string, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) string); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Broadcast provides a mock function with given fields: ctx, txBytes, mode
 func (_m *ReaderWriter) Broadcast(ctx context.Context, txBytes []byte, mode tx.BroadcastMode) (*tx.BroadcastTxResponse, error) {
 	ret := _m.Called(ctx, txBytes, mode)
@@ -202,6 +418,36 @@ func (_m *ReaderWriter) Context() *cosmos_sdkclient.Context {
 	return r0
 }
 
+// ContractRawState provides a mock function with given fields: ctx, contractAddress, key, height
+func (_m *ReaderWriter) ContractRawState(ctx context.Context, contractAddress types.AccAddress, key []byte, height int64) ([]byte, error) {
+	ret := _m.Called(ctx, contractAddress, key, height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContractRawState")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.AccAddress, []byte, int64) ([]byte, error)); ok {
+		return rf(ctx, contractAddress, key, height)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.AccAddress, []byte, int64) []byte); ok {
+		r0 = rf(ctx, contractAddress, key, height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.AccAddress, []byte, int64) error); ok {
+		r1 = rf(ctx, contractAddress, key, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ContractState provides a mock function with given fields: ctx, contractAddress, queryMsg
 func (_m *ReaderWriter) ContractState(ctx context.Context, contractAddress types.AccAddress, queryMsg []byte) ([]byte, error) {
 	ret := _m.Called(ctx, contractAddress, queryMsg)
@@ -232,9 +478,39 @@ func (_m *ReaderWriter) ContractState(ctx context.Context, contractAddress types
 	return r0, r1
 }
 
-// CreateAndSign provides a mock function with given fields: msgs, account, sequence, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutHeight
-func (_m *ReaderWriter) CreateAndSign(msgs []types.Msg, account uint64, sequence uint64, gasLimit uint64, gasLimitMultiplier float64, gasPrice types.DecCoin, signer cryptotypes.PrivKey, timeoutHeight uint64) ([]byte, error) {
-	ret := _m.Called(msgs, account, sequence, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutHeight)
+// ContractStateAtHeight provides a mock function with given fields: ctx, contractAddress, queryMsg, height
+func (_m *ReaderWriter) ContractStateAtHeight(ctx context.Context, contractAddress types.AccAddress, queryMsg []byte, height int64) ([]byte, error) {
+	ret := _m.Called(ctx, contractAddress, queryMsg, height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContractStateAtHeight")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.AccAddress, []byte, int64) ([]byte, error)); ok {
+		return rf(ctx, contractAddress, queryMsg, height)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.AccAddress, []byte, int64) []byte); ok {
+		r0 = rf(ctx, contractAddress, queryMsg, height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.AccAddress, []byte, int64) error); ok {
+		r1 = rf(ctx, contractAddress, queryMsg, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAndSign provides a mock function with given fields: msgs, account, sequence, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutHeight, memo
+func (_m *ReaderWriter) CreateAndSign(msgs []types.Msg, account uint64, sequence uint64, gasLimit uint64, gasLimitMultiplier float64, gasPrice types.DecCoin, signer cryptotypes.PrivKey, timeoutHeight uint64, memo string) ([]byte, error) {
+	ret := _m.Called(msgs, account, sequence, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutHeight, memo)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateAndSign")
@@ -242,19 +518,109 @@ func (_m *ReaderWriter) CreateAndSign(msgs []types.Msg, account uint64, sequence
 
 	var r0 []byte
 	var r1 error
-	if rf, ok := ret.Get(0).(func([]types.Msg, uint64, uint64, uint64, float64, types.DecCoin, cryptotypes.PrivKey, uint64) ([]byte, error)); ok {
-		return rf(msgs, account, sequence, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutHeight)
+	if rf, ok := ret.Get(0).(func([]types.Msg, uint64, uint64, uint64, float64, types.DecCoin, cryptotypes.PrivKey, uint64, string) ([]byte, error)); ok {
+		return rf(msgs, account, sequence, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutHeight, memo)
 	}
-	if rf, ok := ret.Get(0).(func([]types.Msg, uint64, uint64, uint64, float64, types.DecCoin, cryptotypes.PrivKey, uint64) []byte); ok {
-		r0 = rf(msgs, account, sequence, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutHeight)
+	if rf, ok := ret.Get(0).(func([]types.Msg, uint64, uint64, uint64, float64, types.DecCoin, cryptotypes.PrivKey, uint64, string) []byte); ok {
+		r0 = rf(msgs, account, sequence, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutHeight, memo)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]byte)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func([]types.Msg, uint64, uint64, uint64, float64, types.DecCoin, cryptotypes.PrivKey, uint64) error); ok {
-		r1 = rf(msgs, account, sequence, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutHeight)
+	if rf, ok := ret.Get(1).(func([]types.Msg, uint64, uint64, uint64, float64, types.DecCoin, cryptotypes.PrivKey, uint64, string) error); ok {
+		r1 = rf(msgs, account, sequence, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutHeight, memo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAndSignMultisig provides a mock function with given fields: msgs, account, sequence, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutHeight, memo
+func (_m *ReaderWriter) CreateAndSignMultisig(msgs []types.Msg, account uint64, sequence uint64, gasLimit uint64, gasLimitMultiplier float64, gasPrice types.DecCoin, signer client.MultisigSigner, timeoutHeight uint64, memo string) ([]byte, error) {
+	ret := _m.Called(msgs, account, sequence, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutHeight, memo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAndSignMultisig")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]types.Msg, uint64, uint64, uint64, float64, types.DecCoin, client.MultisigSigner, uint64, string) ([]byte, error)); ok {
+		return rf(msgs, account, sequence, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutHeight, memo)
+	}
+	if rf, ok := ret.Get(0).(func([]types.Msg, uint64, uint64, uint64, float64, types.DecCoin, client.MultisigSigner, uint64, string) []byte); ok {
+		r0 = rf(msgs, account, sequence, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutHeight, memo)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]types.Msg, uint64, uint64, uint64, float64, types.DecCoin, client.MultisigSigner, uint64, string) error); ok {
+		r1 = rf(msgs, account, sequence, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutHeight, memo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAndSignUnordered provides a mock function with given fields: msgs, account, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutTimestamp, memo
+func (_m *ReaderWriter) CreateAndSignUnordered(msgs []types.Msg, account uint64, gasLimit uint64, gasLimitMultiplier float64, gasPrice types.DecCoin, signer cryptotypes.PrivKey, timeoutTimestamp time.Time, memo string) ([]byte, error) {
+	ret := _m.Called(msgs, account, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutTimestamp, memo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAndSignUnordered")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]types.Msg, uint64, uint64, float64, types.DecCoin, cryptotypes.PrivKey, time.Time, string) ([]byte, error)); ok {
+		return rf(msgs, account, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutTimestamp, memo)
+	}
+	if rf, ok := ret.Get(0).(func([]types.Msg, uint64, uint64, float64, types.DecCoin, cryptotypes.PrivKey, time.Time, string) []byte); ok {
+		r0 = rf(msgs, account, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutTimestamp, memo)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]types.Msg, uint64, uint64, float64, types.DecCoin, cryptotypes.PrivKey, time.Time, string) error); ok {
+		r1 = rf(msgs, account, gasLimit, gasLimitMultiplier, gasPrice, signer, timeoutTimestamp, memo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DenomMetadata provides a mock function with given fields: ctx, denom
+func (_m *ReaderWriter) DenomMetadata(ctx context.Context, denom string) (*banktypes.Metadata, error) {
+	ret := _m.Called(ctx, denom)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DenomMetadata")
+	}
+
+	var r0 *banktypes.Metadata
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*banktypes.Metadata, error)); ok {
+		return rf(ctx, denom)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *banktypes.Metadata); ok {
+		r0 = rf(ctx, denom)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*banktypes.Metadata)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, denom)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -292,6 +658,66 @@ func (_m *ReaderWriter) LatestBlock(_a0 context.Context) (*tmservice.GetLatestBl
 	return r0, r1
 }
 
+// MinimumGasPrices provides a mock function with given fields: ctx
+func (_m *ReaderWriter) MinimumGasPrices(ctx context.Context) (types.DecCoins, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MinimumGasPrices")
+	}
+
+	var r0 types.DecCoins
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (types.DecCoins, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) types.DecCoins); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(types.DecCoins)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NodeInfo provides a mock function with given fields: ctx
+func (_m *ReaderWriter) NodeInfo(ctx context.Context) (*tmservice.GetNodeInfoResponse, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NodeInfo")
+	}
+
+	var r0 *tmservice.GetNodeInfoResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*tmservice.GetNodeInfoResponse, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *tmservice.GetNodeInfoResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*tmservice.GetNodeInfoResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SignAndBroadcast provides a mock function with given fields: ctx, msgs, accountNum, sequence, gasPrice, signer, mode
 func (_m *ReaderWriter) SignAndBroadcast(ctx context.Context, msgs []types.Msg, accountNum uint64, sequence uint64, gasPrice types.DecCoin, signer cryptotypes.PrivKey, mode tx.BroadcastMode) (*tx.BroadcastTxResponse, error) {
 	ret := _m.Called(ctx, msgs, accountNum, sequence, gasPrice, signer, mode)
@@ -352,6 +778,36 @@ func (_m *ReaderWriter) Simulate(ctx context.Context, txBytes []byte) (*tx.Simul
 	return r0, r1
 }
 
+// SimulateUnordered provides a mock function with given fields: ctx, msgs, timeoutTimestamp
+func (_m *ReaderWriter) SimulateUnordered(ctx context.Context, msgs []types.Msg, timeoutTimestamp time.Time) (*tx.SimulateResponse, error) {
+	ret := _m.Called(ctx, msgs, timeoutTimestamp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SimulateUnordered")
+	}
+
+	var r0 *tx.SimulateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []types.Msg, time.Time) (*tx.SimulateResponse, error)); ok {
+		return rf(ctx, msgs, timeoutTimestamp)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []types.Msg, time.Time) *tx.SimulateResponse); ok {
+		r0 = rf(ctx, msgs, timeoutTimestamp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*tx.SimulateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []types.Msg, time.Time) error); ok {
+		r1 = rf(ctx, msgs, timeoutTimestamp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SimulateUnsigned provides a mock function with given fields: ctx, msgs, sequence
 func (_m *ReaderWriter) SimulateUnsigned(ctx context.Context, msgs []types.Msg, sequence uint64) (*tx.SimulateResponse, error) {
 	ret := _m.Called(ctx, msgs, sequence)
@@ -0,0 +1,121 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	mock "github.com/stretchr/testify/mock"
+
+	types "github.com/cometbft/cometbft/types"
+)
+
+// Subscriber is an autogenerated mock type for the Subscriber type
+type Subscriber struct {
+	mock.Mock
+}
+
+// Subscribe provides a mock function with given fields: ctx, query
+func (_m *Subscriber) Subscribe(ctx context.Context, query string) (<-chan coretypes.ResultEvent, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribe")
+	}
+
+	var r0 <-chan coretypes.ResultEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (<-chan coretypes.ResultEvent, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan coretypes.ResultEvent); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan coretypes.ResultEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubscribeNewBlocks provides a mock function with given fields: ctx
+func (_m *Subscriber) SubscribeNewBlocks(ctx context.Context) (<-chan types.EventDataNewBlock, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeNewBlocks")
+	}
+
+	var r0 <-chan types.EventDataNewBlock
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (<-chan types.EventDataNewBlock, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) <-chan types.EventDataNewBlock); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan types.EventDataNewBlock)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubscribeTxs provides a mock function with given fields: ctx, query
+func (_m *Subscriber) SubscribeTxs(ctx context.Context, query string) (<-chan types.EventDataTx, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeTxs")
+	}
+
+	var r0 <-chan types.EventDataTx
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (<-chan types.EventDataTx, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan types.EventDataTx); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan types.EventDataTx)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSubscriber creates a new instance of Subscriber. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSubscriber(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Subscriber {
+	mock := &Subscriber{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
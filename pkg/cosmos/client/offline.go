@@ -0,0 +1,132 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
+)
+
+// offlineSignMode is the sign mode of txs signed offline. Unlike the direct sign bytes, the legacy amino JSON
+// sign bytes do not include the public key of the signer, and can be reviewed and signed by hardware wallets.
+const offlineSignMode = signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON
+
+// UnsignedTx is a tx exported to be signed offline, such as by a cold wallet, and broadcast later with Sign.
+// It has everything needed to sign the tx, so the signer does not need to query the chain.
+type UnsignedTx struct {
+	ChainID       string    `json:"chain_id"`
+	Sender        string    `json:"sender"`
+	AccountNumber uint64    `json:"account_number"`
+	Sequence      uint64    `json:"sequence"`
+	GasLimit      uint64    `json:"gas_limit"`
+	Fee           sdk.Coins `json:"fee"`
+	// TimeoutHeight is the last height at which the tx may be included, or 0 if unset.
+	TimeoutHeight uint64 `json:"timeout_height"`
+	// SignDoc is the legacy amino JSON document which must be signed.
+	SignDoc string `json:"sign_doc"`
+	// Tx is the unsigned tx, in the JSON encoding of the sdk.
+	Tx json.RawMessage `json:"tx"`
+}
+
+// NewUnsignedTx returns an unsigned tx of msgs from sender, built like CreateAndSign builds its txs.
+func NewUnsignedTx(chainID, sender string, msgs []sdk.Msg, account uint64, sequence uint64, gasLimit uint64, gasLimitMultiplier float64, gasPrice sdk.DecCoin, timeoutHeight uint64, memo string) (*UnsignedTx, error) {
+	txConfig := params.ClientTxConfig()
+	txBuilder, err := newTxBuilder(txConfig, msgs, gasLimit, gasLimitMultiplier, gasPrice, timeoutHeight, memo)
+	if err != nil {
+		return nil, err
+	}
+	tx := txBuilder.GetTx()
+	signBytes, err := txConfig.SignModeHandler().GetSignBytes(offlineSignMode, authsigning.SignerData{
+		Address:       sender,
+		AccountNumber: account,
+		ChainID:       chainID,
+		Sequence:      sequence,
+	}, tx)
+	if err != nil {
+		return nil, err
+	}
+	txJSON, err := txConfig.TxJSONEncoder()(tx)
+	if err != nil {
+		return nil, err
+	}
+	return &UnsignedTx{
+		ChainID:       chainID,
+		Sender:        sender,
+		AccountNumber: account,
+		Sequence:      sequence,
+		GasLimit:      tx.GetGas(),
+		Fee:           tx.GetFee(),
+		TimeoutHeight: timeoutHeight,
+		SignDoc:       string(signBytes),
+		Tx:            txJSON,
+	}, nil
+}
+
+// BuildUnsignedTx simulates msgs from sender, and returns an unsigned tx at its current sequence,
+// which pays for the simulated gas, buffered by gasLimitMultiplier, at gasPrice.
+func (c *Client) BuildUnsignedTx(ctx context.Context, sender sdk.AccAddress, msgs []sdk.Msg, gasLimitMultiplier float64, gasPrice sdk.DecCoin, timeoutHeight uint64, memo string) (*UnsignedTx, error) {
+	an, sn, err := c.Account(ctx, sender)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read account: %w", err)
+	}
+	sim, err := c.SimulateUnsigned(ctx, msgs, sn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate: %w", err)
+	}
+	return NewUnsignedTx(c.chainID, c.address(sender), msgs, an, sn, sim.GasInfo.GasUsed, gasLimitMultiplier, gasPrice, timeoutHeight, memo)
+}
+
+// Sign returns the encoded tx, signed with signature, which pubKey produced by signing the SignDoc.
+// The sign bytes are derived from the Tx again, so that the signature covers the tx which is broadcast.
+func (u *UnsignedTx) Sign(pubKey cryptotypes.PubKey, signature []byte) ([]byte, error) {
+	_, sender, err := bech32.DecodeAndConvert(u.Sender)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender: %w", err)
+	}
+	if !bytes.Equal(pubKey.Address(), sender) {
+		return nil, fmt.Errorf("public key is not the key of sender %s", u.Sender)
+	}
+
+	txConfig := params.ClientTxConfig()
+	tx, err := txConfig.TxJSONDecoder()(u.Tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode tx: %w", err)
+	}
+	txBuilder, err := txConfig.WrapTxBuilder(tx)
+	if err != nil {
+		return nil, err
+	}
+	signBytes, err := txConfig.SignModeHandler().GetSignBytes(offlineSignMode, authsigning.SignerData{
+		Address:       u.Sender,
+		AccountNumber: u.AccountNumber,
+		ChainID:       u.ChainID,
+		Sequence:      u.Sequence,
+	}, txBuilder.GetTx())
+	if err != nil {
+		return nil, err
+	}
+	if string(signBytes) != u.SignDoc {
+		return nil, errors.New("tx does not match the sign doc")
+	}
+	if !pubKey.VerifySignature(signBytes, signature) {
+		return nil, errors.New("invalid signature")
+	}
+
+	if err = txBuilder.SetSignatures(signing.SignatureV2{
+		PubKey:   pubKey,
+		Data:     &signing.SingleSignatureData{SignMode: offlineSignMode, Signature: signature},
+		Sequence: u.Sequence,
+	}); err != nil {
+		return nil, err
+	}
+	return txConfig.TxEncoder()(txBuilder.GetTx())
+}
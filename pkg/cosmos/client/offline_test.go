@@ -0,0 +1,81 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
+)
+
+func TestUnsignedTx(t *testing.T) {
+	key := secp256k1.GenPrivKey()
+	sender := sdk.AccAddress(key.PubKey().Address())
+	contract := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	msgs := []sdk.Msg{&wasmtypes.MsgExecuteContract{
+		Sender:   sender.String(),
+		Contract: contract.String(),
+		Msg:      []byte(`{"set_billing":{"config":{"recommended_gas_price_micro":"1"}}}`),
+	}}
+	gasPrice := sdk.NewDecCoinFromDec("ucosm", sdk.MustNewDecFromStr("0.01"))
+
+	unsigned, err := NewUnsignedTx("test-chain", sender.String(), msgs, 3, 5, 100_000, 1.5, gasPrice, 1234, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(150_000), unsigned.GasLimit)
+	assert.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("ucosm", 1500)), unsigned.Fee)
+	assert.Equal(t, uint64(1234), unsigned.TimeoutHeight)
+	assert.Contains(t, unsigned.SignDoc, `"account_number":"3"`)
+	assert.Contains(t, unsigned.SignDoc, `"sequence":"5"`)
+
+	// exported and imported again, as by the cli
+	b, err := json.MarshalIndent(unsigned, "", "  ")
+	require.NoError(t, err)
+	var imported UnsignedTx
+	require.NoError(t, json.Unmarshal(b, &imported))
+
+	signature, err := key.Sign([]byte(imported.SignDoc))
+	require.NoError(t, err)
+
+	t.Run("signed", func(t *testing.T) {
+		txBytes, err := imported.Sign(key.PubKey(), signature)
+		require.NoError(t, err)
+
+		txConfig := params.ClientTxConfig()
+		decoded, err := txConfig.TxDecoder()(txBytes)
+		require.NoError(t, err)
+		tx := decoded.(authsigning.Tx)
+		assert.Equal(t, "admin", tx.GetMemo())
+		assert.Equal(t, uint64(1234), tx.GetTimeoutHeight())
+		sigs, err := tx.GetSignaturesV2()
+		require.NoError(t, err)
+		require.Len(t, sigs, 1)
+		signerData := authsigning.SignerData{Address: sender.String(), ChainID: "test-chain", AccountNumber: 3, Sequence: 5, PubKey: key.PubKey()}
+		require.NoError(t, authsigning.VerifySignature(key.PubKey(), signerData, sigs[0].Data, txConfig.SignModeHandler(), tx))
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		_, err := imported.Sign(secp256k1.GenPrivKey().PubKey(), signature)
+		require.ErrorContains(t, err, "public key is not the key of sender")
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		other, err := key.Sign([]byte("other"))
+		require.NoError(t, err)
+		_, err = imported.Sign(key.PubKey(), other)
+		require.EqualError(t, err, "invalid signature")
+	})
+
+	t.Run("modified tx", func(t *testing.T) {
+		modified := imported
+		modified.Tx = bytes.Replace(imported.Tx, []byte(`"admin"`), []byte(`"other"`), 1)
+		_, err := modified.Sign(key.PubKey(), signature)
+		require.EqualError(t, err, "tx does not match the sign doc")
+	})
+}
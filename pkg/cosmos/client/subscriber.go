@@ -0,0 +1,337 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	cmtjson "github.com/cometbft/cometbft/libs/json"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/gorilla/websocket"
+)
+
+// Subscriber provides methods for subscribing to events from a cosmos chain.
+// Subscriptions connect with the HTTPOptions of the client, reconnect automatically, skip events which
+// were already delivered, and are closed once the context is done.
+// Events emitted while disconnected are not replayed.
+//
+//go:generate mockery --name Subscriber --output ./mocks/
+type Subscriber interface {
+	// Subscribe returns the events matching query, which follows the tendermint query language,
+	// e.g. "tm.event='Tx' AND wasm._contract_address='wasm1...'".
+	Subscribe(ctx context.Context, query string) (<-chan coretypes.ResultEvent, error)
+	// SubscribeNewBlocks returns each new block.
+	SubscribeNewBlocks(ctx context.Context) (<-chan cmttypes.EventDataNewBlock, error)
+	// SubscribeTxs returns the results of the txs matching query. An empty query matches all txs.
+	SubscribeTxs(ctx context.Context, query string) (<-chan cmttypes.EventDataTx, error)
+}
+
+var _ Subscriber = (*Client)(nil)
+
+const (
+	// subscriptionBufferSize is the number of events buffered per subscription.
+	subscriptionBufferSize = 100
+	// subscriptionDedupeSize is the number of recent events remembered per subscription to skip duplicates.
+	subscriptionDedupeSize = 1000
+	// subscriptionReadWait is how long a websocket may be silent before it is considered failed.
+	// Nodes ping every 27s by default.
+	subscriptionReadWait = time.Minute
+	// subscriptionWriteWait is how long writing a message to a websocket may take.
+	subscriptionWriteWait  = 10 * time.Second
+	subscriptionMinBackoff = time.Second
+	subscriptionMaxBackoff = 30 * time.Second
+)
+
+// Subscribe subscribes to the events matching query over the tendermint websocket.
+func (c *Client) Subscribe(ctx context.Context, query string) (<-chan coretypes.ResultEvent, error) {
+	sub, err := c.dialSubscription(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan coretypes.ResultEvent, subscriptionBufferSize)
+	go c.runSubscription(ctx, query, sub, out)
+	return out, nil
+}
+
+// SubscribeNewBlocks subscribes to new blocks over the tendermint websocket.
+func (c *Client) SubscribeNewBlocks(ctx context.Context) (<-chan cmttypes.EventDataNewBlock, error) {
+	events, err := c.Subscribe(ctx, cmttypes.EventQueryNewBlock.String())
+	if err != nil {
+		return nil, err
+	}
+	return eventData[cmttypes.EventDataNewBlock](ctx, events), nil
+}
+
+// SubscribeTxs subscribes to the txs matching query over the tendermint websocket.
+func (c *Client) SubscribeTxs(ctx context.Context, query string) (<-chan cmttypes.EventDataTx, error) {
+	txQuery := cmttypes.EventQueryTx.String()
+	if query != "" {
+		txQuery += " AND " + query
+	}
+	events, err := c.Subscribe(ctx, txQuery)
+	if err != nil {
+		return nil, err
+	}
+	return eventData[cmttypes.EventDataTx](ctx, events), nil
+}
+
+// eventData returns the data of events of type T.
+func eventData[T cmttypes.TMEventData](ctx context.Context, events <-chan coretypes.ResultEvent) <-chan T {
+	out := make(chan T, subscriptionBufferSize)
+	go func() {
+		defer close(out)
+		for event := range events {
+			data, ok := event.Data.(T)
+			if !ok {
+				continue
+			}
+			select {
+			case out <- data:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// wsSubscription is a subscription to query over its own websocket connection to the node.
+type wsSubscription struct {
+	query     string
+	conn      *websocket.Conn
+	responses <-chan rpctypes.RPCResponse // closed when the connection fails
+	closed    chan struct{}
+}
+
+// websocketURL returns the websocket endpoint of the node at tendermintURL, and the basic auth embedded in it, if any.
+func websocketURL(tendermintURL string) (string, *url.Userinfo, error) {
+	u, err := url.Parse(tendermintURL)
+	if err != nil {
+		return "", nil, err
+	}
+	// default to ws, unless the node is served over TLS
+	if u.Scheme == "https" || u.Scheme == "wss" {
+		u.Scheme = "wss"
+	} else {
+		u.Scheme = "ws"
+	}
+	user := u.User
+	u.User = nil // not allowed in websocket URLs
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/websocket"
+	return u.String(), user, nil
+}
+
+// dialSubscription connects to the websocket with the HTTPOptions of the client, and subscribes to query,
+// waiting for the node to confirm the subscription.
+func (c *Client) dialSubscription(ctx context.Context, query string) (*wsSubscription, error) {
+	wsURL, user, err := websocketURL(c.tendermintURL)
+	if err != nil {
+		return nil, err
+	}
+	// the handshake request is only used to collect the headers
+	handshake := &http.Request{Header: http.Header{}}
+	opts := c.httpOpts
+	if opts.Username == "" && user != nil {
+		opts.Username = user.Username()
+		opts.Password, _ = user.Password()
+	}
+	opts.setHeaders(handshake)
+	dialer := &websocket.Dialer{Proxy: http.ProxyFromEnvironment, HandshakeTimeout: subscriptionReadWait,
+		TLSClientConfig: opts.tlsConfig()}
+	conn, resp, err := dialer.DialContext(ctx, wsURL, handshake.Header)
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to websocket: %w", err)
+	}
+
+	sub := &wsSubscription{query: query, conn: conn, closed: make(chan struct{})}
+	if err = sub.call("subscribe"); err != nil {
+		sub.close()
+		return nil, fmt.Errorf("failed to subscribe to %q: %w", query, err)
+	}
+	responses := sub.readResponses()
+	select {
+	case resp, ok := <-responses:
+		if !ok {
+			sub.close()
+			return nil, errors.New("websocket closed before the subscription was confirmed")
+		}
+		if resp.Error != nil {
+			sub.close()
+			return nil, fmt.Errorf("failed to subscribe to %q: %w", query, resp.Error)
+		}
+	case <-ctx.Done():
+		sub.close()
+		return nil, ctx.Err()
+	}
+	sub.responses = responses
+	return sub, nil
+}
+
+// call sends a request for method with the query of the subscription.
+func (s *wsSubscription) call(method string) error {
+	req, err := rpctypes.MapToRequest(rpctypes.JSONRPCIntID(0), method, map[string]interface{}{"query": s.query})
+	if err != nil {
+		return err
+	}
+	if err = s.conn.SetWriteDeadline(time.Now().Add(subscriptionWriteWait)); err != nil {
+		return err
+	}
+	return s.conn.WriteJSON(req)
+}
+
+// readResponses reads responses until the connection fails or is closed. The node pings the connection
+// periodically, so it is considered failed once nothing has been read for subscriptionReadWait.
+func (s *wsSubscription) readResponses() <-chan rpctypes.RPCResponse {
+	out := make(chan rpctypes.RPCResponse, subscriptionBufferSize)
+	extendDeadline := func() error { return s.conn.SetReadDeadline(time.Now().Add(subscriptionReadWait)) }
+	s.conn.SetPingHandler(func(data string) error {
+		if err := extendDeadline(); err != nil {
+			return err
+		}
+		return s.conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(subscriptionWriteWait))
+	})
+	go func() {
+		defer close(out)
+		for {
+			if err := extendDeadline(); err != nil {
+				return
+			}
+			var resp rpctypes.RPCResponse
+			if err := s.conn.ReadJSON(&resp); err != nil {
+				return
+			}
+			select {
+			case out <- resp:
+			case <-s.closed:
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// close closes the connection, without unsubscribing.
+func (s *wsSubscription) close() {
+	close(s.closed)
+	_ = s.conn.Close()
+}
+
+// runSubscription forwards the events from sub to out until ctx is done, restarting the subscription
+// on a new websocket, with backoff, whenever the connection fails.
+func (c *Client) runSubscription(ctx context.Context, query string, sub *wsSubscription, out chan<- coretypes.ResultEvent) {
+	defer close(out)
+	seen := newEventDedupe(subscriptionDedupeSize)
+	for {
+		c.forwardEvents(ctx, sub, seen, out)
+		c.closeSubscription(sub)
+		if ctx.Err() != nil {
+			return
+		}
+
+		backoff := subscriptionMinBackoff
+		for {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return
+			}
+			var err error
+			sub, err = c.dialSubscription(ctx, query)
+			if err == nil {
+				break
+			}
+			c.log.Warnw("failed to restart subscription", "query", query, "backoff", backoff, "err", err)
+			backoff = min(2*backoff, subscriptionMaxBackoff)
+		}
+	}
+}
+
+// forwardEvents forwards new events from sub to out, and returns once ctx is done or the connection fails.
+func (c *Client) forwardEvents(ctx context.Context, sub *wsSubscription, seen *eventDedupe, out chan<- coretypes.ResultEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case resp, ok := <-sub.responses:
+			if !ok {
+				c.log.Warnw("websocket closed, restarting subscription", "query", sub.query)
+				return
+			}
+			if resp.Error != nil {
+				c.log.Warnw("subscription failed, restarting subscription", "query", sub.query, "err", resp.Error)
+				return
+			}
+			var event coretypes.ResultEvent
+			if err := cmtjson.Unmarshal(resp.Result, &event); err != nil {
+				c.log.Errorw("failed to unmarshal event", "query", sub.query, "err", err)
+				continue
+			}
+			if key := eventKey(event); key != "" && !seen.add(key) {
+				continue
+			}
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// closeSubscription unsubscribes, if still connected, and closes the connection.
+func (c *Client) closeSubscription(sub *wsSubscription) {
+	if err := sub.call("unsubscribe"); err != nil {
+		c.log.Debugw("failed to unsubscribe", "query", sub.query, "err", err)
+	}
+	sub.close()
+}
+
+// eventKey identifies blocks and txs, so that they are only delivered once.
+// Other events are not deduplicated.
+func eventKey(event coretypes.ResultEvent) string {
+	switch data := event.Data.(type) {
+	case cmttypes.EventDataNewBlock:
+		if data.Block != nil {
+			return fmt.Sprintf("block/%d", data.Block.Height)
+		}
+	case cmttypes.EventDataNewBlockHeader:
+		return fmt.Sprintf("header/%d", data.Header.Height)
+	case cmttypes.EventDataTx:
+		return fmt.Sprintf("tx/%d/%d", data.Height, data.Index)
+	}
+	return ""
+}
+
+// eventDedupe remembers the most recent event keys.
+type eventDedupe struct {
+	keys []string
+	next int
+	seen map[string]struct{}
+}
+
+func newEventDedupe(size int) *eventDedupe {
+	return &eventDedupe{keys: make([]string, size), seen: make(map[string]struct{}, size)}
+}
+
+// add returns false if key was already seen, and otherwise remembers it, forgetting the oldest key.
+func (d *eventDedupe) add(key string) bool {
+	if _, ok := d.seen[key]; ok {
+		return false
+	}
+	if old := d.keys[d.next]; old != "" {
+		delete(d.seen, old)
+	}
+	d.keys[d.next] = key
+	d.next = (d.next + 1) % len(d.keys)
+	d.seen[key] = struct{}{}
+	return true
+}
@@ -171,8 +171,18 @@ func DeployTestContract(t *testing.T, tendermintURL, chainID string, token strin
 	require.True(t, success)
 
 	// get code id from tx receipt
-	storeCodeLog := storeTx.TxResponse.Logs[len(storeTx.TxResponse.Logs)-1]
-	codeID, err := strconv.ParseUint(storeCodeLog.GetEvents()[1].Attributes[1].Value, 10, 64)
+	var rawCodeID string
+	for _, event := range TxEvents(storeTx.TxResponse) {
+		if event.Type != wasmtypes.EventTypeStoreCode {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if attr.Key == wasmtypes.AttributeKeyCodeID {
+				rawCodeID = attr.Value
+			}
+		}
+	}
+	codeID, err := strconv.ParseUint(rawCodeID, 10, 64)
 	require.NoError(t, err, "failed to parse code id from tx receipt")
 
 	accountNumber, sequenceNumber, err := tc.Account(ctx, ownerAccount.Address)
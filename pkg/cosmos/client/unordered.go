@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
+)
+
+// Unordered txs are replay protected by their hash until their timeout timestamp, instead of by the account sequence,
+// so that an account can send them concurrently. They were added to the TxBody in cosmos-sdk v0.53, after the
+// version of this module, so the fields are encoded here.
+const (
+	txBodyUnorderedField        protowire.Number = 4
+	txBodyTimeoutTimestampField protowire.Number = 5
+)
+
+// BatchSimulateUnordered is like BatchSimulateUnsigned, but simulates unordered txs.
+func (c *Client) BatchSimulateUnordered(ctx context.Context, msgs SimMsgs, timeoutTimestamp time.Time) (*BatchSimResults, error) {
+	return c.batchSimulate(ctx, msgs, func(ctx context.Context, msgs []sdk.Msg) (uint64, error) {
+		return gasUsed(c.SimulateUnordered(ctx, msgs, timeoutTimestamp))
+	})
+}
+
+// SimulateUnordered is like SimulateUnsigned, but simulates an unordered tx, which times out at timeoutTimestamp.
+func (c *Client) SimulateUnordered(ctx context.Context, msgs []sdk.Msg, timeoutTimestamp time.Time) (*txtypes.SimulateResponse, error) {
+	// the sequence of unordered txs is not checked
+	txBytes, err := c.encodeUnsigned(msgs, 0)
+	if err != nil {
+		return nil, err
+	}
+	var raw txtypes.TxRaw
+	if err = raw.Unmarshal(txBytes); err != nil {
+		return nil, err
+	}
+	raw.BodyBytes = appendUnordered(raw.BodyBytes, timeoutTimestamp)
+	if txBytes, err = raw.Marshal(); err != nil {
+		return nil, err
+	}
+	return c.Simulate(ctx, txBytes)
+}
+
+// CreateAndSignUnordered is like CreateAndSign, but creates an unordered tx, which times out at timeoutTimestamp
+// instead of a height. Unordered txs can only be signed in SIGN_MODE_DIRECT, since the legacy amino JSON
+// sign doc has no fields for them.
+func (c *Client) CreateAndSignUnordered(msgs []sdk.Msg, account uint64, gasLimit uint64, gasLimitMultiplier float64, gasPrice sdk.DecCoin, signer cryptotypes.PrivKey, timeoutTimestamp time.Time, memo string) ([]byte, error) {
+	if c.signMode != signing.SignMode_SIGN_MODE_DIRECT {
+		return nil, errors.New("unordered txs must be signed in SIGN_MODE_DIRECT")
+	}
+	txConfig := params.ClientTxConfig()
+	txBuilder, err := newTxBuilder(txConfig, msgs, gasLimit, gasLimitMultiplier, gasPrice, 0, memo)
+	if err != nil {
+		return nil, err
+	}
+	// sets the signer infos of the auth info, which are signed
+	if err = txBuilder.SetSignatures(signing.SignatureV2{
+		PubKey:   signer.PubKey(),
+		Data:     &signing.SingleSignatureData{SignMode: signing.SignMode_SIGN_MODE_DIRECT},
+		Sequence: 0,
+	}); err != nil {
+		return nil, err
+	}
+	txBytes, err := txConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		return nil, err
+	}
+	var raw txtypes.TxRaw
+	if err = raw.Unmarshal(txBytes); err != nil {
+		return nil, err
+	}
+	raw.BodyBytes = appendUnordered(raw.BodyBytes, timeoutTimestamp)
+
+	signDoc := txtypes.SignDoc{
+		BodyBytes:     raw.BodyBytes,
+		AuthInfoBytes: raw.AuthInfoBytes,
+		ChainId:       c.chainID,
+		AccountNumber: account,
+	}
+	signBytes, err := signDoc.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signer.Sign(signBytes)
+	if err != nil {
+		return nil, err
+	}
+	raw.Signatures = [][]byte{signature}
+	return raw.Marshal()
+}
+
+// appendUnordered appends the unordered and timeout_timestamp fields to the encoding of a TxBody. The body must not
+// have extension options, so that the fields stay in ascending order, as required by ADR-027.
+func appendUnordered(body []byte, timeoutTimestamp time.Time) []byte {
+	var ts []byte
+	if seconds := timeoutTimestamp.Unix(); seconds != 0 {
+		ts = protowire.AppendTag(ts, 1, protowire.VarintType)
+		ts = protowire.AppendVarint(ts, uint64(seconds))
+	}
+	if nanos := timeoutTimestamp.Nanosecond(); nanos != 0 {
+		ts = protowire.AppendTag(ts, 2, protowire.VarintType)
+		ts = protowire.AppendVarint(ts, uint64(nanos))
+	}
+	b := append([]byte{}, body...)
+	b = protowire.AppendTag(b, txBodyUnorderedField, protowire.VarintType)
+	b = protowire.AppendVarint(b, protowire.EncodeBool(true))
+	b = protowire.AppendTag(b, txBodyTimeoutTimestampField, protowire.BytesType)
+	return protowire.AppendBytes(b, ts)
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestClient_CreateAndSignUnordered(t *testing.T) {
+	key := secp256k1.GenPrivKey()
+	from := sdk.AccAddress(key.PubKey().Address())
+	send := banktypes.NewMsgSend(from, from, sdk.NewCoins(sdk.NewInt64Coin("ucosm", 1)))
+	gasPrice := sdk.NewDecCoinFromDec("ucosm", sdk.MustNewDecFromStr("0.01"))
+	timeout := time.Unix(1_700_000_000, 500)
+	c := &Client{chainID: "test-chain", signMode: signing.SignMode_SIGN_MODE_DIRECT}
+
+	txBytes, err := c.CreateAndSignUnordered([]sdk.Msg{send}, 3, 100_000, 1.5, gasPrice, key, timeout, "memo")
+	require.NoError(t, err)
+
+	var raw txtypes.TxRaw
+	require.NoError(t, raw.Unmarshal(txBytes))
+	signDoc := txtypes.SignDoc{BodyBytes: raw.BodyBytes, AuthInfoBytes: raw.AuthInfoBytes, ChainId: "test-chain", AccountNumber: 3}
+	signBytes, err := signDoc.Marshal()
+	require.NoError(t, err)
+	require.Len(t, raw.Signatures, 1)
+	assert.True(t, key.PubKey().VerifySignature(signBytes, raw.Signatures[0]))
+
+	// the fields known to this sdk version are unchanged
+	var body txtypes.TxBody
+	require.NoError(t, body.Unmarshal(raw.BodyBytes))
+	assert.Equal(t, "memo", body.Memo)
+	assert.Zero(t, body.TimeoutHeight)
+	var authInfo txtypes.AuthInfo
+	require.NoError(t, authInfo.Unmarshal(raw.AuthInfoBytes))
+	assert.Zero(t, authInfo.SignerInfos[0].Sequence)
+
+	// followed by the unordered fields
+	var unordered bool
+	var seconds, nanos uint64
+	b := raw.BodyBytes
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+		switch num {
+		case txBodyUnorderedField:
+			v, n := protowire.ConsumeVarint(b)
+			require.GreaterOrEqual(t, n, 0)
+			unordered = protowire.DecodeBool(v)
+		case txBodyTimeoutTimestampField:
+			ts, _ := protowire.ConsumeBytes(b)
+			seconds, nanos = consumeTimestamp(t, ts)
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+	}
+	assert.True(t, unordered)
+	assert.Equal(t, uint64(1_700_000_000), seconds)
+	assert.Equal(t, uint64(500), nanos)
+
+	c.SetSignMode(signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON)
+	_, err = c.CreateAndSignUnordered([]sdk.Msg{send}, 3, 100_000, 1.5, gasPrice, key, timeout, "memo")
+	require.EqualError(t, err, "unordered txs must be signed in SIGN_MODE_DIRECT")
+}
+
+func consumeTimestamp(t *testing.T, b []byte) (seconds, nanos uint64) {
+	for len(b) > 0 {
+		num, _, n := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+		v, n := protowire.ConsumeVarint(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+		if num == 1 {
+			seconds = v
+		} else {
+			nanos = v
+		}
+	}
+	return
+}
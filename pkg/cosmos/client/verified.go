@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/crypto/merkle"
+	"github.com/cometbft/cometbft/light"
+	"github.com/cometbft/cometbft/light/provider"
+	lighthttp "github.com/cometbft/cometbft/light/provider/http"
+	dbs "github.com/cometbft/cometbft/light/store/db"
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+var (
+	// ErrProofVerification is returned when a query response does not match its proof.
+	ErrProofVerification = errors.New("failed to verify query proof")
+	// ErrUnverifiable is returned for queries which cannot be verified, while verified reads are enabled.
+	ErrUnverifiable = errors.New("query result cannot be verified")
+)
+
+// HeaderVerifier returns headers which have been verified by a light client, e.g. *light.Client.
+type HeaderVerifier interface {
+	VerifyLightBlockAtHeight(ctx context.Context, height int64, now time.Time) (*cmttypes.LightBlock, error)
+}
+
+var _ HeaderVerifier = (*light.Client)(nil)
+
+// LightNode is a node which serves headers to a light client.
+type LightNode struct {
+	TendermintURL  string
+	RequestTimeout time.Duration
+	HTTPOptions    HTTPOptions
+}
+
+func (n LightNode) provider(chainID string) (provider.Provider, error) {
+	tmClient, err := newTendermintClient(n.TendermintURL, n.RequestTimeout, n.HTTPOptions)
+	if err != nil {
+		return nil, err
+	}
+	return lighthttp.NewWithClient(chainID, tmClient), nil
+}
+
+// NewLightClient returns a light client which verifies the headers served by primary, starting from the
+// header trusted by trustOptions, and cross-checks them against witnesses, of which there must be at least one.
+// Verified headers are kept in memory.
+func NewLightClient(ctx context.Context, chainID string, trustOptions light.TrustOptions, primary LightNode, witnesses []LightNode) (*light.Client, error) {
+	p, err := primary.provider(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create primary %s: %w", primary.TendermintURL, err)
+	}
+	ws := make([]provider.Provider, len(witnesses))
+	for i, w := range witnesses {
+		if ws[i], err = w.provider(chainID); err != nil {
+			return nil, fmt.Errorf("failed to create witness %s: %w", w.TendermintURL, err)
+		}
+	}
+	return light.NewClient(ctx, chainID, trustOptions, p, ws, dbs.New(dbm.NewMemDB(), chainID))
+}
+
+// EnableVerifiedReads makes the client verify the proofs of contract state reads against the app hashes
+// of headers returned by verifier, so that a single RPC node cannot forge results.
+// Smart contract queries execute contract code and have no proof, so ContractState and
+// ContractStateAtHeight fail with ErrUnverifiable, and ContractRawState must be used instead.
+// Other reads, such as of accounts, balances, txs and blocks, are not verified.
+// It must be called before the client is used.
+func (c *Client) EnableVerifiedReads(verifier HeaderVerifier) {
+	c.verifier = verifier
+}
+
+// ContractRawState reads the value stored under key by a WASM contract as of height.
+// A height of 0 means the latest state. The value is nil if the key is not set.
+// If verified reads are enabled, the value (or its absence) is checked against its merkle proof.
+func (c *Client) ContractRawState(ctx context.Context, contractAddress sdk.AccAddress, key []byte, height int64) ([]byte, error) {
+	if c.verifier == nil {
+		s, err := c.wasmClient.RawContractState(ContextWithHeight(ctx, height), &wasmtypes.QueryRawContractStateRequest{
+			Address:   c.address(contractAddress),
+			QueryData: key,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return s.Data, nil
+	}
+	storeKey := append(wasmtypes.GetContractStorePrefix(contractAddress), key...)
+	return c.verifiedStoreQuery(ctx, wasmtypes.StoreKey, storeKey, height)
+}
+
+// verifiedStoreQuery reads key from the store named storeName as of height, and verifies the result.
+func (c *Client) verifiedStoreQuery(ctx context.Context, storeName string, key []byte, height int64) ([]byte, error) {
+	if height == 0 {
+		// The app hash committing to the state at a height is only in the header of the next block,
+		// so read the state before the latest block.
+		latest, err := c.LatestBlock(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest block: %w", err)
+		}
+		if latest.SdkBlock == nil {
+			return nil, errors.New("latest block is missing")
+		}
+		height = latest.SdkBlock.Header.Height - 1
+	}
+	res, err := c.clientCtx.Client.ABCIQueryWithOptions(ctx, "/store/"+storeName+"/key", key, rpcclient.ABCIQueryOptions{
+		Height: height,
+		Prove:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := res.Response
+	if !resp.IsOK() {
+		return nil, fmt.Errorf("query failed with code %d: %s", resp.Code, resp.Log)
+	}
+	if resp.Height != height {
+		return nil, fmt.Errorf("%w: response height %d does not match query height %d", ErrProofVerification, resp.Height, height)
+	}
+	if resp.ProofOps == nil || len(resp.ProofOps.Ops) == 0 {
+		return nil, fmt.Errorf("%w: response has no proof", ErrProofVerification)
+	}
+
+	header, err := c.verifier.VerifyLightBlockAtHeight(ctx, height+1, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify header at height %d: %w", height+1, err)
+	}
+
+	keyPath := merkle.KeyPath{}.
+		AppendKey([]byte(storeName), merkle.KeyEncodingURL).
+		AppendKey(key, merkle.KeyEncodingURL).
+		String()
+	prt := rootmulti.DefaultProofRuntime()
+	if len(resp.Value) == 0 {
+		err = prt.VerifyAbsence(resp.ProofOps, header.AppHash, keyPath)
+	} else {
+		err = prt.VerifyValue(resp.ProofOps, header.AppHash, keyPath, resp.Value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProofVerification, err)
+	}
+	if len(resp.Value) == 0 {
+		return nil, nil
+	}
+	return resp.Value, nil
+}
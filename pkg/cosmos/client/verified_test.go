@@ -0,0 +1,126 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	dbm "github.com/cometbft/cometbft-db"
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
+	"github.com/cometbft/cometbft/libs/log"
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	cosmosclient "github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// standInNode serves ABCI store queries, with proofs, from an in-memory multistore.
+type standInNode struct {
+	cosmosclient.TendermintRPC
+	store  *rootmulti.Store
+	tamper func(*abci.ResponseQuery)
+}
+
+func (n *standInNode) ABCIQueryWithOptions(_ context.Context, path string, data cmtbytes.HexBytes, opts rpcclient.ABCIQueryOptions) (*coretypes.ResultABCIQuery, error) {
+	resp := n.store.Query(abci.RequestQuery{
+		Path:   strings.TrimPrefix(path, "/store"),
+		Data:   data,
+		Height: opts.Height,
+		Prove:  opts.Prove,
+	})
+	if n.tamper != nil {
+		n.tamper(&resp)
+	}
+	return &coretypes.ResultABCIQuery{Response: resp}, nil
+}
+
+// appHashes stands in for a light client, trusting the given app hash at each height.
+type appHashes map[int64][]byte
+
+func (h appHashes) VerifyLightBlockAtHeight(_ context.Context, height int64, _ time.Time) (*cmttypes.LightBlock, error) {
+	appHash, ok := h[height]
+	if !ok {
+		return nil, fmt.Errorf("no header at height %d", height)
+	}
+	return &cmttypes.LightBlock{SignedHeader: &cmttypes.SignedHeader{
+		Header: &cmttypes.Header{Height: height, AppHash: appHash},
+	}}, nil
+}
+
+func TestClient_ContractRawState_Verified(t *testing.T) {
+	ctx := context.Background()
+	contract := sdk.AccAddress(bytes.Repeat([]byte{0x01}, 32))
+	value := []byte(`{"f":1}`)
+
+	store := rootmulti.NewStore(dbm.NewMemDB(), log.NewNopLogger())
+	wasmKey := storetypes.NewKVStoreKey(wasmtypes.StoreKey)
+	store.MountStoreWithDB(wasmKey, storetypes.StoreTypeIAVL, nil)
+	require.NoError(t, store.LoadLatestVersion())
+	store.GetCommitKVStore(wasmKey).Set(append(wasmtypes.GetContractStorePrefix(contract), []byte("config")...), value)
+	commit := store.Commit()
+	// the header of the next block commits to the state
+	trusted := appHashes{commit.Version + 1: commit.Hash}
+
+	newClient := func(tamper func(*abci.ResponseQuery), verifier HeaderVerifier) *Client {
+		c := &Client{clientCtx: cosmosclient.Context{}.WithClient(&standInNode{store: store, tamper: tamper})}
+		c.EnableVerifiedReads(verifier)
+		return c
+	}
+
+	t.Run("value", func(t *testing.T) {
+		got, err := newClient(nil, trusted).ContractRawState(ctx, contract, []byte("config"), commit.Version)
+		require.NoError(t, err)
+		assert.Equal(t, value, got)
+	})
+
+	t.Run("absent", func(t *testing.T) {
+		got, err := newClient(nil, trusted).ContractRawState(ctx, contract, []byte("missing"), commit.Version)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("forged value", func(t *testing.T) {
+		c := newClient(func(resp *abci.ResponseQuery) { resp.Value = []byte(`{"f":2}`) }, trusted)
+		_, err := c.ContractRawState(ctx, contract, []byte("config"), commit.Version)
+		require.ErrorIs(t, err, ErrProofVerification)
+	})
+
+	t.Run("forged absence", func(t *testing.T) {
+		c := newClient(func(resp *abci.ResponseQuery) { resp.Value = nil }, trusted)
+		_, err := c.ContractRawState(ctx, contract, []byte("config"), commit.Version)
+		require.ErrorIs(t, err, ErrProofVerification)
+	})
+
+	t.Run("missing proof", func(t *testing.T) {
+		c := newClient(func(resp *abci.ResponseQuery) { resp.ProofOps = nil }, trusted)
+		_, err := c.ContractRawState(ctx, contract, []byte("config"), commit.Version)
+		require.ErrorIs(t, err, ErrProofVerification)
+	})
+
+	t.Run("untrusted app hash", func(t *testing.T) {
+		c := newClient(nil, appHashes{commit.Version + 1: bytes.Repeat([]byte{0xff}, 32)})
+		_, err := c.ContractRawState(ctx, contract, []byte("config"), commit.Version)
+		require.ErrorIs(t, err, ErrProofVerification)
+	})
+
+	t.Run("unverified header", func(t *testing.T) {
+		c := newClient(nil, appHashes{})
+		_, err := c.ContractRawState(ctx, contract, []byte("config"), commit.Version)
+		require.ErrorContains(t, err, "failed to verify header")
+	})
+
+	t.Run("smart query", func(t *testing.T) {
+		_, err := newClient(nil, trusted).ContractState(ctx, contract, []byte(`{"latest_config_details":{}}`))
+		require.ErrorIs(t, err, ErrUnverifiable)
+	})
+}
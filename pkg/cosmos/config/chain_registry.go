@@ -0,0 +1,133 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/goplugin/plugin-common/pkg/config"
+)
+
+// registryChain is the subset of a chain-registry chain.json which is used to bootstrap a TOMLConfig.
+// See https://github.com/cosmos/chain-registry/blob/master/chain.schema.json.
+type registryChain struct {
+	ChainName    string `json:"chain_name"`
+	ChainID      string `json:"chain_id"`
+	Bech32Prefix string `json:"bech32_prefix"`
+	Fees         struct {
+		FeeTokens []registryFeeToken `json:"fee_tokens"`
+	} `json:"fees"`
+	APIs struct {
+		RPC []registryEndpoint `json:"rpc"`
+	} `json:"apis"`
+}
+
+type registryFeeToken struct {
+	Denom            string           `json:"denom"`
+	FixedMinGasPrice *decimal.Decimal `json:"fixed_min_gas_price"`
+	LowGasPrice      *decimal.Decimal `json:"low_gas_price"`
+	AverageGasPrice  *decimal.Decimal `json:"average_gas_price"`
+	HighGasPrice     *decimal.Decimal `json:"high_gas_price"`
+}
+
+type registryEndpoint struct {
+	Address string `json:"address"`
+}
+
+// registryAssetList is the subset of a chain-registry assetlist.json which is used to check the fee token.
+// See https://github.com/cosmos/chain-registry/blob/master/assetlist.schema.json.
+type registryAssetList struct {
+	Assets []registryAsset `json:"assets"`
+}
+
+type registryAsset struct {
+	Base       string              `json:"base"`
+	Display    string              `json:"display"`
+	DenomUnits []registryDenomUnit `json:"denom_units"`
+}
+
+type registryDenomUnit struct {
+	Denom    string `json:"denom"`
+	Exponent uint32 `json:"exponent"`
+}
+
+// LoadChainRegistry reads a chain-registry chain.json and assetlist.json, and returns the equivalent config.
+// See NewTOMLConfigFromChainRegistry.
+func LoadChainRegistry(chainPath, assetListPath string) (*TOMLConfig, error) {
+	chainJSON, err := os.ReadFile(chainPath)
+	if err != nil {
+		return nil, err
+	}
+	assetListJSON, err := os.ReadFile(assetListPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewTOMLConfigFromChainRegistry(chainJSON, assetListJSON)
+}
+
+// NewTOMLConfigFromChainRegistry returns a config for the chain described by a chain-registry chain.json
+// and assetlist.json. The first fee token is the GasToken, and must be an asset of the chain.
+// Its average gas price is the FallbackGasPrice, and its low (or fixed minimum) gas price is the MinGasPrice.
+// Its high gas price is the MaxGasPrice, which caps the estimated gas price. Its display unit is added to the Denoms.
+// Each RPC endpoint is a primary node.
+// Other values are left unset, so that they take the defaults. The returned config is validated.
+func NewTOMLConfigFromChainRegistry(chainJSON, assetListJSON []byte) (*TOMLConfig, error) {
+	var chain registryChain
+	if err := json.Unmarshal(chainJSON, &chain); err != nil {
+		return nil, fmt.Errorf("failed to parse chain.json: %w", err)
+	}
+	var assets registryAssetList
+	if err := json.Unmarshal(assetListJSON, &assets); err != nil {
+		return nil, fmt.Errorf("failed to parse assetlist.json: %w", err)
+	}
+
+	if len(chain.Fees.FeeTokens) == 0 {
+		return nil, errors.New("chain.json has no fee tokens")
+	}
+	fee := chain.Fees.FeeTokens[0]
+	i := slices.IndexFunc(assets.Assets, func(a registryAsset) bool { return a.Base == fee.Denom })
+	if i == -1 {
+		return nil, fmt.Errorf("fee token %s is not an asset in assetlist.json", fee.Denom)
+	}
+	asset := assets.Assets[i]
+
+	c := &TOMLConfig{
+		ChainID: &chain.ChainID,
+		Chain: Chain{
+			Bech32Prefix: &chain.Bech32Prefix,
+			GasToken:     &fee.Denom,
+			MinGasPrice:  fee.LowGasPrice,
+			MaxGasPrice:  fee.HighGasPrice,
+		},
+	}
+	if j := slices.IndexFunc(asset.DenomUnits, func(u registryDenomUnit) bool { return u.Denom == asset.Display }); j != -1 && asset.Display != asset.Base {
+		c.Denoms = Denoms{{Base: &asset.Base, Display: &asset.Display, Exponent: &asset.DenomUnits[j].Exponent}}
+	}
+	if c.Chain.MinGasPrice == nil {
+		c.Chain.MinGasPrice = fee.FixedMinGasPrice
+	}
+	c.Chain.FallbackGasPrice = fee.AverageGasPrice
+	if c.Chain.FallbackGasPrice == nil {
+		c.Chain.FallbackGasPrice = c.Chain.MinGasPrice
+	}
+	for i, rpc := range chain.APIs.RPC {
+		u, err := config.ParseURL(rpc.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rpc address %q: %w", rpc.Address, err)
+		}
+		name := fmt.Sprintf("%s-%d", chain.ChainName, i)
+		c.Nodes = append(c.Nodes, &Node{Name: &name, TendermintURL: u})
+	}
+
+	// validate with the defaults, without including them in c
+	withDefaults := *c
+	withDefaults.SetDefaults()
+	if err := config.Validate(TOMLConfigs{&withDefaults}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const registryChainJSON = `{
+  "chain_name": "osmosis",
+  "chain_id": "osmosis-1",
+  "bech32_prefix": "osmo",
+  "fees": {
+    "fee_tokens": [
+      {"denom": "uosmo", "fixed_min_gas_price": 0.0025, "low_gas_price": 0.0025, "average_gas_price": 0.025, "high_gas_price": 0.04},
+      {"denom": "uion", "fixed_min_gas_price": 0.0025}
+    ]
+  },
+  "apis": {
+    "rpc": [
+      {"address": "https://rpc.osmosis.zone/", "provider": "Osmosis Foundation"},
+      {"address": "https://osmosis-rpc.polkachu.com", "provider": "Polkachu"}
+    ]
+  }
+}`
+
+const registryAssetListJSON = `{
+  "chain_name": "osmosis",
+  "assets": [
+    {"base": "uosmo", "display": "osmo", "denom_units": [{"denom": "uosmo", "exponent": 0}, {"denom": "osmo", "exponent": 6}]},
+    {"base": "uion", "display": "ion", "denom_units": [{"denom": "uion", "exponent": 0}, {"denom": "ion", "exponent": 6}]}
+  ]
+}`
+
+func TestNewTOMLConfigFromChainRegistry(t *testing.T) {
+	c, err := NewTOMLConfigFromChainRegistry([]byte(registryChainJSON), []byte(registryAssetListJSON))
+	require.NoError(t, err)
+	assert.Equal(t, "osmosis-1", *c.ChainID)
+	assert.Equal(t, "osmo", *c.Chain.Bech32Prefix)
+	assert.Equal(t, "uosmo", *c.Chain.GasToken)
+	assert.True(t, decimal.RequireFromString("0.025").Equal(*c.Chain.FallbackGasPrice))
+	assert.True(t, decimal.RequireFromString("0.0025").Equal(*c.Chain.MinGasPrice))
+	assert.True(t, decimal.RequireFromString("0.04").Equal(*c.Chain.MaxGasPrice))
+	assert.Nil(t, c.Chain.BlockRate, "defaults are not included")
+	require.Len(t, c.Nodes, 2)
+	assert.Equal(t, "osmosis-0", *c.Nodes[0].Name)
+	assert.Equal(t, "https://rpc.osmosis.zone/", c.Nodes[0].TendermintURL.String())
+	assert.Equal(t, "osmosis-1", *c.Nodes[1].Name)
+	require.Len(t, c.Denoms, 1)
+	assert.Equal(t, "uosmo", *c.Denoms[0].Base)
+	assert.Equal(t, "osmo", *c.Denoms[0].Display)
+	assert.Equal(t, uint32(6), *c.Denoms[0].Exponent)
+
+	t.Run("unknown fee token", func(t *testing.T) {
+		_, err := NewTOMLConfigFromChainRegistry([]byte(registryChainJSON), []byte(`{"assets": [{"base": "uion"}]}`))
+		require.ErrorContains(t, err, "fee token uosmo is not an asset in assetlist.json")
+	})
+
+	t.Run("no high gas price", func(t *testing.T) {
+		c, err := NewTOMLConfigFromChainRegistry([]byte(`{"chain_name": "osmosis", "chain_id": "osmosis-1", "bech32_prefix": "osmo",
+			"fees": {"fee_tokens": [{"denom": "uosmo", "low_gas_price": 0.0025}]},
+			"apis": {"rpc": [{"address": "https://rpc.osmosis.zone/"}]}}`), []byte(registryAssetListJSON))
+		require.NoError(t, err)
+		assert.Nil(t, c.Chain.MaxGasPrice, "no cap")
+	})
+
+	t.Run("no nodes", func(t *testing.T) {
+		_, err := NewTOMLConfigFromChainRegistry([]byte(`{"chain_name": "osmosis", "chain_id": "osmosis-1", "bech32_prefix": "osmo",
+			"fees": {"fee_tokens": [{"denom": "uosmo"}]}}`), []byte(registryAssetListJSON))
+		require.ErrorContains(t, err, "Nodes: missing: must have at least one node")
+	})
+}
@@ -1,13 +1,25 @@
 package config
 
 import (
+	"crypto/rand"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"math/big"
+	"net/http"
 	"net/url"
+	"os"
+	"regexp"
 	"slices"
 	"time"
 
+	"github.com/cometbft/cometbft/light"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	"github.com/pelletier/go-toml/v2"
 	"github.com/shopspring/decimal"
 
@@ -15,6 +27,8 @@ import (
 
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/db"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/ethermint"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
 )
 
 // Global defaults.
@@ -25,8 +39,13 @@ var defaultConfigSet = configSet{
 	// In practice during the UST depegging and subsequent extreme congestion, we saw
 	// ~16 block FIFO lineups.
 	BlocksUntilTxTimeout: 30,
+	BroadcastFanOut:      1,
 	ConfirmPollPeriod:    time.Second,
+	DetectAutoFill:       false,
+	DetectMode:           DetectModeWarn,
 	FallbackGasPrice:     sdk.MustNewDecFromStr("0.015"),
+	GasPriceSource:       PriceSourceFixed,
+	KeyAlgorithm:         KeyAlgorithmSecp256k1,
 	// This is high since we simulate before signing the transaction.
 	// There's a chicken and egg problem: need to sign to simulate accurately
 	// but you need to specify a gas limit when signing.
@@ -45,23 +64,41 @@ var defaultConfigSet = configSet{
 	MaxMsgsPerBatch:     100,
 	OCR2CachePollPeriod: 4 * time.Second,
 	OCR2CacheTTL:        time.Minute,
+	SignMode:            SignModeDirect,
 	TxMsgTimeout:        10 * time.Minute,
+	UnorderedTxs:        false,
 	Bech32Prefix:        "wasm",  // note: this shouldn't be used outside of tests
 	GasToken:            "ucosm", // note: this shouldn't be used outside of tests
 }
 
 type Config interface {
+	Adapter() string
 	Bech32Prefix() string
 	BlockRate() time.Duration
 	BlocksUntilTxTimeout() int64
+	// BroadcastFanOut returns the number of nodes each tx is broadcast to, or 0 for every healthy node.
+	BroadcastFanOut() int64
 	ConfirmPollPeriod() time.Duration
 	FallbackGasPrice() sdk.Dec
+	// FeeTokens returns the accepted fee denoms in order of preference, starting with the GasToken.
+	FeeTokens() []FeeToken
 	GasToken() string
 	GasLimitMultiplier() float64
+	KeyAlgorithm() KeyAlgorithm
+	MaxGasPrice() sdk.Dec
 	MaxMsgsPerBatch() int64
+	MinGasPrice() sdk.Dec
 	OCR2CachePollPeriod() time.Duration
 	OCR2CacheTTL() time.Duration
+	// MultisigPubKey returns the key of the multisig account with address, if any.
+	MultisigPubKey(address string) (*kmultisig.LegacyAminoPubKey, bool)
+	// SenderPolicy returns the TxPolicy of sender, if any.
+	SenderPolicy(sender string) (SenderPolicy, bool)
+	SignMode() SignMode
 	TxMsgTimeout() time.Duration
+	// TrustOptions returns the trust options of the light client which verifies reads, if VerifiedReads are enabled.
+	TrustOptions() (light.TrustOptions, bool)
+	UnorderedTxs() bool
 }
 
 // opt: remove
@@ -69,33 +106,74 @@ type configSet struct {
 	Bech32Prefix         string
 	BlockRate            time.Duration
 	BlocksUntilTxTimeout int64
+	BroadcastFanOut      int64
 	ConfirmPollPeriod    time.Duration
+	DetectAutoFill       bool
+	DetectMode           DetectMode
 	FallbackGasPrice     sdk.Dec
+	GasPriceSource       PriceSource
 	GasToken             string
 	GasLimitMultiplier   float64
+	KeyAlgorithm         KeyAlgorithm
 	MaxMsgsPerBatch      int64
 	OCR2CachePollPeriod  time.Duration
 	OCR2CacheTTL         time.Duration
+	SignMode             SignMode
 	TxMsgTimeout         time.Duration
+	UnorderedTxs         bool
 }
 
 type Chain struct {
+	// Adapter is the name of the adapter for the OCR2 contracts of the chain, e.g. cosmwasm or injective-module.
+	// Jobs may override it with the adapter of their relay config.
+	Adapter              *string
 	Bech32Prefix         *string
 	BlockRate            *config.Duration
 	BlocksUntilTxTimeout *int64
-	ConfirmPollPeriod    *config.Duration
-	FallbackGasPrice     *decimal.Decimal
-	GasToken             *string
-	GasLimitMultiplier   *decimal.Decimal
-	MaxMsgsPerBatch      *int64
-	OCR2CachePollPeriod  *config.Duration
-	OCR2CacheTTL         *config.Duration
-	TxMsgTimeout         *config.Duration
+	// BroadcastFanOut is the number of nodes, including sendonly nodes, which each tx is broadcast to in parallel.
+	// The tx is sent once any of them admits it to its mempool. Nodes whose last broadcast failed or found a full
+	// mempool are picked last. 0 broadcasts to every healthy node. Defaults to 1.
+	BroadcastFanOut   *int64
+	ConfirmPollPeriod *config.Duration
+	// DetectAutoFill fills in the Bech32Prefix and GasToken, if they are unset, with the bech32 prefix and fee denom
+	// detected from a node at startup, and replaces the BlockRate and MinGasPrice with the detected values when they
+	// disagree.
+	DetectAutoFill *bool
+	// DetectMode determines what happens when the config disagrees with the params detected from a node at startup.
+	DetectMode       *DetectMode
+	FallbackGasPrice *decimal.Decimal
+	// GasPriceSource determines the gas price of the GasToken. Defaults to fixed.
+	GasPriceSource     *PriceSource
+	GasToken           *string
+	GasLimitMultiplier *decimal.Decimal
+	// KeyAlgorithm of the keys which sign txs, which determines their addresses, public key type and signatures.
+	// Defaults to secp256k1. The keystore of eth_secp256k1 keys must sign digests as they are, see txm.DigestKeystore.
+	KeyAlgorithm *KeyAlgorithm
+	// MaxGasPrice caps the estimated gas price. Unset means no cap.
+	MaxGasPrice     *decimal.Decimal
+	MaxMsgsPerBatch *int64
+	// MinGasPrice is the lowest gas price paid, regardless of the estimated gas price. Unset means no minimum.
+	MinGasPrice         *decimal.Decimal
+	OCR2CachePollPeriod *config.Duration
+	OCR2CacheTTL        *config.Duration
+	// SignMode of txs, for chains and signers which do not accept direct signatures. Defaults to direct.
+	// The textual mode is not supported by cosmos-sdk v0.47.
+	SignMode     *SignMode
+	TxMsgTimeout *config.Duration
+	// UnorderedTxs sends unordered txs on chains which support them (cosmos-sdk v0.53+), which are replay protected by
+	// a timeout of BlocksUntilTxTimeout blocks, capped at the 10 minutes accepted by default, instead of the account
+	// sequence, so that batches from the same sender do not wait for each other. Multisig accounts and chains without
+	// support send sequenced txs. Defaults to false.
+	UnorderedTxs *bool
+
+	// bech32PrefixSource and gasTokenSource record whether the Bech32Prefix and GasToken were configured, so that
+	// unset ones can be filled in by DetectAutoFill.
+	bech32PrefixSource, gasTokenSource valueSource
 }
 
 func (c *Chain) SetDefaults() {
 	if c.Bech32Prefix == nil {
-		c.Bech32Prefix = &defaultConfigSet.Bech32Prefix
+		c.Bech32Prefix, c.bech32PrefixSource = &defaultConfigSet.Bech32Prefix, sourceDefault
 	}
 	if c.BlockRate == nil {
 		c.BlockRate = config.MustNewDuration(defaultConfigSet.BlockRate)
@@ -103,20 +181,35 @@ func (c *Chain) SetDefaults() {
 	if c.BlocksUntilTxTimeout == nil {
 		c.BlocksUntilTxTimeout = &defaultConfigSet.BlocksUntilTxTimeout
 	}
+	if c.BroadcastFanOut == nil {
+		c.BroadcastFanOut = &defaultConfigSet.BroadcastFanOut
+	}
 	if c.ConfirmPollPeriod == nil {
 		c.ConfirmPollPeriod = config.MustNewDuration(defaultConfigSet.ConfirmPollPeriod)
 	}
+	if c.DetectAutoFill == nil {
+		c.DetectAutoFill = &defaultConfigSet.DetectAutoFill
+	}
+	if c.DetectMode == nil {
+		c.DetectMode = &defaultConfigSet.DetectMode
+	}
 	if c.FallbackGasPrice == nil {
 		d := decimal.NewFromBigInt(defaultConfigSet.FallbackGasPrice.BigInt(), -sdk.Precision)
 		c.FallbackGasPrice = &d
 	}
+	if c.GasPriceSource == nil {
+		c.GasPriceSource = &defaultConfigSet.GasPriceSource
+	}
 	if c.GasToken == nil {
-		c.GasToken = &defaultConfigSet.GasToken
+		c.GasToken, c.gasTokenSource = &defaultConfigSet.GasToken, sourceDefault
 	}
 	if c.GasLimitMultiplier == nil {
 		d := decimal.NewFromFloat(defaultConfigSet.GasLimitMultiplier)
 		c.GasLimitMultiplier = &d
 	}
+	if c.KeyAlgorithm == nil {
+		c.KeyAlgorithm = &defaultConfigSet.KeyAlgorithm
+	}
 	if c.MaxMsgsPerBatch == nil {
 		c.MaxMsgsPerBatch = &defaultConfigSet.MaxMsgsPerBatch
 	}
@@ -126,14 +219,252 @@ func (c *Chain) SetDefaults() {
 	if c.OCR2CacheTTL == nil {
 		c.OCR2CacheTTL = config.MustNewDuration(defaultConfigSet.OCR2CacheTTL)
 	}
+	if c.SignMode == nil {
+		c.SignMode = &defaultConfigSet.SignMode
+	}
 	if c.TxMsgTimeout == nil {
 		c.TxMsgTimeout = config.MustNewDuration(defaultConfigSet.TxMsgTimeout)
 	}
+	if c.UnorderedTxs == nil {
+		c.UnorderedTxs = &defaultConfigSet.UnorderedTxs
+	}
+}
+
+// bech32PrefixRegexp matches the human readable part of bech32 addresses, as accepted by the sdk.
+var bech32PrefixRegexp = regexp.MustCompile(`^[a-z][a-z0-9]{0,82}$`)
+
+func (c *Chain) ValidateConfig() (err error) {
+	if c.Adapter != nil && *c.Adapter == "" {
+		err = errors.Join(err, config.ErrEmpty{Name: "Adapter", Msg: "must be set or omitted"})
+	}
+	if c.Bech32Prefix != nil {
+		if *c.Bech32Prefix == "" {
+			err = errors.Join(err, config.ErrEmpty{Name: "Bech32Prefix", Msg: "required for all chains"})
+		} else if !bech32PrefixRegexp.MatchString(*c.Bech32Prefix) {
+			err = errors.Join(err, config.ErrInvalid{Name: "Bech32Prefix", Value: *c.Bech32Prefix,
+				Msg: "must be lowercase alphanumeric, starting with a letter"})
+		}
+	}
+	for _, d := range []struct {
+		name string
+		d    *config.Duration
+	}{
+		{"BlockRate", c.BlockRate},
+		{"ConfirmPollPeriod", c.ConfirmPollPeriod},
+		{"OCR2CachePollPeriod", c.OCR2CachePollPeriod},
+		{"OCR2CacheTTL", c.OCR2CacheTTL},
+		{"TxMsgTimeout", c.TxMsgTimeout},
+	} {
+		if d.d != nil && d.d.Duration() <= 0 {
+			err = errors.Join(err, config.ErrInvalid{Name: d.name, Value: d.d.String(), Msg: "must be positive"})
+		}
+	}
+	if c.DetectMode != nil && !slices.Contains([]DetectMode{DetectModeOff, DetectModeWarn, DetectModeRefuse}, *c.DetectMode) {
+		err = errors.Join(err, config.ErrInvalid{Name: "DetectMode", Value: *c.DetectMode,
+			Msg: fmt.Sprintf("must be %s, %s or %s", DetectModeOff, DetectModeWarn, DetectModeRefuse)})
+	}
+	if c.BlocksUntilTxTimeout != nil && *c.BlocksUntilTxTimeout <= 0 {
+		err = errors.Join(err, config.ErrInvalid{Name: "BlocksUntilTxTimeout", Value: *c.BlocksUntilTxTimeout, Msg: "must be positive"})
+	}
+	if c.BlockRate != nil && c.BlocksUntilTxTimeout != nil && c.ConfirmPollPeriod != nil && *c.BlocksUntilTxTimeout > 0 {
+		// the txm polls for confirmation BlockRate * BlocksUntilTxTimeout / ConfirmPollPeriod times
+		if txTimeout := time.Duration(*c.BlocksUntilTxTimeout) * c.BlockRate.Duration(); c.ConfirmPollPeriod.Duration() > txTimeout {
+			err = errors.Join(err, config.ErrInvalid{Name: "ConfirmPollPeriod", Value: c.ConfirmPollPeriod.String(),
+				Msg: fmt.Sprintf("must not exceed BlockRate * BlocksUntilTxTimeout (%s)", txTimeout)})
+		}
+	}
+	if c.OCR2CachePollPeriod != nil && c.OCR2CacheTTL != nil && c.OCR2CacheTTL.Duration() < c.OCR2CachePollPeriod.Duration() {
+		err = errors.Join(err, config.ErrInvalid{Name: "OCR2CacheTTL", Value: c.OCR2CacheTTL.String(),
+			Msg: fmt.Sprintf("must be at least OCR2CachePollPeriod (%s)", c.OCR2CachePollPeriod)})
+	}
+	err = errors.Join(err, validateGasPrices(c.FallbackGasPrice, c.MinGasPrice, c.MaxGasPrice))
+	if c.GasLimitMultiplier != nil && c.GasLimitMultiplier.LessThan(decimal.NewFromInt(1)) {
+		err = errors.Join(err, config.ErrInvalid{Name: "GasLimitMultiplier", Value: c.GasLimitMultiplier.String(), Msg: "must be at least 1"})
+	}
+	if c.GasPriceSource != nil {
+		err = errors.Join(err, c.GasPriceSource.validate("GasPriceSource"))
+	}
+	if c.GasToken != nil {
+		if *c.GasToken == "" {
+			err = errors.Join(err, config.ErrEmpty{Name: "GasToken", Msg: "required for all chains"})
+		} else if err1 := params.ValidateGasToken(*c.GasToken); err1 != nil {
+			err = errors.Join(err, config.ErrInvalid{Name: "GasToken", Value: *c.GasToken, Msg: err1.Error()})
+		}
+	}
+	if c.KeyAlgorithm != nil && !slices.Contains([]KeyAlgorithm{KeyAlgorithmSecp256k1, KeyAlgorithmEthSecp256k1, KeyAlgorithmInjectiveEthSecp256k1}, *c.KeyAlgorithm) {
+		err = errors.Join(err, config.ErrInvalid{Name: "KeyAlgorithm", Value: *c.KeyAlgorithm,
+			Msg: fmt.Sprintf("must be %s, %s or %s", KeyAlgorithmSecp256k1, KeyAlgorithmEthSecp256k1, KeyAlgorithmInjectiveEthSecp256k1)})
+	}
+	if c.BroadcastFanOut != nil && *c.BroadcastFanOut < 0 {
+		err = errors.Join(err, config.ErrInvalid{Name: "BroadcastFanOut", Value: *c.BroadcastFanOut, Msg: "must not be negative"})
+	}
+	if c.MaxMsgsPerBatch != nil && *c.MaxMsgsPerBatch <= 0 {
+		err = errors.Join(err, config.ErrInvalid{Name: "MaxMsgsPerBatch", Value: *c.MaxMsgsPerBatch, Msg: "must be positive"})
+	}
+	if c.SignMode != nil && !slices.Contains([]SignMode{SignModeDirect, SignModeAminoJSON}, *c.SignMode) {
+		err = errors.Join(err, config.ErrInvalid{Name: "SignMode", Value: *c.SignMode,
+			Msg: fmt.Sprintf("must be %s or %s", SignModeDirect, SignModeAminoJSON)})
+	}
+	if c.UnorderedTxs != nil && *c.UnorderedTxs && c.SignMode != nil && *c.SignMode != SignModeDirect {
+		err = errors.Join(err, config.ErrInvalid{Name: "UnorderedTxs", Value: *c.UnorderedTxs,
+			Msg: fmt.Sprintf("requires SignMode %s", SignModeDirect)})
+	}
+	return
+}
+
+// KeyAlgorithm is the algorithm of the keys which sign txs.
+type KeyAlgorithm string
+
+const (
+	// KeyAlgorithmSecp256k1 is the secp256k1 algorithm of most cosmos chains.
+	KeyAlgorithmSecp256k1 KeyAlgorithm = "secp256k1"
+	// KeyAlgorithmEthSecp256k1 is the eth_secp256k1 algorithm of Ethermint chains, such as Evmos.
+	// Addresses are derived as on Ethereum, and the keccak256 hash of txs is signed.
+	KeyAlgorithmEthSecp256k1 KeyAlgorithm = "eth_secp256k1"
+	// KeyAlgorithmInjectiveEthSecp256k1 is eth_secp256k1, with the public key type of Injective.
+	KeyAlgorithmInjectiveEthSecp256k1 KeyAlgorithm = "injective_eth_secp256k1"
+)
+
+// IsEthereum returns whether keys are eth_secp256k1.
+func (a KeyAlgorithm) IsEthereum() bool {
+	return a == KeyAlgorithmEthSecp256k1 || a == KeyAlgorithmInjectiveEthSecp256k1
+}
+
+// PubKey returns key, a compressed public key, as the public key type of the algorithm.
+func (a KeyAlgorithm) PubKey(key []byte) cryptotypes.PubKey {
+	switch a {
+	case KeyAlgorithmEthSecp256k1:
+		return &ethermint.PubKey{Key: key}
+	case KeyAlgorithmInjectiveEthSecp256k1:
+		return &ethermint.InjectivePubKey{Key: key}
+	default:
+		return &secp256k1.PubKey{Key: key}
+	}
+}
+
+// SignMode is the mode in which txs are signed.
+type SignMode string
+
+const (
+	// SignModeDirect signs the protobuf encoding of txs.
+	SignModeDirect SignMode = "direct"
+	// SignModeAminoJSON signs the legacy amino JSON encoding of txs, as required by some chains and hardware
+	// or external signers.
+	SignModeAminoJSON SignMode = "amino-json"
+)
+
+// Proto returns the protobuf enum of the mode.
+func (m SignMode) Proto() signing.SignMode {
+	if m == SignModeAminoJSON {
+		return signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON
+	}
+	return signing.SignMode_SIGN_MODE_DIRECT
+}
+
+// PriceSource determines the gas price of a fee denom.
+type PriceSource string
+
+const (
+	// PriceSourceFixed uses the FallbackGasPrice.
+	PriceSourceFixed PriceSource = "fixed"
+	// PriceSourceNode uses the minimum gas price accepted by a node, or the FallbackGasPrice if it has none.
+	PriceSourceNode PriceSource = "node"
+)
+
+func (s PriceSource) validate(name string) error {
+	if s != PriceSourceFixed && s != PriceSourceNode {
+		return config.ErrInvalid{Name: name, Value: s, Msg: fmt.Sprintf("must be %s or %s", PriceSourceFixed, PriceSourceNode)}
+	}
+	return nil
+}
+
+// validateGasPrices checks the gas prices of a fee denom, any of which may be nil.
+func validateGasPrices(fallbackGasPrice, minGasPrice, maxGasPrice *decimal.Decimal) (err error) {
+	if fallbackGasPrice != nil && fallbackGasPrice.IsNegative() {
+		err = errors.Join(err, config.ErrInvalid{Name: "FallbackGasPrice", Value: fallbackGasPrice.String(), Msg: "must not be negative"})
+	}
+	if minGasPrice != nil && minGasPrice.IsNegative() {
+		err = errors.Join(err, config.ErrInvalid{Name: "MinGasPrice", Value: minGasPrice.String(), Msg: "must not be negative"})
+	}
+	if maxGasPrice != nil {
+		if minGasPrice != nil && maxGasPrice.LessThan(*minGasPrice) {
+			err = errors.Join(err, config.ErrInvalid{Name: "MaxGasPrice", Value: maxGasPrice.String(),
+				Msg: fmt.Sprintf("must be at least MinGasPrice (%s)", minGasPrice)})
+		}
+		if fallbackGasPrice != nil && maxGasPrice.LessThan(*fallbackGasPrice) {
+			err = errors.Join(err, config.ErrInvalid{Name: "MaxGasPrice", Value: maxGasPrice.String(),
+				Msg: fmt.Sprintf("must be at least FallbackGasPrice (%s)", fallbackGasPrice)})
+		}
+	}
+	return
+}
+
+// NodePriority determines which requests are sent to a node.
+type NodePriority string
+
+const (
+	// NodePriorityPrimary nodes serve all requests.
+	NodePriorityPrimary NodePriority = "primary"
+	// NodePrioritySendOnly nodes are not picked for reads, and are only used when requested by name.
+	NodePrioritySendOnly NodePriority = "sendonly"
+)
+
+// Secret is a string which is redacted when printed, and by TOMLString.
+// It is marshalled as is, so that configs round trip.
+type Secret string
+
+const redacted = "xxxxx"
+
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+func (s *Secret) UnmarshalText(text []byte) error {
+	*s = Secret(text)
+	return nil
+}
+
+func (s Secret) String() string {
+	return redacted
+}
+
+func (s Secret) GoString() string {
+	return redacted
 }
 
 type Node struct {
 	Name          *string
 	TendermintURL *config.URL
+	// RequestTimeout defaults to client.DefaultTimeout.
+	RequestTimeout *config.Duration
+	// Headers are set on each HTTP request, e.g. to pass a provider API key.
+	Headers map[string]Secret
+	// Username and Password are sent with basic auth, if set.
+	Username *string
+	Password *Secret
+	// Priority is either primary or sendonly. Defaults to primary.
+	Priority *NodePriority
+	// Weight is the relative share of reads picked by primary nodes. Defaults to 1.
+	Weight *uint32
+	// TLSCAFile is a PEM bundle of the CAs which verify the TLS certificate of the node,
+	// instead of the system roots.
+	TLSCAFile *string
+}
+
+// Redacted returns a copy of n with its secrets redacted, for display.
+func (n *Node) Redacted() *Node {
+	r := *n
+	if n.Headers != nil {
+		r.Headers = make(map[string]Secret, len(n.Headers))
+		for key := range n.Headers {
+			r.Headers[key] = redacted
+		}
+	}
+	if n.Password != nil {
+		password := Secret(redacted)
+		r.Password = &password
+	}
+	return &r
 }
 
 func (n *Node) ValidateConfig() (err error) {
@@ -145,9 +476,82 @@ func (n *Node) ValidateConfig() (err error) {
 	if n.TendermintURL == nil {
 		err = errors.Join(err, config.ErrMissing{Name: "TendermintURL", Msg: "required for all nodes"})
 	}
+	if n.RequestTimeout != nil && n.RequestTimeout.Duration() <= 0 {
+		err = errors.Join(err, config.ErrInvalid{Name: "RequestTimeout", Value: n.RequestTimeout.String(), Msg: "must be positive"})
+	}
+	if n.Username != nil && n.Password == nil {
+		err = errors.Join(err, config.ErrMissing{Name: "Password", Msg: "required with Username"})
+	} else if n.Username == nil && n.Password != nil {
+		err = errors.Join(err, config.ErrMissing{Name: "Username", Msg: "required with Password"})
+	}
+	if n.Priority != nil && *n.Priority != NodePriorityPrimary && *n.Priority != NodePrioritySendOnly {
+		err = errors.Join(err, config.ErrInvalid{Name: "Priority", Value: *n.Priority,
+			Msg: fmt.Sprintf("must be %s or %s", NodePriorityPrimary, NodePrioritySendOnly)})
+	}
+	if n.Weight != nil && *n.Weight == 0 {
+		err = errors.Join(err, config.ErrInvalid{Name: "Weight", Value: *n.Weight, Msg: "must be positive"})
+	}
+	if n.TLSCAFile != nil {
+		if _, err1 := n.rootCAs(); err1 != nil {
+			err = errors.Join(err, config.ErrInvalid{Name: "TLSCAFile", Value: *n.TLSCAFile, Msg: err1.Error()})
+		}
+	}
 	return
 }
 
+// IsSendOnly returns true if the node must not be picked for reads.
+func (n *Node) IsSendOnly() bool {
+	return n.Priority != nil && *n.Priority == NodePrioritySendOnly
+}
+
+// GetWeight returns the relative share of reads picked by the node.
+func (n *Node) GetWeight() uint32 {
+	if n.Weight == nil {
+		return 1
+	}
+	return *n.Weight
+}
+
+// GetRequestTimeout returns the request timeout of the node, or 0 for the client default.
+func (n *Node) GetRequestTimeout() time.Duration {
+	if n.RequestTimeout == nil {
+		return 0
+	}
+	return n.RequestTimeout.Duration()
+}
+
+// HTTPOptions returns the options for HTTP requests to the node.
+func (n *Node) HTTPOptions() (opts client.HTTPOptions, err error) {
+	if len(n.Headers) > 0 {
+		opts.Headers = make(http.Header, len(n.Headers))
+		for k, v := range n.Headers {
+			opts.Headers.Set(k, string(v))
+		}
+	}
+	if n.Username != nil {
+		opts.Username = *n.Username
+	}
+	if n.Password != nil {
+		opts.Password = string(*n.Password)
+	}
+	if n.TLSCAFile != nil {
+		opts.RootCAs, err = n.rootCAs()
+	}
+	return
+}
+
+func (n *Node) rootCAs() (*x509.CertPool, error) {
+	pem, err := os.ReadFile(*n.TLSCAFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no PEM certificates found")
+	}
+	return pool, nil
+}
+
 type TOMLConfigs []*TOMLConfig
 
 func (cs TOMLConfigs) validateKeys() (err error) {
@@ -227,6 +631,175 @@ func setFromNode(n, f *Node) {
 	if f.TendermintURL != nil {
 		n.TendermintURL = f.TendermintURL
 	}
+	if f.RequestTimeout != nil {
+		n.RequestTimeout = f.RequestTimeout
+	}
+	if f.Headers != nil {
+		n.Headers = f.Headers
+	}
+	if f.Username != nil {
+		n.Username = f.Username
+	}
+	if f.Password != nil {
+		n.Password = f.Password
+	}
+	if f.Priority != nil {
+		n.Priority = f.Priority
+	}
+	if f.Weight != nil {
+		n.Weight = f.Weight
+	}
+	if f.TLSCAFile != nil {
+		n.TLSCAFile = f.TLSCAFile
+	}
+}
+
+// Denom describes the units of a denomination, for chains which have no bank metadata for it.
+type Denom struct {
+	// Base is the smallest unit, in which amounts are stored on chain, e.g. uatom.
+	Base *string
+	// Display is the unit shown to users, e.g. atom.
+	Display *string
+	// Exponent is the number of decimals of the Display unit, e.g. 6, so that 1atom is 10^6uatom.
+	Exponent *uint32
+}
+
+func (d *Denom) ValidateConfig() (err error) {
+	if d.Base == nil {
+		err = errors.Join(err, config.ErrMissing{Name: "Base", Msg: "required for all denoms"})
+	} else if err1 := sdk.ValidateDenom(*d.Base); err1 != nil {
+		err = errors.Join(err, config.ErrInvalid{Name: "Base", Value: *d.Base, Msg: err1.Error()})
+	}
+	if d.Display != nil {
+		if err1 := sdk.ValidateDenom(*d.Display); err1 != nil {
+			err = errors.Join(err, config.ErrInvalid{Name: "Display", Value: *d.Display, Msg: err1.Error()})
+		}
+		if d.Exponent == nil {
+			err = errors.Join(err, config.ErrMissing{Name: "Exponent", Msg: "required with Display"})
+		} else if *d.Exponent == 0 && (d.Base == nil || *d.Display != *d.Base) {
+			err = errors.Join(err, config.ErrInvalid{Name: "Exponent", Value: *d.Exponent, Msg: "must be positive"})
+		}
+	} else if d.Exponent != nil {
+		err = errors.Join(err, config.ErrMissing{Name: "Display", Msg: "required with Exponent"})
+	}
+	return
+}
+
+// Metadata returns the bank metadata equivalent to d.
+func (d *Denom) Metadata() banktypes.Metadata {
+	md := banktypes.Metadata{
+		Base:       *d.Base,
+		DenomUnits: []*banktypes.DenomUnit{{Denom: *d.Base, Exponent: 0}},
+	}
+	if d.Display != nil {
+		md.Display = *d.Display
+		if *d.Display != *d.Base {
+			md.DenomUnits = append(md.DenomUnits, &banktypes.DenomUnit{Denom: *d.Display, Exponent: *d.Exponent})
+		}
+	}
+	return md
+}
+
+// FeeDenom is a denom in which fees are paid when the balance of the GasToken does not cover them.
+type FeeDenom struct {
+	Denom *string
+	// FallbackGasPrice is the gas price in Denom, when the PriceSource has none.
+	FallbackGasPrice *decimal.Decimal
+	// MaxGasPrice caps the gas price. Unset means no cap.
+	MaxGasPrice *decimal.Decimal
+	// MinGasPrice is the lowest gas price paid. Unset means no minimum.
+	MinGasPrice *decimal.Decimal
+	// PriceSource determines the gas price. Defaults to fixed.
+	PriceSource *PriceSource
+}
+
+func (d *FeeDenom) ValidateConfig() (err error) {
+	if d.Denom == nil {
+		err = errors.Join(err, config.ErrMissing{Name: "Denom", Msg: "required for all fee denoms"})
+	} else if err1 := sdk.ValidateDenom(*d.Denom); err1 != nil {
+		err = errors.Join(err, config.ErrInvalid{Name: "Denom", Value: *d.Denom, Msg: err1.Error()})
+	}
+	if d.FallbackGasPrice == nil {
+		err = errors.Join(err, config.ErrMissing{Name: "FallbackGasPrice", Msg: "required for all fee denoms"})
+	}
+	err = errors.Join(err, validateGasPrices(d.FallbackGasPrice, d.MinGasPrice, d.MaxGasPrice))
+	if d.PriceSource != nil {
+		err = errors.Join(err, d.PriceSource.validate("PriceSource"))
+	}
+	return
+}
+
+type FeeDenoms []*FeeDenom
+
+func (ds *FeeDenoms) SetFrom(fs *FeeDenoms) {
+	for _, f := range *fs {
+		if f.Denom == nil {
+			*ds = append(*ds, f)
+		} else if i := slices.IndexFunc(*ds, func(d *FeeDenom) bool {
+			return d.Denom != nil && *d.Denom == *f.Denom
+		}); i == -1 {
+			*ds = append(*ds, f)
+		} else {
+			setFromFeeDenom((*ds)[i], f)
+		}
+	}
+}
+
+func setFromFeeDenom(d, f *FeeDenom) {
+	if f.Denom != nil {
+		d.Denom = f.Denom
+	}
+	if f.FallbackGasPrice != nil {
+		d.FallbackGasPrice = f.FallbackGasPrice
+	}
+	if f.MaxGasPrice != nil {
+		d.MaxGasPrice = f.MaxGasPrice
+	}
+	if f.MinGasPrice != nil {
+		d.MinGasPrice = f.MinGasPrice
+	}
+	if f.PriceSource != nil {
+		d.PriceSource = f.PriceSource
+	}
+}
+
+// FeeToken is an accepted fee denom, with its gas prices.
+type FeeToken struct {
+	Denom            string
+	FallbackGasPrice sdk.Dec
+	// MaxGasPrice is nil if unset.
+	MaxGasPrice sdk.Dec
+	// MinGasPrice is zero if unset.
+	MinGasPrice sdk.Dec
+	PriceSource PriceSource
+}
+
+type Denoms []*Denom
+
+func (ds *Denoms) SetFrom(fs *Denoms) {
+	for _, f := range *fs {
+		if f.Base == nil {
+			*ds = append(*ds, f)
+		} else if i := slices.IndexFunc(*ds, func(d *Denom) bool {
+			return d.Base != nil && *d.Base == *f.Base
+		}); i == -1 {
+			*ds = append(*ds, f)
+		} else {
+			setFromDenom((*ds)[i], f)
+		}
+	}
+}
+
+func setFromDenom(d, f *Denom) {
+	if f.Base != nil {
+		d.Base = f.Base
+	}
+	if f.Display != nil {
+		d.Display = f.Display
+	}
+	if f.Exponent != nil {
+		d.Exponent = f.Exponent
+	}
 }
 
 func legacyNode(n *Node, id string) db.Node {
@@ -243,6 +816,16 @@ type TOMLConfig struct {
 	Enabled *bool
 	Chain
 	Nodes Nodes
+	// Denoms describe the units of denominations, in place of their bank metadata.
+	Denoms Denoms
+	// FeeDenoms are used in order when the balance of the GasToken does not cover the fee.
+	FeeDenoms FeeDenoms
+	// TxPolicies restrict the msgs sent by each sender.
+	TxPolicies TxPolicies
+	// Multisigs are the multisig accounts which may send msgs.
+	Multisigs Multisigs
+	// VerifiedReads configures the verification of contract state reads.
+	VerifiedReads VerifiedReads
 }
 
 func (c *TOMLConfig) IsEnabled() bool {
@@ -258,11 +841,19 @@ func (c *TOMLConfig) SetFrom(f *TOMLConfig) {
 	}
 	setFromChain(&c.Chain, &f.Chain)
 	c.Nodes.SetFrom(&f.Nodes)
+	c.Denoms.SetFrom(&f.Denoms)
+	c.FeeDenoms.SetFrom(&f.FeeDenoms)
+	c.TxPolicies.SetFrom(&f.TxPolicies)
+	c.Multisigs.SetFrom(&f.Multisigs)
+	c.VerifiedReads.SetFrom(&f.VerifiedReads)
 }
 
 func setFromChain(c, f *Chain) {
+	if f.Adapter != nil {
+		c.Adapter = f.Adapter
+	}
 	if f.Bech32Prefix != nil {
-		c.Bech32Prefix = f.Bech32Prefix
+		c.Bech32Prefix, c.bech32PrefixSource = f.Bech32Prefix, f.bech32PrefixSource
 	}
 	if f.BlockRate != nil {
 		c.BlockRate = f.BlockRate
@@ -270,30 +861,57 @@ func setFromChain(c, f *Chain) {
 	if f.BlocksUntilTxTimeout != nil {
 		c.BlocksUntilTxTimeout = f.BlocksUntilTxTimeout
 	}
+	if f.BroadcastFanOut != nil {
+		c.BroadcastFanOut = f.BroadcastFanOut
+	}
 	if f.ConfirmPollPeriod != nil {
 		c.ConfirmPollPeriod = f.ConfirmPollPeriod
 	}
+	if f.DetectAutoFill != nil {
+		c.DetectAutoFill = f.DetectAutoFill
+	}
+	if f.DetectMode != nil {
+		c.DetectMode = f.DetectMode
+	}
 	if f.FallbackGasPrice != nil {
 		c.FallbackGasPrice = f.FallbackGasPrice
 	}
+	if f.GasPriceSource != nil {
+		c.GasPriceSource = f.GasPriceSource
+	}
 	if f.GasToken != nil {
-		c.GasToken = f.GasToken
+		c.GasToken, c.gasTokenSource = f.GasToken, f.gasTokenSource
 	}
 	if f.GasLimitMultiplier != nil {
 		c.GasLimitMultiplier = f.GasLimitMultiplier
 	}
+	if f.KeyAlgorithm != nil {
+		c.KeyAlgorithm = f.KeyAlgorithm
+	}
+	if f.MaxGasPrice != nil {
+		c.MaxGasPrice = f.MaxGasPrice
+	}
 	if f.MaxMsgsPerBatch != nil {
 		c.MaxMsgsPerBatch = f.MaxMsgsPerBatch
 	}
+	if f.MinGasPrice != nil {
+		c.MinGasPrice = f.MinGasPrice
+	}
 	if f.OCR2CachePollPeriod != nil {
 		c.OCR2CachePollPeriod = f.OCR2CachePollPeriod
 	}
 	if f.OCR2CacheTTL != nil {
 		c.OCR2CacheTTL = f.OCR2CacheTTL
 	}
+	if f.SignMode != nil {
+		c.SignMode = f.SignMode
+	}
 	if f.TxMsgTimeout != nil {
 		c.TxMsgTimeout = f.TxMsgTimeout
 	}
+	if f.UnorderedTxs != nil {
+		c.UnorderedTxs = f.UnorderedTxs
+	}
 }
 
 func (c *TOMLConfig) ValidateConfig() (err error) {
@@ -305,13 +923,68 @@ func (c *TOMLConfig) ValidateConfig() (err error) {
 
 	if len(c.Nodes) == 0 {
 		err = errors.Join(err, config.ErrMissing{Name: "Nodes", Msg: "must have at least one node"})
+	} else if !slices.ContainsFunc(c.Nodes, func(n *Node) bool { return !n.IsSendOnly() }) {
+		err = errors.Join(err, config.ErrInvalid{Name: "Nodes", Value: len(c.Nodes), Msg: "must have at least one primary node"})
+	}
+
+	bases := config.UniqueStrings{}
+	for i, d := range c.Denoms {
+		if bases.IsDupe(d.Base) {
+			err = errors.Join(err, config.NewErrDuplicate(fmt.Sprintf("Denoms.%d.Base", i), *d.Base))
+		}
 	}
 
+	feeDenoms := config.UniqueStrings{}
+	feeDenoms.IsDupe(c.Chain.GasToken)
+	for i, d := range c.FeeDenoms {
+		if feeDenoms.IsDupe(d.Denom) {
+			err = errors.Join(err, config.NewErrDuplicate(fmt.Sprintf("FeeDenoms.%d.Denom", i), *d.Denom))
+		}
+	}
+
+	senders := config.UniqueStrings{}
+	var hasDefaultPolicy bool
+	for i, p := range c.TxPolicies {
+		if p.Sender == nil {
+			if hasDefaultPolicy {
+				err = errors.Join(err, config.ErrInvalid{Name: fmt.Sprintf("TxPolicies.%d.Sender", i), Value: nil, Msg: "only one policy may apply to all senders"})
+			}
+			hasDefaultPolicy = true
+		} else if senders.IsDupe(p.Sender) {
+			err = errors.Join(err, config.NewErrDuplicate(fmt.Sprintf("TxPolicies.%d.Sender", i), *p.Sender))
+		}
+	}
+
+	multisigs := config.UniqueStrings{}
+	for i, m := range c.Multisigs {
+		if multisigs.IsDupe(m.Name) {
+			err = errors.Join(err, config.NewErrDuplicate(fmt.Sprintf("Multisigs.%d.Name", i), *m.Name))
+		}
+	}
+	if len(c.Multisigs) > 0 && c.Chain.KeyAlgorithm != nil && *c.Chain.KeyAlgorithm != KeyAlgorithmSecp256k1 {
+		err = errors.Join(err, config.ErrInvalid{Name: "Multisigs", Value: len(c.Multisigs),
+			Msg: fmt.Sprintf("require KeyAlgorithm %s", KeyAlgorithmSecp256k1)})
+	}
+
+	if c.VerifiedReads.IsEnabled() && len(c.Nodes) < 2 {
+		err = errors.Join(err, config.ErrInvalid{Name: "Nodes", Value: len(c.Nodes),
+			Msg: "verified reads require at least two nodes, to cross-check headers"})
+	}
+
+	// the embedded Chain is not validated by config.Validate
+	err = errors.Join(err, c.Chain.ValidateConfig())
+
 	return
 }
 
+// TOMLString returns the config as TOML, with secrets redacted.
 func (c *TOMLConfig) TOMLString() (string, error) {
-	b, err := toml.Marshal(c)
+	redactedCfg := *c
+	redactedCfg.Nodes = make(Nodes, len(c.Nodes))
+	for i, n := range c.Nodes {
+		redactedCfg.Nodes[i] = n.Redacted()
+	}
+	b, err := toml.Marshal(&redactedCfg)
 	if err != nil {
 		return "", err
 	}
@@ -320,6 +993,14 @@ func (c *TOMLConfig) TOMLString() (string, error) {
 
 var _ Config = &TOMLConfig{}
 
+// Adapter returns the name of the adapter, or "" if unset.
+func (c *TOMLConfig) Adapter() string {
+	if c.Chain.Adapter == nil {
+		return ""
+	}
+	return *c.Chain.Adapter
+}
+
 func (c *TOMLConfig) Bech32Prefix() string {
 	return *c.Chain.Bech32Prefix
 }
@@ -332,6 +1013,10 @@ func (c *TOMLConfig) BlocksUntilTxTimeout() int64 {
 	return *c.Chain.BlocksUntilTxTimeout
 }
 
+func (c *TOMLConfig) BroadcastFanOut() int64 {
+	return *c.Chain.BroadcastFanOut
+}
+
 func (c *TOMLConfig) ConfirmPollPeriod() time.Duration {
 	return c.Chain.ConfirmPollPeriod.Duration()
 }
@@ -340,6 +1025,35 @@ func (c *TOMLConfig) FallbackGasPrice() sdk.Dec {
 	return sdkDecFromDecimal(c.Chain.FallbackGasPrice)
 }
 
+func (c *TOMLConfig) FeeTokens() []FeeToken {
+	tokens := []FeeToken{{
+		Denom:            c.GasToken(),
+		FallbackGasPrice: c.FallbackGasPrice(),
+		MaxGasPrice:      c.MaxGasPrice(),
+		MinGasPrice:      c.MinGasPrice(),
+		PriceSource:      *c.Chain.GasPriceSource,
+	}}
+	for _, d := range c.FeeDenoms {
+		t := FeeToken{
+			Denom:            *d.Denom,
+			FallbackGasPrice: sdkDecFromDecimal(d.FallbackGasPrice),
+			MinGasPrice:      sdk.ZeroDec(),
+			PriceSource:      PriceSourceFixed,
+		}
+		if d.MaxGasPrice != nil {
+			t.MaxGasPrice = sdkDecFromDecimal(d.MaxGasPrice)
+		}
+		if d.MinGasPrice != nil {
+			t.MinGasPrice = sdkDecFromDecimal(d.MinGasPrice)
+		}
+		if d.PriceSource != nil {
+			t.PriceSource = *d.PriceSource
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens
+}
+
 func (c *TOMLConfig) GasToken() string {
 	return *c.Chain.GasToken
 }
@@ -348,10 +1062,30 @@ func (c *TOMLConfig) GasLimitMultiplier() float64 {
 	return c.Chain.GasLimitMultiplier.InexactFloat64()
 }
 
+func (c *TOMLConfig) KeyAlgorithm() KeyAlgorithm {
+	return *c.Chain.KeyAlgorithm
+}
+
+// MaxGasPrice returns the maximum gas price, or a nil sdk.Dec if unset.
+func (c *TOMLConfig) MaxGasPrice() sdk.Dec {
+	if c.Chain.MaxGasPrice == nil {
+		return sdk.Dec{}
+	}
+	return sdkDecFromDecimal(c.Chain.MaxGasPrice)
+}
+
 func (c *TOMLConfig) MaxMsgsPerBatch() int64 {
 	return *c.Chain.MaxMsgsPerBatch
 }
 
+// MinGasPrice returns the minimum gas price, or zero if unset.
+func (c *TOMLConfig) MinGasPrice() sdk.Dec {
+	if c.Chain.MinGasPrice == nil {
+		return sdk.ZeroDec()
+	}
+	return sdkDecFromDecimal(c.Chain.MinGasPrice)
+}
+
 func (c *TOMLConfig) OCR2CachePollPeriod() time.Duration {
 	return c.Chain.OCR2CachePollPeriod.Duration()
 }
@@ -360,15 +1094,68 @@ func (c *TOMLConfig) OCR2CacheTTL() time.Duration {
 	return c.Chain.OCR2CacheTTL.Duration()
 }
 
+func (c *TOMLConfig) SignMode() SignMode {
+	return *c.Chain.SignMode
+}
+
 func (c *TOMLConfig) TxMsgTimeout() time.Duration {
 	return c.Chain.TxMsgTimeout.Duration()
 }
 
+func (c *TOMLConfig) TrustOptions() (light.TrustOptions, bool) {
+	if !c.VerifiedReads.IsEnabled() {
+		return light.TrustOptions{}, false
+	}
+	return c.VerifiedReads.TrustOptions(), true
+}
+
+func (c *TOMLConfig) UnorderedTxs() bool {
+	return *c.Chain.UnorderedTxs
+}
+
 func sdkDecFromDecimal(d *decimal.Decimal) sdk.Dec {
 	i := d.Shift(sdk.Precision)
 	return sdk.NewDecFromBigIntWithPrec(i.BigInt(), sdk.Precision)
 }
 
+// PickNode returns a random primary node, picked in proportion to the node weights.
+func (c *TOMLConfig) PickNode() (*Node, error) {
+	var total int64
+	for _, n := range c.Nodes {
+		if !n.IsSendOnly() {
+			total += int64(n.GetWeight())
+		}
+	}
+	if total == 0 {
+		return nil, errors.New("no primary nodes available")
+	}
+	r, err := rand.Int(rand.Reader, big.NewInt(total))
+	if err != nil {
+		return nil, fmt.Errorf("could not generate a random node index: %w", err)
+	}
+	pick := r.Int64()
+	for _, n := range c.Nodes {
+		if n.IsSendOnly() {
+			continue
+		}
+		if pick < int64(n.GetWeight()) {
+			return n, nil
+		}
+		pick -= int64(n.GetWeight())
+	}
+	return nil, errors.New("no primary nodes available")
+}
+
+// GetNodeConfig returns the node named name.
+func (c *TOMLConfig) GetNodeConfig(name string) (*Node, error) {
+	for _, n := range c.Nodes {
+		if n.Name != nil && *n.Name == name {
+			return n, nil
+		}
+	}
+	return nil, fmt.Errorf("node not found")
+}
+
 func (c *TOMLConfig) GetNode(name string) (db.Node, error) {
 	for _, n := range c.Nodes {
 		if *n.Name == name {
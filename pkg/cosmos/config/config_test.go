@@ -1,12 +1,19 @@
 package config
 
 import (
+	"net/http"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pelletier/go-toml/v2"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/goplugin/plugin-common/pkg/config"
 
@@ -91,6 +98,272 @@ func TestCosmosConfig_GetNode(t *testing.T) {
 	}
 }
 
+func TestNode_secrets(t *testing.T) {
+	const input = `
+ChainID = 'Chainlink-99'
+
+[[Nodes]]
+Name = 'primary'
+TendermintURL = 'https://rpc.example.com'
+RequestTimeout = '5s'
+Username = 'user'
+Password = 'hunter2'
+Weight = 3
+
+[Nodes.Headers]
+X-Api-Key = 'api-key-secret'
+`
+	var c TOMLConfig
+	require.NoError(t, toml.Unmarshal([]byte(input), &c))
+	n := c.Nodes[0]
+	assert.Equal(t, 5*time.Second, n.GetRequestTimeout())
+	assert.Equal(t, uint32(3), n.GetWeight())
+	assert.False(t, n.IsSendOnly())
+
+	opts, err := n.HTTPOptions()
+	require.NoError(t, err)
+	assert.Equal(t, http.Header{"X-Api-Key": []string{"api-key-secret"}}, opts.Headers)
+	assert.Equal(t, "user", opts.Username)
+	assert.Equal(t, "hunter2", opts.Password)
+
+	out, err := c.TOMLString()
+	require.NoError(t, err)
+	assert.NotContains(t, out, "hunter2")
+	assert.NotContains(t, out, "api-key-secret")
+	assert.Contains(t, out, "user")
+	assert.Equal(t, 2, strings.Count(out, redacted))
+	assert.Equal(t, Secret("hunter2"), *n.Password, "not modified")
+
+	// secrets round trip
+	b, err := toml.Marshal(&c)
+	require.NoError(t, err)
+	var got TOMLConfig
+	require.NoError(t, toml.Unmarshal(b, &got))
+	assert.Equal(t, c.Nodes, got.Nodes)
+}
+
+func TestNode_ValidateConfig(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0600))
+	valid := func() *Node {
+		return &Node{Name: ptr("node"), TendermintURL: config.MustParseURL("http://localhost:26657")}
+	}
+	for _, tt := range []struct {
+		name   string
+		modify func(*Node)
+		errStr string
+	}{
+		{name: "valid", modify: func(*Node) {}},
+		{name: "timeout", modify: func(n *Node) { n.RequestTimeout = config.MustNewDuration(0) }, errStr: "RequestTimeout: invalid value (0s): must be positive"},
+		{name: "password", modify: func(n *Node) { n.Username = ptr("user") }, errStr: "Password: missing: required with Username"},
+		{name: "username", modify: func(n *Node) { n.Password = ptr(Secret("pass")) }, errStr: "Username: missing: required with Password"},
+		{name: "priority", modify: func(n *Node) { n.Priority = ptr(NodePriority("backup")) }, errStr: "Priority: invalid value (backup): must be primary or sendonly"},
+		{name: "weight", modify: func(n *Node) { n.Weight = ptr(uint32(0)) }, errStr: "Weight: invalid value (0): must be positive"},
+		{name: "tls ca file", modify: func(n *Node) { n.TLSCAFile = &caFile }, errStr: "no PEM certificates found"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			n := valid()
+			tt.modify(n)
+			err := n.ValidateConfig()
+			if tt.errStr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.errStr)
+		})
+	}
+}
+
+func TestChain_ValidateConfig(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		modify func(*Chain)
+		errStr string
+	}{
+		{name: "defaults", modify: func(*Chain) {}},
+		{name: "bech32 prefix", modify: func(c *Chain) { c.Bech32Prefix = ptr("Wasm-1") }, errStr: "Bech32Prefix: invalid value (Wasm-1): must be lowercase alphanumeric, starting with a letter"},
+		{name: "empty bech32 prefix", modify: func(c *Chain) { c.Bech32Prefix = ptr("") }, errStr: "Bech32Prefix: empty: required for all chains"},
+		{name: "block rate", modify: func(c *Chain) { c.BlockRate = config.MustNewDuration(0) }, errStr: "BlockRate: invalid value (0s): must be positive"},
+		{name: "confirm poll period", modify: func(c *Chain) { c.ConfirmPollPeriod = config.MustNewDuration(0) }, errStr: "ConfirmPollPeriod: invalid value (0s): must be positive"},
+		{name: "slow confirm poll period", modify: func(c *Chain) { c.ConfirmPollPeriod = config.MustNewDuration(time.Hour) },
+			errStr: "ConfirmPollPeriod: invalid value (1h0m0s): must not exceed BlockRate * BlocksUntilTxTimeout (3m0s)"},
+		{name: "detect mode", modify: func(c *Chain) { c.DetectMode = ptr(DetectMode("fill")) }, errStr: "DetectMode: invalid value (fill): must be off, warn or refuse"},
+		{name: "blocks until tx timeout", modify: func(c *Chain) { c.BlocksUntilTxTimeout = ptr[int64](-1) }, errStr: "BlocksUntilTxTimeout: invalid value (-1): must be positive"},
+		{name: "ocr2 cache ttl", modify: func(c *Chain) { c.OCR2CacheTTL = config.MustNewDuration(time.Second) },
+			errStr: "OCR2CacheTTL: invalid value (1s): must be at least OCR2CachePollPeriod (4s)"},
+		{name: "tx msg timeout", modify: func(c *Chain) { c.TxMsgTimeout = config.MustNewDuration(0) }, errStr: "TxMsgTimeout: invalid value (0s): must be positive"},
+		{name: "fallback gas price", modify: func(c *Chain) { c.FallbackGasPrice = ptr(decimal.RequireFromString("-0.1")) }, errStr: "FallbackGasPrice: invalid value (-0.1): must not be negative"},
+		{name: "gas limit multiplier", modify: func(c *Chain) { c.GasLimitMultiplier = ptr(decimal.RequireFromString("0.9")) }, errStr: "GasLimitMultiplier: invalid value (0.9): must be at least 1"},
+		{name: "min gas price", modify: func(c *Chain) { c.MinGasPrice = ptr(decimal.RequireFromString("-1")) }, errStr: "MinGasPrice: invalid value (-1): must not be negative"},
+		{name: "max gas price", modify: func(c *Chain) { c.MaxGasPrice = ptr(decimal.RequireFromString("0.01")) }, errStr: "MaxGasPrice: invalid value (0.01): must be at least FallbackGasPrice (0.015)"},
+		{name: "max below min gas price", modify: func(c *Chain) {
+			c.MinGasPrice = ptr(decimal.RequireFromString("0.1"))
+			c.MaxGasPrice = ptr(decimal.RequireFromString("0.05"))
+		}, errStr: "MaxGasPrice: invalid value (0.05): must be at least MinGasPrice (0.1)"},
+		{name: "gas price source", modify: func(c *Chain) { c.GasPriceSource = ptr(PriceSource("oracle")) }, errStr: "GasPriceSource: invalid value (oracle): must be fixed or node"},
+		{name: "empty gas token", modify: func(c *Chain) { c.GasToken = ptr("") }, errStr: "GasToken: empty: required for all chains"},
+		{name: "gas token", modify: func(c *Chain) { c.GasToken = ptr("1cosm") }, errStr: "GasToken: invalid value (1cosm): invalid denom: 1cosm"},
+		{name: "key algorithm", modify: func(c *Chain) { c.KeyAlgorithm = ptr(KeyAlgorithm("ed25519")) }, errStr: "KeyAlgorithm: invalid value (ed25519): must be secp256k1, eth_secp256k1 or injective_eth_secp256k1"},
+		{name: "sign mode", modify: func(c *Chain) { c.SignMode = ptr(SignMode("textual")) }, errStr: "SignMode: invalid value (textual): must be direct or amino-json"},
+		{name: "unordered txs", modify: func(c *Chain) { c.UnorderedTxs, c.SignMode = ptr(true), ptr(SignModeAminoJSON) }, errStr: "UnorderedTxs: invalid value (true): requires SignMode direct"},
+		{name: "broadcast fan out", modify: func(c *Chain) { c.BroadcastFanOut = ptr[int64](-1) }, errStr: "BroadcastFanOut: invalid value (-1): must not be negative"},
+		{name: "max msgs per batch", modify: func(c *Chain) { c.MaxMsgsPerBatch = ptr[int64](0) }, errStr: "MaxMsgsPerBatch: invalid value (0): must be positive"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var c Chain
+			c.SetDefaults()
+			tt.modify(&c)
+			err := c.ValidateConfig()
+			if tt.errStr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.errStr)
+		})
+	}
+}
+
+func TestDenom_ValidateConfig(t *testing.T) {
+	valid := func() *Denom {
+		return &Denom{Base: ptr("inj"), Display: ptr("INJ"), Exponent: ptr(uint32(18))}
+	}
+	for _, tt := range []struct {
+		name   string
+		modify func(*Denom)
+		errStr string
+	}{
+		{name: "valid", modify: func(*Denom) {}},
+		{name: "base only", modify: func(d *Denom) { d.Display, d.Exponent = nil, nil }},
+		{name: "base", modify: func(d *Denom) { d.Base = nil }, errStr: "Base: missing: required for all denoms"},
+		{name: "invalid base", modify: func(d *Denom) { d.Base = ptr("1inj") }, errStr: "Base: invalid value (1inj): invalid denom: 1inj"},
+		{name: "exponent", modify: func(d *Denom) { d.Exponent = nil }, errStr: "Exponent: missing: required with Display"},
+		{name: "display", modify: func(d *Denom) { d.Display = nil }, errStr: "Display: missing: required with Exponent"},
+		{name: "zero exponent", modify: func(d *Denom) { d.Exponent = ptr(uint32(0)) }, errStr: "Exponent: invalid value (0): must be positive"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			d := valid()
+			tt.modify(d)
+			err := d.ValidateConfig()
+			if tt.errStr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.errStr)
+		})
+	}
+}
+
+func TestFeeDenom_ValidateConfig(t *testing.T) {
+	valid := func() *FeeDenom {
+		return &FeeDenom{Denom: ptr("uatom"), FallbackGasPrice: ptr(decimal.RequireFromString("0.025"))}
+	}
+	for _, tt := range []struct {
+		name   string
+		modify func(*FeeDenom)
+		errStr string
+	}{
+		{name: "valid", modify: func(*FeeDenom) {}},
+		{name: "node", modify: func(d *FeeDenom) { d.PriceSource = ptr(PriceSourceNode) }},
+		{name: "denom", modify: func(d *FeeDenom) { d.Denom = nil }, errStr: "Denom: missing: required for all fee denoms"},
+		{name: "invalid denom", modify: func(d *FeeDenom) { d.Denom = ptr("1atom") }, errStr: "Denom: invalid value (1atom): invalid denom: 1atom"},
+		{name: "fallback gas price", modify: func(d *FeeDenom) { d.FallbackGasPrice = nil }, errStr: "FallbackGasPrice: missing: required for all fee denoms"},
+		{name: "negative fallback gas price", modify: func(d *FeeDenom) { d.FallbackGasPrice = ptr(decimal.RequireFromString("-1")) },
+			errStr: "FallbackGasPrice: invalid value (-1): must not be negative"},
+		{name: "max gas price", modify: func(d *FeeDenom) { d.MaxGasPrice = ptr(decimal.RequireFromString("0.01")) },
+			errStr: "MaxGasPrice: invalid value (0.01): must be at least FallbackGasPrice (0.025)"},
+		{name: "price source", modify: func(d *FeeDenom) { d.PriceSource = ptr(PriceSource("oracle")) }, errStr: "PriceSource: invalid value (oracle): must be fixed or node"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			d := valid()
+			tt.modify(d)
+			err := d.ValidateConfig()
+			if tt.errStr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.errStr)
+		})
+	}
+}
+
+func TestTOMLConfig_FeeTokens(t *testing.T) {
+	c := &TOMLConfig{FeeDenoms: FeeDenoms{{
+		Denom:            ptr("uatom"),
+		FallbackGasPrice: ptr(decimal.RequireFromString("0.025")),
+		MaxGasPrice:      ptr(decimal.RequireFromString("0.1")),
+		PriceSource:      ptr(PriceSourceNode),
+	}}}
+	c.SetDefaults()
+	tokens := c.FeeTokens()
+	require.Len(t, tokens, 2)
+	assert.Equal(t, FeeToken{
+		Denom:            "ucosm",
+		FallbackGasPrice: sdk.MustNewDecFromStr("0.015"),
+		MinGasPrice:      sdk.ZeroDec(),
+		PriceSource:      PriceSourceFixed,
+	}, tokens[0])
+	assert.Equal(t, FeeToken{
+		Denom:            "uatom",
+		FallbackGasPrice: sdk.MustNewDecFromStr("0.025"),
+		MaxGasPrice:      sdk.MustNewDecFromStr("0.1"),
+		MinGasPrice:      sdk.ZeroDec(),
+		PriceSource:      PriceSourceNode,
+	}, tokens[1])
+}
+
+func TestTOMLConfigs_ValidateConfig(t *testing.T) {
+	cs := TOMLConfigs{{
+		ChainID: ptr("Chainlink-99"),
+		Chain:   Chain{MaxMsgsPerBatch: ptr[int64](-1)},
+		Nodes:   Nodes{{Name: ptr("node"), TendermintURL: config.MustParseURL("http://localhost:26657")}},
+	}}
+	cs[0].SetDefaults()
+	require.ErrorContains(t, config.Validate(cs), "0.MaxMsgsPerBatch: invalid value (-1): must be positive")
+
+	cs[0].Chain.MaxMsgsPerBatch = ptr[int64](1)
+	cs[0].Denoms = Denoms{{Base: ptr("uatom")}, {Base: ptr("uatom"), Display: ptr("atom"), Exponent: ptr(uint32(6))}}
+	require.ErrorContains(t, config.Validate(cs), "Denoms.1.Base: invalid value (uatom): duplicate - must be unique")
+
+	cs[0].Denoms = nil
+	cs[0].FeeDenoms = FeeDenoms{{Denom: ptr("ucosm"), FallbackGasPrice: ptr(decimal.RequireFromString("0.01"))}}
+	require.ErrorContains(t, config.Validate(cs), "FeeDenoms.0.Denom: invalid value (ucosm): duplicate - must be unique")
+
+	cs[0].FeeDenoms = nil
+	cs[0].TxPolicies = TxPolicies{{MaxSend: ptr("1ucosm")}, {MaxFunds: ptr("1ucosm")}}
+	require.ErrorContains(t, config.Validate(cs), "TxPolicies.1.Sender: invalid value (<nil>): only one policy may apply to all senders")
+}
+
+func TestTOMLConfig_PickNode(t *testing.T) {
+	c := &TOMLConfig{Nodes: Nodes{
+		{Name: ptr("sendonly"), Priority: ptr(NodePrioritySendOnly), Weight: ptr(uint32(100))},
+		{Name: ptr("heavy"), Weight: ptr(uint32(3))},
+		{Name: ptr("light")},
+	}}
+	counts := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		n, err := c.PickNode()
+		require.NoError(t, err)
+		counts[*n.Name]++
+	}
+	assert.Zero(t, counts["sendonly"])
+	assert.InDelta(t, 3000, counts["heavy"], 300)
+	assert.InDelta(t, 1000, counts["light"], 300)
+
+	c.Nodes = c.Nodes[:1]
+	_, err := c.PickNode()
+	require.ErrorContains(t, err, "no primary nodes available")
+	require.ErrorContains(t, c.ValidateConfig(), "must have at least one primary node")
+}
+
+func TestNodes_SetFrom(t *testing.T) {
+	ns := Nodes{{Name: ptr("node"), Weight: ptr(uint32(1)), Headers: map[string]Secret{"A": "1"}}}
+	ns.SetFrom(&Nodes{{Name: ptr("node"), Weight: ptr(uint32(2)), Password: ptr(Secret("pass"))}})
+	require.Len(t, ns, 1)
+	assert.Equal(t, uint32(2), *ns[0].Weight)
+	assert.Equal(t, Secret("pass"), *ns[0].Password)
+	assert.Equal(t, map[string]Secret{"A": "1"}, ns[0].Headers)
+}
+
 func ptr[T any](t T) *T {
 	return &t
 }
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/shopspring/decimal"
+
+	"github.com/goplugin/plugin-common/pkg/config"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
+)
+
+// DetectMode determines what happens when the config disagrees with the chain params detected from a node.
+type DetectMode string
+
+const (
+	// DetectModeOff skips detection.
+	DetectModeOff DetectMode = "off"
+	// DetectModeWarn logs each disagreement.
+	DetectModeWarn DetectMode = "warn"
+	// DetectModeRefuse fails to start the chain if there are any disagreements.
+	DetectModeRefuse DetectMode = "refuse"
+)
+
+// valueSource is where a config value came from.
+type valueSource uint8
+
+const (
+	// sourceConfig values are configured.
+	sourceConfig valueSource = iota
+	// sourceDefault values are set by SetDefaults.
+	sourceDefault
+	// sourceDetected values are filled in from the chain params detected from a node.
+	sourceDetected
+)
+
+// blockRateTolerance is the fraction by which the BlockRate may differ from the detected block rate.
+const blockRateTolerance = 0.25
+
+// ReconcileChainParams compares c with the chain params detected from a node. It returns a copy of c and an error
+// describing each remaining disagreement. If DetectAutoFill is enabled, the copy has the unset Bech32Prefix and
+// GasToken filled in with the detected bech32 prefix and fee denom, and the BlockRate and MinGasPrice replaced by
+// the detected values. Configured Bech32Prefix and GasToken are never replaced.
+// The ChainID must always match, since a node for another chain cannot be used at all.
+func (c *TOMLConfig) ReconcileChainParams(p params.ChainParams) (next *TOMLConfig, err error) {
+	cp := *c
+	next = &cp
+	autoFill := c.Chain.DetectAutoFill != nil && *c.Chain.DetectAutoFill
+
+	if p.ChainID != *c.ChainID {
+		return nil, config.ErrInvalid{Name: "ChainID", Value: *c.ChainID, Msg: fmt.Sprintf("node reports chain ID %s", p.ChainID)}
+	}
+	if autoFill && c.Chain.bech32PrefixSource != sourceConfig && p.Bech32Prefix != "" {
+		next.Chain.Bech32Prefix, next.Chain.bech32PrefixSource = &p.Bech32Prefix, sourceDetected
+	}
+	if p.Bech32Prefix != next.Bech32Prefix() {
+		err = errors.Join(err, config.ErrInvalid{Name: "Bech32Prefix", Value: next.Bech32Prefix(),
+			Msg: fmt.Sprintf("node reports bech32 prefix %s", p.Bech32Prefix)})
+	}
+
+	if feeDenom := detectedFeeDenom(p); autoFill && c.Chain.gasTokenSource != sourceConfig && feeDenom != "" {
+		next.Chain.GasToken, next.Chain.gasTokenSource = &feeDenom, sourceDetected
+	}
+	gasToken := next.GasToken()
+	var nodeMin decimal.Decimal
+	var hasMinGasPrice bool
+	for _, price := range p.MinGasPrices {
+		if price.Denom == gasToken {
+			nodeMin, hasMinGasPrice = decimal.NewFromBigInt(price.Amount.BigInt(), -sdk.Precision), true
+		}
+	}
+	if gasToken != p.BondDenom && len(p.MinGasPrices) > 0 && !hasMinGasPrice {
+		err = errors.Join(err, config.ErrInvalid{Name: "GasToken", Value: gasToken,
+			Msg: fmt.Sprintf("node accepts fees in %s", p.MinGasPrices)})
+	}
+	if hasMinGasPrice {
+		if cur := c.Chain.MinGasPrice; cur == nil || cur.LessThan(nodeMin) {
+			if autoFill {
+				next.Chain.MinGasPrice = &nodeMin
+			} else if c.Chain.FallbackGasPrice.LessThan(nodeMin) {
+				err = errors.Join(err, config.ErrInvalid{Name: "FallbackGasPrice", Value: c.Chain.FallbackGasPrice.String(),
+					Msg: fmt.Sprintf("below the node minimum gas price %s", nodeMin)})
+			}
+		}
+	}
+
+	if p.BlockRate > 0 {
+		rate := c.BlockRate()
+		if diff := (rate - p.BlockRate).Abs(); float64(diff) > blockRateTolerance*float64(p.BlockRate) {
+			if autoFill {
+				next.Chain.BlockRate = config.MustNewDuration(p.BlockRate.Round(time.Millisecond))
+			} else {
+				err = errors.Join(err, config.ErrInvalid{Name: "BlockRate", Value: rate,
+					Msg: fmt.Sprintf("node produces a block every %s", p.BlockRate.Round(time.Millisecond))})
+			}
+		}
+	}
+	return
+}
+
+// detectedFeeDenom returns the denom in which fees are paid according to p: the BondDenom, if the node accepts
+// fees in it, otherwise the first denom of the MinGasPrices.
+func detectedFeeDenom(p params.ChainParams) string {
+	if len(p.MinGasPrices) == 0 {
+		return p.BondDenom
+	}
+	for _, price := range p.MinGasPrices {
+		if price.Denom == p.BondDenom {
+			return p.BondDenom
+		}
+	}
+	return p.MinGasPrices[0].Denom
+}
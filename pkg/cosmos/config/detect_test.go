@@ -0,0 +1,119 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goplugin/plugin-common/pkg/config"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
+)
+
+func TestTOMLConfig_ReconcileChainParams(t *testing.T) {
+	detected := params.ChainParams{
+		ChainID:      "osmosis-1",
+		Bech32Prefix: "osmo",
+		BondDenom:    "uosmo",
+		MinGasPrices: sdk.NewDecCoins(sdk.NewDecCoinFromDec("uosmo", sdk.MustNewDecFromStr("0.0025"))),
+		BlockRate:    5 * time.Second,
+	}
+	newConfig := func(modify func(*TOMLConfig)) *TOMLConfig {
+		c := &TOMLConfig{ChainID: ptr("osmosis-1"), Chain: Chain{
+			Bech32Prefix: ptr("osmo"),
+			GasToken:     ptr("uosmo"),
+		}}
+		modify(c)
+		c.SetDefaults()
+		return c
+	}
+
+	for _, tt := range []struct {
+		name   string
+		modify func(*TOMLConfig)
+		errStr string
+		check  func(t *testing.T, next *TOMLConfig)
+	}{
+		{name: "agrees", modify: func(*TOMLConfig) {}},
+		{name: "bech32 prefix", modify: func(c *TOMLConfig) { c.Chain.Bech32Prefix = ptr("wasm") },
+			errStr: "Bech32Prefix: invalid value (wasm): node reports bech32 prefix osmo"},
+		{name: "gas token", modify: func(c *TOMLConfig) { c.Chain.GasToken = ptr("uion") },
+			errStr: "GasToken: invalid value (uion): node accepts fees in 0.002500000000000000uosmo"},
+		{name: "fallback gas price", modify: func(c *TOMLConfig) { c.Chain.FallbackGasPrice = ptr(decimal.RequireFromString("0.001")) },
+			errStr: "FallbackGasPrice: invalid value (0.001): below the node minimum gas price 0.0025"},
+		{name: "block rate", modify: func(c *TOMLConfig) { c.Chain.BlockRate = config.MustNewDuration(time.Second) },
+			errStr: "BlockRate: invalid value (1s): node produces a block every 5s"},
+		{name: "block rate within tolerance", modify: func(c *TOMLConfig) { c.Chain.BlockRate = config.MustNewDuration(6 * time.Second) }},
+		{name: "auto fill rates", modify: func(c *TOMLConfig) {
+			c.Chain.DetectAutoFill = ptr(true)
+			c.Chain.BlockRate = config.MustNewDuration(time.Second)
+			c.Chain.FallbackGasPrice = ptr(decimal.RequireFromString("0.001"))
+		}, check: func(t *testing.T, next *TOMLConfig) {
+			assert.Equal(t, 5*time.Second, next.BlockRate())
+			assert.Equal(t, sdk.MustNewDecFromStr("0.0025"), next.MinGasPrice())
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newConfig(tt.modify)
+			next, err := c.ReconcileChainParams(detected)
+			require.NotNil(t, next)
+			if tt.errStr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.errStr)
+			}
+			if tt.check != nil {
+				tt.check(t, next)
+			}
+		})
+	}
+
+	t.Run("auto fill", func(t *testing.T) {
+		unset := func(c *TOMLConfig) {
+			c.Chain.Bech32Prefix, c.Chain.GasToken = nil, nil
+			c.Chain.DetectAutoFill = ptr(true)
+			c.Nodes = Nodes{{Name: ptr("node"), TendermintURL: config.MustParseURL("http://localhost:26657")}}
+		}
+		next, err := newConfig(unset).ReconcileChainParams(detected)
+		require.NoError(t, err)
+		assert.Equal(t, "osmo", next.Bech32Prefix())
+		assert.Equal(t, "uosmo", next.GasToken())
+		assert.Equal(t, sdk.MustNewDecFromStr("0.0025"), next.MinGasPrice())
+
+		r := NewReloadable(newConfig(unset))
+		_, err = r.Reload(next)
+		require.NoError(t, err, "the unset prefix can be filled in")
+		reloaded := newConfig(unset)
+		reloaded.Chain.MaxMsgsPerBatch = ptr[int64](1)
+		_, err = r.Reload(reloaded)
+		require.NoError(t, err)
+		assert.Equal(t, "osmo", r.Bech32Prefix(), "filled in values are kept")
+		assert.Equal(t, "uosmo", r.GasToken())
+
+		feeOnly := detected
+		feeOnly.MinGasPrices = sdk.NewDecCoins(sdk.NewDecCoinFromDec("uion", sdk.MustNewDecFromStr("0.01")))
+		next, err = newConfig(unset).ReconcileChainParams(feeOnly)
+		require.NoError(t, err)
+		assert.Equal(t, "uion", next.GasToken(), "the bond denom is not accepted for fees")
+
+		next, err = newConfig(func(c *TOMLConfig) {
+			c.Chain.DetectAutoFill = ptr(true)
+			c.Chain.Bech32Prefix, c.Chain.GasToken = ptr("wasm"), ptr("uion")
+		}).ReconcileChainParams(detected)
+		require.ErrorContains(t, err, "Bech32Prefix: invalid value (wasm): node reports bech32 prefix osmo")
+		require.ErrorContains(t, err, "GasToken: invalid value (uion): node accepts fees in 0.002500000000000000uosmo")
+		assert.Equal(t, "wasm", next.Bech32Prefix(), "configured values are not replaced")
+		assert.Equal(t, "uion", next.GasToken())
+	})
+
+	t.Run("chain id", func(t *testing.T) {
+		c := newConfig(func(c *TOMLConfig) { c.ChainID = ptr("osmo-test-5") })
+		next, err := c.ReconcileChainParams(detected)
+		require.ErrorContains(t, err, "ChainID: invalid value (osmo-test-5): node reports chain ID osmosis-1")
+		assert.Nil(t, next)
+	})
+}
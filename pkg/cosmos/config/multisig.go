@@ -0,0 +1,110 @@
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+
+	"github.com/goplugin/plugin-common/pkg/config"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
+)
+
+// Multisig is a multisig account, which sends txs with the signatures of Threshold of its cosigners.
+// The Txm signs with the keys of the cosigners in the keystore, and asks its Cosigner for the others.
+type Multisig struct {
+	// Name identifies the multisig in the config.
+	Name *string
+	// Threshold is the number of cosigners which must sign.
+	Threshold *uint32
+	// PubKeys are the hex-encoded compressed secp256k1 public keys of the cosigners, in the order of the multisig key.
+	PubKeys []string
+}
+
+func (m *Multisig) ValidateConfig() (err error) {
+	if m.Name == nil {
+		err = errors.Join(err, config.ErrMissing{Name: "Name", Msg: "required for all multisigs"})
+	} else if *m.Name == "" {
+		err = errors.Join(err, config.ErrEmpty{Name: "Name", Msg: "required for all multisigs"})
+	}
+	if len(m.PubKeys) == 0 {
+		err = errors.Join(err, config.ErrMissing{Name: "PubKeys", Msg: "required for all multisigs"})
+	}
+	keys := config.UniqueStrings{}
+	for i, k := range m.PubKeys {
+		if _, err1 := parseCosignerPubKey(k); err1 != nil {
+			err = errors.Join(err, config.ErrInvalid{Name: fmt.Sprintf("PubKeys.%d", i), Value: k, Msg: err1.Error()})
+		} else if keys.IsDupe(&k) {
+			err = errors.Join(err, config.NewErrDuplicate(fmt.Sprintf("PubKeys.%d", i), k))
+		}
+	}
+	if m.Threshold == nil {
+		err = errors.Join(err, config.ErrMissing{Name: "Threshold", Msg: "required for all multisigs"})
+	} else if *m.Threshold == 0 || int(*m.Threshold) > len(m.PubKeys) {
+		err = errors.Join(err, config.ErrInvalid{Name: "Threshold", Value: *m.Threshold,
+			Msg: fmt.Sprintf("must be between 1 and the number of PubKeys (%d)", len(m.PubKeys))})
+	}
+	return
+}
+
+func parseCosignerPubKey(s string) (cryptotypes.PubKey, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != secp256k1.PubKeySize {
+		return nil, fmt.Errorf("must be %d bytes", secp256k1.PubKeySize)
+	}
+	return &secp256k1.PubKey{Key: b}, nil
+}
+
+// PubKey returns the key of the multisig account. m must be valid.
+func (m *Multisig) PubKey() *kmultisig.LegacyAminoPubKey {
+	keys := make([]cryptotypes.PubKey, len(m.PubKeys))
+	for i, k := range m.PubKeys {
+		keys[i], _ = parseCosignerPubKey(k) // validated by ValidateConfig
+	}
+	return kmultisig.NewLegacyAminoPubKey(int(*m.Threshold), keys)
+}
+
+type Multisigs []*Multisig
+
+func (ms *Multisigs) SetFrom(fs *Multisigs) {
+	for _, f := range *fs {
+		if f.Name == nil {
+			*ms = append(*ms, f)
+		} else if i := slices.IndexFunc(*ms, func(m *Multisig) bool {
+			return m.Name != nil && *m.Name == *f.Name
+		}); i == -1 {
+			*ms = append(*ms, f)
+		} else {
+			setFromMultisig((*ms)[i], f)
+		}
+	}
+}
+
+func setFromMultisig(m, f *Multisig) {
+	if f.Threshold != nil {
+		m.Threshold = f.Threshold
+	}
+	if f.PubKeys != nil {
+		m.PubKeys = f.PubKeys
+	}
+}
+
+// MultisigPubKey returns the key of the multisig account with address, if it is one of the Multisigs.
+func (c *TOMLConfig) MultisigPubKey(address string) (*kmultisig.LegacyAminoPubKey, bool) {
+	codec := params.NewAddressCodec(c.Bech32Prefix())
+	for _, m := range c.Multisigs {
+		pubKey := m.PubKey()
+		if addr, err := codec.BytesToString(pubKey.Address().Bytes()); err == nil && addr == address {
+			return pubKey, true
+		}
+	}
+	return nil, false
+}
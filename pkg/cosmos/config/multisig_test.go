@@ -0,0 +1,81 @@
+package config
+
+import (
+	"encoding/hex"
+	"testing"
+
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
+)
+
+func testCosigners(n int) (keys []cryptotypes.PubKey, hexKeys []string) {
+	for i := 0; i < n; i++ {
+		key := secp256k1.GenPrivKey().PubKey()
+		keys = append(keys, key)
+		hexKeys = append(hexKeys, hex.EncodeToString(key.Bytes()))
+	}
+	return
+}
+
+func TestMultisig_ValidateConfig(t *testing.T) {
+	_, pubKeys := testCosigners(3)
+	for _, tt := range []struct {
+		name   string
+		modify func(*Multisig)
+		errStr string
+	}{
+		{name: "valid", modify: func(*Multisig) {}},
+		{name: "name", modify: func(m *Multisig) { m.Name = ptr("") }, errStr: "Name: empty: required for all multisigs"},
+		{name: "missing threshold", modify: func(m *Multisig) { m.Threshold = nil }, errStr: "Threshold: missing: required for all multisigs"},
+		{name: "zero threshold", modify: func(m *Multisig) { m.Threshold = ptr[uint32](0) }, errStr: "Threshold: invalid value (0): must be between 1 and the number of PubKeys (3)"},
+		{name: "high threshold", modify: func(m *Multisig) { m.Threshold = ptr[uint32](4) }, errStr: "Threshold: invalid value (4): must be between 1 and the number of PubKeys (3)"},
+		{name: "pubkey", modify: func(m *Multisig) { m.PubKeys[1] = m.PubKeys[1][2:] }, errStr: "PubKeys.1: invalid value"},
+		{name: "duplicate pubkey", modify: func(m *Multisig) { m.PubKeys[2] = m.PubKeys[0] }, errStr: "PubKeys.2: invalid value (" + pubKeys[0] + "): duplicate"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Multisig{Name: ptr("admin"), Threshold: ptr[uint32](2), PubKeys: append([]string{}, pubKeys...)}
+			tt.modify(m)
+			err := m.ValidateConfig()
+			if tt.errStr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.errStr)
+		})
+	}
+}
+
+func TestTOMLConfig_MultisigPubKey(t *testing.T) {
+	keys, pubKeys := testCosigners(3)
+	c := &TOMLConfig{Multisigs: Multisigs{{Name: ptr("admin"), Threshold: ptr[uint32](2), PubKeys: pubKeys}}}
+	c.SetDefaults()
+
+	want := kmultisig.NewLegacyAminoPubKey(2, keys)
+	address := params.NewAddressCodec(c.Bech32Prefix()).MustBytesToString(want.Address().Bytes())
+	got, ok := c.MultisigPubKey(address)
+	require.True(t, ok)
+	assert.True(t, want.Equals(got))
+
+	_, ok = c.MultisigPubKey(testAddress(t, 1))
+	assert.False(t, ok)
+
+	c.Multisigs = append(c.Multisigs, &Multisig{Name: ptr("admin"), Threshold: ptr[uint32](1), PubKeys: pubKeys[:1]})
+	c.Chain.KeyAlgorithm = ptr(KeyAlgorithmEthSecp256k1)
+	err := c.ValidateConfig()
+	assert.ErrorContains(t, err, "Multisigs.1.Name: invalid value (admin): duplicate")
+	assert.ErrorContains(t, err, "Multisigs: invalid value (2): require KeyAlgorithm secp256k1")
+}
+
+func TestMultisigs_SetFrom(t *testing.T) {
+	_, pubKeys := testCosigners(2)
+	ms := Multisigs{{Name: ptr("admin"), Threshold: ptr[uint32](1), PubKeys: pubKeys}}
+	ms.SetFrom(&Multisigs{{Name: ptr("admin"), Threshold: ptr[uint32](2)}, {Name: ptr("billing"), Threshold: ptr[uint32](1), PubKeys: pubKeys[:1]}})
+	require.Len(t, ms, 2)
+	assert.Equal(t, &Multisig{Name: ptr("admin"), Threshold: ptr[uint32](2), PubKeys: pubKeys}, ms[0])
+	assert.Equal(t, "billing", *ms[1].Name)
+}
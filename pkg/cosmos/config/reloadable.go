@@ -0,0 +1,168 @@
+package config
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cometbft/cometbft/light"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/goplugin/plugin-common/pkg/config"
+)
+
+// Reloadable is a Config which can be replaced at runtime, while it is in use.
+// Each method reads the current config, so long-lived users such as the Txm pick up new values
+// on their next call.
+type Reloadable struct {
+	mu  sync.Mutex // serializes Reload
+	cfg atomic.Pointer[TOMLConfig]
+}
+
+var _ Config = &Reloadable{}
+
+func NewReloadable(cfg *TOMLConfig) *Reloadable {
+	r := &Reloadable{}
+	r.cfg.Store(cfg)
+	return r
+}
+
+// Get returns the current config, which must not be modified.
+func (r *Reloadable) Get() *TOMLConfig {
+	return r.cfg.Load()
+}
+
+// Reload replaces the current config with f, applied over the defaults with SetFrom, if the result is valid.
+// f is the complete new config, so nodes which are not in f are removed.
+// ChainID, Enabled, Bech32Prefix and KeyAlgorithm cannot be changed without a restart, except that an unset
+// Bech32Prefix can be filled in from the detected chain params. Filled in values are kept while f leaves them unset.
+// f must not be modified after it is passed to Reload.
+func (r *Reloadable) Reload(f *TOMLConfig) (*TOMLConfig, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cur := r.cfg.Load()
+
+	next := &TOMLConfig{}
+	next.SetFrom(f)
+	next.SetDefaults()
+	if next.Chain.bech32PrefixSource == sourceDefault && cur.Chain.bech32PrefixSource == sourceDetected {
+		next.Chain.Bech32Prefix, next.Chain.bech32PrefixSource = cur.Chain.Bech32Prefix, sourceDetected
+	}
+	if next.Chain.gasTokenSource == sourceDefault && cur.Chain.gasTokenSource == sourceDetected {
+		next.Chain.GasToken, next.Chain.gasTokenSource = cur.Chain.GasToken, sourceDetected
+	}
+	if err := config.Validate(TOMLConfigs{next}); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if *next.ChainID != *cur.ChainID {
+		err = errors.Join(err, config.ErrInvalid{Name: "ChainID", Value: *next.ChainID, Msg: "cannot be changed without a restart"})
+	}
+	if next.IsEnabled() != cur.IsEnabled() {
+		err = errors.Join(err, config.ErrInvalid{Name: "Enabled", Value: next.IsEnabled(), Msg: "cannot be changed without a restart"})
+	}
+	filled := cur.Chain.bech32PrefixSource == sourceDefault && next.Chain.bech32PrefixSource == sourceDetected
+	if *next.Chain.Bech32Prefix != *cur.Chain.Bech32Prefix && !filled {
+		err = errors.Join(err, config.ErrInvalid{Name: "Bech32Prefix", Value: *next.Chain.Bech32Prefix, Msg: "cannot be changed without a restart"})
+	}
+	if *next.Chain.KeyAlgorithm != *cur.Chain.KeyAlgorithm {
+		err = errors.Join(err, config.ErrInvalid{Name: "KeyAlgorithm", Value: *next.Chain.KeyAlgorithm, Msg: "cannot be changed without a restart"})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.cfg.Store(next)
+	return next, nil
+}
+
+func (r *Reloadable) Adapter() string {
+	return r.Get().Adapter()
+}
+
+func (r *Reloadable) Bech32Prefix() string {
+	return r.Get().Bech32Prefix()
+}
+
+func (r *Reloadable) BlockRate() time.Duration {
+	return r.Get().BlockRate()
+}
+
+func (r *Reloadable) BlocksUntilTxTimeout() int64 {
+	return r.Get().BlocksUntilTxTimeout()
+}
+
+func (r *Reloadable) BroadcastFanOut() int64 {
+	return r.Get().BroadcastFanOut()
+}
+
+func (r *Reloadable) ConfirmPollPeriod() time.Duration {
+	return r.Get().ConfirmPollPeriod()
+}
+
+func (r *Reloadable) FallbackGasPrice() sdk.Dec {
+	return r.Get().FallbackGasPrice()
+}
+
+func (r *Reloadable) FeeTokens() []FeeToken {
+	return r.Get().FeeTokens()
+}
+
+func (r *Reloadable) GasToken() string {
+	return r.Get().GasToken()
+}
+
+func (r *Reloadable) GasLimitMultiplier() float64 {
+	return r.Get().GasLimitMultiplier()
+}
+
+func (r *Reloadable) KeyAlgorithm() KeyAlgorithm {
+	return r.Get().KeyAlgorithm()
+}
+
+func (r *Reloadable) MaxGasPrice() sdk.Dec {
+	return r.Get().MaxGasPrice()
+}
+
+func (r *Reloadable) MaxMsgsPerBatch() int64 {
+	return r.Get().MaxMsgsPerBatch()
+}
+
+func (r *Reloadable) MinGasPrice() sdk.Dec {
+	return r.Get().MinGasPrice()
+}
+
+func (r *Reloadable) OCR2CachePollPeriod() time.Duration {
+	return r.Get().OCR2CachePollPeriod()
+}
+
+func (r *Reloadable) OCR2CacheTTL() time.Duration {
+	return r.Get().OCR2CacheTTL()
+}
+
+func (r *Reloadable) MultisigPubKey(address string) (*kmultisig.LegacyAminoPubKey, bool) {
+	return r.Get().MultisigPubKey(address)
+}
+
+func (r *Reloadable) SenderPolicy(sender string) (SenderPolicy, bool) {
+	return r.Get().SenderPolicy(sender)
+}
+
+func (r *Reloadable) SignMode() SignMode {
+	return r.Get().SignMode()
+}
+
+func (r *Reloadable) TxMsgTimeout() time.Duration {
+	return r.Get().TxMsgTimeout()
+}
+
+func (r *Reloadable) TrustOptions() (light.TrustOptions, bool) {
+	return r.Get().TrustOptions()
+}
+
+func (r *Reloadable) UnorderedTxs() bool {
+	return r.Get().UnorderedTxs()
+}
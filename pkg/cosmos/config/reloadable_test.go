@@ -0,0 +1,72 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goplugin/plugin-common/pkg/config"
+)
+
+func TestReloadable_Reload(t *testing.T) {
+	newConfig := func(maxMsgs int64, nodes ...string) *TOMLConfig {
+		c := &TOMLConfig{ChainID: ptr("Chainlink-99"), Chain: Chain{MaxMsgsPerBatch: ptr(maxMsgs)}}
+		for _, n := range nodes {
+			c.Nodes = append(c.Nodes, &Node{Name: ptr(n), TendermintURL: config.MustParseURL("http://" + n + ":26657")})
+		}
+		return c
+	}
+	initial := newConfig(10, "a", "b")
+	initial.SetDefaults()
+	r := NewReloadable(initial)
+	assert.Equal(t, int64(10), r.MaxMsgsPerBatch())
+
+	t.Run("valid", func(t *testing.T) {
+		next, err := r.Reload(newConfig(20, "b", "c"))
+		require.NoError(t, err)
+		assert.Same(t, next, r.Get())
+		assert.Equal(t, int64(20), r.MaxMsgsPerBatch())
+		assert.Equal(t, initial.BlockRate(), r.BlockRate(), "defaults are applied")
+
+		_, err = r.Get().GetNodeConfig("a")
+		require.Error(t, err, "removed nodes are gone")
+		_, err = r.Get().GetNodeConfig("c")
+		require.NoError(t, err)
+	})
+
+	for _, tt := range []struct {
+		name   string
+		cfg    func() *TOMLConfig
+		errStr string
+	}{
+		{name: "invalid", cfg: func() *TOMLConfig { return newConfig(-1, "a") }, errStr: "MaxMsgsPerBatch: invalid value (-1): must be positive"},
+		{name: "duplicate node", cfg: func() *TOMLConfig {
+			c := newConfig(1, "a", "b")
+			c.Nodes[1].TendermintURL = c.Nodes[0].TendermintURL
+			return c
+		}, errStr: "TendermintURL: invalid value (http://a:26657): duplicate"},
+		{name: "chain id", cfg: func() *TOMLConfig {
+			c := newConfig(1, "a")
+			c.ChainID = ptr("Chainlink-100")
+			return c
+		}, errStr: "ChainID: invalid value (Chainlink-100): cannot be changed without a restart"},
+		{name: "bech32 prefix", cfg: func() *TOMLConfig {
+			c := newConfig(1, "a")
+			c.Chain.Bech32Prefix = ptr("cosmos")
+			return c
+		}, errStr: "Bech32Prefix: invalid value (cosmos): cannot be changed without a restart"},
+		{name: "key algorithm", cfg: func() *TOMLConfig {
+			c := newConfig(1, "a")
+			c.Chain.KeyAlgorithm = ptr(KeyAlgorithmEthSecp256k1)
+			return c
+		}, errStr: "KeyAlgorithm: invalid value (eth_secp256k1): cannot be changed without a restart"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			before := r.Get()
+			_, err := r.Reload(tt.cfg())
+			require.ErrorContains(t, err, tt.errStr)
+			assert.Same(t, before, r.Get(), "config is unchanged")
+		})
+	}
+}
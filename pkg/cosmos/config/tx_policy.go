@@ -0,0 +1,160 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+
+	"github.com/goplugin/plugin-common/pkg/config"
+)
+
+// TxPolicy restricts the msgs sent by a sender, and the fees it pays, so that a compromised job spec cannot
+// drain its key. Each restriction is optional. Coin limits may be in any unit of the chain's denominations, e.g.
+// "1atom" for "1000000uatom", and are converted to base units, rounded down.
+type TxPolicy struct {
+	// Sender is the address the policy applies to. Unset applies to each sender without a policy of its own.
+	Sender *string
+	// AllowedContracts are the only contracts which may be executed. Unset allows any contract.
+	AllowedContracts []string
+	// AllowedRecipients are the only addresses to which coins may be sent. Unset allows any recipient.
+	AllowedRecipients []string
+	// MaxFunds caps the coins attached to each contract execution, e.g. "1000ucosm".
+	MaxFunds *string
+	// MaxSend caps the coins sent by each MsgSend, unless it is enqueued with adapters.WithLargeSend.
+	MaxSend *string
+	// MaxFees caps the fees paid in each FeeWindow.
+	MaxFees *string
+	// FeeWindow is the period of MaxFees. Required with MaxFees.
+	FeeWindow *config.Duration
+}
+
+func (p *TxPolicy) ValidateConfig() (err error) {
+	if p.Sender != nil {
+		err = errors.Join(err, validateAddress("Sender", *p.Sender))
+	}
+	for i, addr := range p.AllowedContracts {
+		err = errors.Join(err, validateAddress(fmt.Sprintf("AllowedContracts.%d", i), addr))
+	}
+	for i, addr := range p.AllowedRecipients {
+		err = errors.Join(err, validateAddress(fmt.Sprintf("AllowedRecipients.%d", i), addr))
+	}
+	for _, c := range []struct {
+		name  string
+		coins *string
+	}{
+		{"MaxFunds", p.MaxFunds},
+		{"MaxSend", p.MaxSend},
+		{"MaxFees", p.MaxFees},
+	} {
+		if c.coins != nil {
+			if _, err1 := sdk.ParseCoinsNormalized(*c.coins); err1 != nil {
+				err = errors.Join(err, config.ErrInvalid{Name: c.name, Value: *c.coins, Msg: err1.Error()})
+			}
+		}
+	}
+	if p.MaxFees != nil && p.FeeWindow == nil {
+		err = errors.Join(err, config.ErrMissing{Name: "FeeWindow", Msg: "required with MaxFees"})
+	}
+	if p.FeeWindow != nil && p.FeeWindow.Duration() <= 0 {
+		err = errors.Join(err, config.ErrInvalid{Name: "FeeWindow", Value: p.FeeWindow, Msg: "must be positive"})
+	}
+	return
+}
+
+func validateAddress(name, addr string) error {
+	if _, _, err := bech32.DecodeAndConvert(addr); err != nil {
+		return config.ErrInvalid{Name: name, Value: addr, Msg: fmt.Sprintf("must be a bech32 address: %v", err)}
+	}
+	return nil
+}
+
+type TxPolicies []*TxPolicy
+
+func (ps *TxPolicies) SetFrom(fs *TxPolicies) {
+	for _, f := range *fs {
+		if i := slices.IndexFunc(*ps, func(p *TxPolicy) bool {
+			return (p.Sender == nil && f.Sender == nil) || (p.Sender != nil && f.Sender != nil && *p.Sender == *f.Sender)
+		}); i == -1 {
+			*ps = append(*ps, f)
+		} else {
+			setFromTxPolicy((*ps)[i], f)
+		}
+	}
+}
+
+func setFromTxPolicy(p, f *TxPolicy) {
+	if f.AllowedContracts != nil {
+		p.AllowedContracts = f.AllowedContracts
+	}
+	if f.AllowedRecipients != nil {
+		p.AllowedRecipients = f.AllowedRecipients
+	}
+	if f.MaxFunds != nil {
+		p.MaxFunds = f.MaxFunds
+	}
+	if f.MaxSend != nil {
+		p.MaxSend = f.MaxSend
+	}
+	if f.MaxFees != nil {
+		p.MaxFees = f.MaxFees
+	}
+	if f.FeeWindow != nil {
+		p.FeeWindow = f.FeeWindow
+	}
+}
+
+// SenderPolicy is the TxPolicy of a sender, with its coins parsed.
+type SenderPolicy struct {
+	// AllowedContracts is nil if any contract is allowed.
+	AllowedContracts []string
+	// AllowedRecipients is nil if any recipient is allowed.
+	AllowedRecipients []string
+	// MaxFunds is nil if unset.
+	MaxFunds sdk.Coins
+	// MaxSend is nil if unset.
+	MaxSend sdk.Coins
+	// MaxFees is nil if unset.
+	MaxFees   sdk.Coins
+	FeeWindow time.Duration
+}
+
+// AllowsContract returns whether contract may be executed.
+func (p SenderPolicy) AllowsContract(contract string) bool {
+	return p.AllowedContracts == nil || slices.Contains(p.AllowedContracts, contract)
+}
+
+// AllowsRecipient returns whether coins may be sent to recipient.
+func (p SenderPolicy) AllowsRecipient(recipient string) bool {
+	return p.AllowedRecipients == nil || slices.Contains(p.AllowedRecipients, recipient)
+}
+
+func (c *TOMLConfig) SenderPolicy(sender string) (SenderPolicy, bool) {
+	i := slices.IndexFunc(c.TxPolicies, func(p *TxPolicy) bool { return p.Sender != nil && *p.Sender == sender })
+	if i == -1 {
+		i = slices.IndexFunc(c.TxPolicies, func(p *TxPolicy) bool { return p.Sender == nil })
+	}
+	if i == -1 {
+		return SenderPolicy{}, false
+	}
+	p := c.TxPolicies[i]
+	sp := SenderPolicy{
+		AllowedContracts:  p.AllowedContracts,
+		AllowedRecipients: p.AllowedRecipients,
+	}
+	// validated by ValidateConfig
+	if p.MaxFunds != nil {
+		sp.MaxFunds, _ = sdk.ParseCoinsNormalized(*p.MaxFunds)
+	}
+	if p.MaxSend != nil {
+		sp.MaxSend, _ = sdk.ParseCoinsNormalized(*p.MaxSend)
+	}
+	if p.MaxFees != nil {
+		sp.MaxFees, _ = sdk.ParseCoinsNormalized(*p.MaxFees)
+		sp.FeeWindow = p.FeeWindow.Duration()
+	}
+	return sp, true
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goplugin/plugin-common/pkg/config"
+)
+
+func testAddress(t *testing.T, b byte) string {
+	addr, err := bech32.ConvertAndEncode("wasm", []byte{b, b, b, b, b, b, b, b, b, b, b, b, b, b, b, b, b, b, b, b})
+	require.NoError(t, err)
+	return addr
+}
+
+func TestTxPolicy_ValidateConfig(t *testing.T) {
+	valid := func() *TxPolicy {
+		return &TxPolicy{
+			Sender:           ptr(testAddress(t, 1)),
+			AllowedContracts: []string{testAddress(t, 2)},
+			MaxFunds:         ptr("100ucosm"),
+			MaxFees:          ptr("1000ucosm,10uatom"),
+			FeeWindow:        config.MustNewDuration(time.Hour),
+		}
+	}
+	for _, tt := range []struct {
+		name   string
+		modify func(*TxPolicy)
+		errStr string
+	}{
+		{name: "valid", modify: func(*TxPolicy) {}},
+		{name: "default", modify: func(p *TxPolicy) { p.Sender = nil }},
+		{name: "sender", modify: func(p *TxPolicy) { p.Sender = ptr("wasm1") }, errStr: "Sender: invalid value (wasm1): must be a bech32 address"},
+		{name: "contract", modify: func(p *TxPolicy) { p.AllowedContracts = append(p.AllowedContracts, "0x123") },
+			errStr: "AllowedContracts.1: invalid value (0x123): must be a bech32 address"},
+		{name: "recipient", modify: func(p *TxPolicy) { p.AllowedRecipients = []string{""} }, errStr: "AllowedRecipients.0: invalid value (): must be a bech32 address"},
+		{name: "funds", modify: func(p *TxPolicy) { p.MaxFunds = ptr("ucosm") }, errStr: "MaxFunds: invalid value (ucosm)"},
+		{name: "fee window", modify: func(p *TxPolicy) { p.FeeWindow = nil }, errStr: "FeeWindow: missing: required with MaxFees"},
+		{name: "zero fee window", modify: func(p *TxPolicy) { p.FeeWindow = config.MustNewDuration(0) }, errStr: "FeeWindow: invalid value (0s): must be positive"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid()
+			tt.modify(p)
+			err := p.ValidateConfig()
+			if tt.errStr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.errStr)
+		})
+	}
+}
+
+func TestTOMLConfig_SenderPolicy(t *testing.T) {
+	sender, other := testAddress(t, 1), testAddress(t, 2)
+	c := &TOMLConfig{TxPolicies: TxPolicies{
+		{MaxSend: ptr("10ucosm")},
+		{Sender: &sender, AllowedRecipients: []string{other}, MaxFees: ptr("1000ucosm"), FeeWindow: config.MustNewDuration(time.Hour)},
+	}}
+	p, ok := c.SenderPolicy(sender)
+	require.True(t, ok)
+	assert.Equal(t, SenderPolicy{
+		AllowedRecipients: []string{other},
+		MaxFees:           sdk.NewCoins(sdk.NewInt64Coin("ucosm", 1000)),
+		FeeWindow:         time.Hour,
+	}, p)
+	assert.True(t, p.AllowsContract(other))
+	assert.True(t, p.AllowsRecipient(other))
+	assert.False(t, p.AllowsRecipient(sender))
+
+	p, ok = c.SenderPolicy(other)
+	require.True(t, ok, "default policy")
+	assert.Equal(t, SenderPolicy{MaxSend: sdk.NewCoins(sdk.NewInt64Coin("ucosm", 10))}, p)
+
+	_, ok = (&TOMLConfig{}).SenderPolicy(sender)
+	assert.False(t, ok)
+}
+
+func TestTxPolicies_SetFrom(t *testing.T) {
+	sender := testAddress(t, 1)
+	ps := TxPolicies{{MaxSend: ptr("10ucosm")}, {Sender: &sender, MaxFunds: ptr("1ucosm")}}
+	ps.SetFrom(&TxPolicies{{Sender: ptr(sender), MaxSend: ptr("5ucosm")}, {MaxFunds: ptr("2ucosm")}})
+	require.Len(t, ps, 2)
+	assert.Equal(t, &TxPolicy{MaxSend: ptr("10ucosm"), MaxFunds: ptr("2ucosm")}, ps[0])
+	assert.Equal(t, &TxPolicy{Sender: &sender, MaxSend: ptr("5ucosm"), MaxFunds: ptr("1ucosm")}, ps[1])
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/cometbft/cometbft/crypto/tmhash"
+	"github.com/cometbft/cometbft/light"
+
+	"github.com/goplugin/plugin-common/pkg/config"
+)
+
+// VerifiedReads verifies the OCR2 contract state read from nodes against headers verified by a light client,
+// so that a single node cannot forge it. The light client follows a primary node and cross-checks it against
+// the other nodes as witnesses, starting from a trusted header.
+// Only contract state is verified: account sequences and balances only affect the txs sent by the Txm, which
+// the chain checks itself, so they are read unverified.
+type VerifiedReads struct {
+	Enabled *bool
+	// TrustedHeight and TrustedHash identify a header trusted to be on the chain, such as one from a block explorer.
+	// It must be more recent than the TrustingPeriod when the chain starts.
+	TrustedHeight *int64
+	// TrustedHash is the hex-encoded hash of the header at TrustedHeight.
+	TrustedHash *string
+	// TrustingPeriod is how long a verified header is trusted, which must be significantly less than the
+	// unbonding period of the chain.
+	TrustingPeriod *config.Duration
+}
+
+func (v *VerifiedReads) IsEnabled() bool {
+	return v.Enabled != nil && *v.Enabled
+}
+
+func (v *VerifiedReads) SetFrom(f *VerifiedReads) {
+	if f.Enabled != nil {
+		v.Enabled = f.Enabled
+	}
+	if f.TrustedHeight != nil {
+		v.TrustedHeight = f.TrustedHeight
+	}
+	if f.TrustedHash != nil {
+		v.TrustedHash = f.TrustedHash
+	}
+	if f.TrustingPeriod != nil {
+		v.TrustingPeriod = f.TrustingPeriod
+	}
+}
+
+func (v *VerifiedReads) ValidateConfig() (err error) {
+	if !v.IsEnabled() {
+		return
+	}
+	if v.TrustedHeight == nil {
+		err = errors.Join(err, config.ErrMissing{Name: "TrustedHeight", Msg: "required for verified reads"})
+	} else if *v.TrustedHeight <= 0 {
+		err = errors.Join(err, config.ErrInvalid{Name: "TrustedHeight", Value: *v.TrustedHeight, Msg: "must be positive"})
+	}
+	if v.TrustedHash == nil {
+		err = errors.Join(err, config.ErrMissing{Name: "TrustedHash", Msg: "required for verified reads"})
+	} else if b, err1 := hex.DecodeString(*v.TrustedHash); err1 != nil {
+		err = errors.Join(err, config.ErrInvalid{Name: "TrustedHash", Value: *v.TrustedHash, Msg: err1.Error()})
+	} else if len(b) != tmhash.Size {
+		err = errors.Join(err, config.ErrInvalid{Name: "TrustedHash", Value: *v.TrustedHash, Msg: fmt.Sprintf("must be %d bytes", tmhash.Size)})
+	}
+	if v.TrustingPeriod == nil {
+		err = errors.Join(err, config.ErrMissing{Name: "TrustingPeriod", Msg: "required for verified reads"})
+	} else if v.TrustingPeriod.Duration() <= 0 {
+		err = errors.Join(err, config.ErrInvalid{Name: "TrustingPeriod", Value: v.TrustingPeriod.String(), Msg: "must be positive"})
+	}
+	return
+}
+
+// TrustOptions returns the options of the light client. v must be valid and enabled.
+func (v *VerifiedReads) TrustOptions() light.TrustOptions {
+	hash, _ := hex.DecodeString(*v.TrustedHash) // validated by ValidateConfig
+	return light.TrustOptions{
+		Period: v.TrustingPeriod.Duration(),
+		Height: *v.TrustedHeight,
+		Hash:   hash,
+	}
+}
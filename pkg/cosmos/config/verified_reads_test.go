@@ -0,0 +1,59 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goplugin/plugin-common/pkg/config"
+)
+
+func TestVerifiedReads_ValidateConfig(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	for _, tt := range []struct {
+		name   string
+		modify func(*VerifiedReads)
+		errStr string
+	}{
+		{name: "valid", modify: func(*VerifiedReads) {}},
+		{name: "disabled", modify: func(v *VerifiedReads) { v.Enabled, v.TrustedHash = ptr(false), nil }},
+		{name: "missing height", modify: func(v *VerifiedReads) { v.TrustedHeight = nil }, errStr: "TrustedHeight: missing: required for verified reads"},
+		{name: "height", modify: func(v *VerifiedReads) { v.TrustedHeight = ptr[int64](0) }, errStr: "TrustedHeight: invalid value (0): must be positive"},
+		{name: "missing hash", modify: func(v *VerifiedReads) { v.TrustedHash = nil }, errStr: "TrustedHash: missing: required for verified reads"},
+		{name: "hash", modify: func(v *VerifiedReads) { v.TrustedHash = ptr("abcd") }, errStr: "TrustedHash: invalid value (abcd): must be 32 bytes"},
+		{name: "missing period", modify: func(v *VerifiedReads) { v.TrustingPeriod = nil }, errStr: "TrustingPeriod: missing: required for verified reads"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &VerifiedReads{Enabled: ptr(true), TrustedHeight: ptr[int64](100), TrustedHash: ptr(hash),
+				TrustingPeriod: config.MustNewDuration(336 * time.Hour)}
+			tt.modify(v)
+			err := v.ValidateConfig()
+			if tt.errStr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.errStr)
+		})
+	}
+}
+
+func TestTOMLConfig_TrustOptions(t *testing.T) {
+	c := &TOMLConfig{}
+	c.SetDefaults()
+	_, ok := c.TrustOptions()
+	assert.False(t, ok)
+
+	c.VerifiedReads = VerifiedReads{Enabled: ptr(true), TrustedHeight: ptr[int64](100), TrustedHash: ptr(strings.Repeat("ab", 32)),
+		TrustingPeriod: config.MustNewDuration(336 * time.Hour)}
+	opts, ok := c.TrustOptions()
+	require.True(t, ok)
+	assert.Equal(t, int64(100), opts.Height)
+	assert.Len(t, opts.Hash, 32)
+	assert.Equal(t, 336*time.Hour, opts.Period)
+
+	c.Nodes = Nodes{{Name: ptr("primary")}}
+	assert.ErrorContains(t, c.ValidateConfig(), "Nodes: invalid value (1): verified reads require at least two nodes, to cross-check headers")
+}
@@ -49,4 +49,18 @@ type Msg struct {
 	TxHash     *string
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
+
+	// Enqueue options, see adapters.EnqueueOptions.
+	Memo           *string
+	Deadline       *time.Time
+	Priority       int32
+	IdempotencyKey *string
+	CoalesceKey    *string
+	AllowLargeSend bool
+	// SenderPool is joined by adapters.JoinSenderPool.
+	SenderPool *string
+	// SupersededBy is the ID of the msg which cancelled this one, if any.
+	SupersededBy *int64
+	// EstimatedGas is the gas used by the msg in the last simulation of its batch, if it succeeded.
+	EstimatedGas *int64
 }
@@ -0,0 +1,10 @@
+package db
+
+import "embed"
+
+// Migrations are the goose migrations of the cosmos_msgs table, which is created by the core node.
+// The node must apply them, in order, after its own cosmos migrations, since the ORM of the Txm
+// reads and writes the columns they add.
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
@@ -0,0 +1,32 @@
+-- +goose Up
+ALTER TABLE cosmos_msgs
+    ADD COLUMN memo text,
+    ADD COLUMN deadline timestamptz,
+    ADD COLUMN priority integer NOT NULL DEFAULT 0,
+    ADD COLUMN idempotency_key text,
+    ADD COLUMN coalesce_key text,
+    ADD COLUMN superseded_by bigint REFERENCES cosmos_msgs (id) ON DELETE SET NULL,
+    ADD COLUMN allow_large_send boolean NOT NULL DEFAULT false,
+    ADD COLUMN sender_pool text;
+
+-- At most one msg of a chain which has not errored may have each idempotency key, so that concurrent
+-- enqueues of the same msg cannot both insert it. ORM.InsertMsg relies on this index.
+CREATE UNIQUE INDEX idx_cosmos_msgs_idempotency_key ON cosmos_msgs (cosmos_chain_id, idempotency_key)
+    WHERE idempotency_key IS NOT NULL AND state <> 'errored';
+
+-- ORM.GetMsgsState reads msgs with the highest priority first.
+CREATE INDEX idx_cosmos_msgs_chain_state_priority ON cosmos_msgs (cosmos_chain_id, state, priority DESC, id);
+
+-- +goose Down
+DROP INDEX idx_cosmos_msgs_chain_state_priority;
+DROP INDEX idx_cosmos_msgs_idempotency_key;
+
+ALTER TABLE cosmos_msgs
+    DROP COLUMN memo,
+    DROP COLUMN deadline,
+    DROP COLUMN priority,
+    DROP COLUMN idempotency_key,
+    DROP COLUMN coalesce_key,
+    DROP COLUMN superseded_by,
+    DROP COLUMN allow_large_send,
+    DROP COLUMN sender_pool;
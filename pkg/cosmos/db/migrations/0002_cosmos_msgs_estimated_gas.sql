@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE cosmos_msgs ADD COLUMN estimated_gas bigint;
+
+-- +goose Down
+ALTER TABLE cosmos_msgs DROP COLUMN estimated_gas;
@@ -7,6 +7,7 @@ import (
 // ConvertDecCoinToDenom is a helper for converting a DecCoin to a given denomination, rounded
 // down with the remainder discarded. Requires InitCosmosSdk to be called first to register
 // both the source and destinations token denominations, otherwise will return an error.
+// Prefer Registry.ConvertToDenom, which supports any denomination of the chain.
 func ConvertDecCoinToDenom(coin sdk.DecCoin, denom string) (sdk.Coin, error) {
 	decCoin, err := sdk.ConvertDecCoin(coin, denom)
 	if err != nil {
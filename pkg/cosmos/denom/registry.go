@@ -0,0 +1,223 @@
+package denom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math/big"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/shopspring/decimal"
+)
+
+// maxIntBitLen is the maximum bit length of an sdk.Int.
+const maxIntBitLen = 256
+
+// Unit is a unit of a denomination, worth 10^Exponent of its base unit.
+type Unit struct {
+	Base     string
+	Exponent uint32
+}
+
+// Registry holds the units of the denominations of a single chain, so that amounts can be converted
+// between units and formatted for display. Unlike sdk.RegisterDenom, it is not global, so chains with
+// different denominations can be used at the same time.
+type Registry struct {
+	mu      sync.RWMutex
+	units   map[string]Unit
+	display map[string]string // by base
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		units:   map[string]Unit{},
+		display: map[string]string{},
+	}
+}
+
+// Register adds the units of a denomination, as described by its bank metadata, replacing any
+// previously registered units of the same base. The base unit must have exponent 0.
+// If the display unit is unset, amounts are displayed in the base unit.
+func (r *Registry) Register(md banktypes.Metadata) error {
+	if err := sdk.ValidateDenom(md.Base); err != nil {
+		return fmt.Errorf("invalid base denom: %w", err)
+	}
+	units := map[string]Unit{md.Base: {Base: md.Base}}
+	for _, u := range md.DenomUnits {
+		if u == nil {
+			continue
+		}
+		if u.Denom == md.Base {
+			if u.Exponent != 0 {
+				return fmt.Errorf("base denom %s must have exponent 0, got %d", md.Base, u.Exponent)
+			}
+		} else if u.Exponent == 0 {
+			return fmt.Errorf("denom %s must have a positive exponent, since it is not the base denom %s", u.Denom, md.Base)
+		}
+		for _, denom := range append([]string{u.Denom}, u.Aliases...) {
+			if err := sdk.ValidateDenom(denom); err != nil {
+				return fmt.Errorf("invalid denom unit: %w", err)
+			}
+			if prev, ok := units[denom]; ok && prev.Exponent != u.Exponent {
+				return fmt.Errorf("denom %s has exponents %d and %d", denom, prev.Exponent, u.Exponent)
+			}
+			units[denom] = Unit{Base: md.Base, Exponent: u.Exponent}
+		}
+	}
+	display := md.Display
+	if display == "" {
+		display = md.Base
+	} else if _, ok := units[display]; !ok {
+		return fmt.Errorf("display denom %s is not a unit of %s", display, md.Base)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for denom := range units {
+		if prev, ok := r.units[denom]; ok && prev.Base != md.Base {
+			return fmt.Errorf("denom %s is already a unit of %s", denom, prev.Base)
+		}
+	}
+	for denom, u := range r.units {
+		if u.Base == md.Base {
+			delete(r.units, denom)
+		}
+	}
+	for denom, u := range units {
+		r.units[denom] = u
+	}
+	r.display[md.Base] = display
+	return nil
+}
+
+// Replace replaces the units of r with those of src, so that denominations which are not registered in src
+// are removed.
+func (r *Registry) Replace(src *Registry) {
+	src.mu.RLock()
+	units, display := maps.Clone(src.units), maps.Clone(src.display)
+	src.mu.RUnlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.units, r.display = units, display
+}
+
+// MetadataReader reads the bank metadata of a denomination, e.g. client.Reader.
+type MetadataReader interface {
+	DenomMetadata(ctx context.Context, denom string) (*banktypes.Metadata, error)
+}
+
+// LoadMetadata registers the bank metadata of each of denoms, as read from reader, and returns the registered
+// metadata, so that it can be registered again in another Registry without reading it again.
+// It returns an error for each denom without valid metadata, but still registers the others.
+func (r *Registry) LoadMetadata(ctx context.Context, reader MetadataReader, denoms ...string) (loaded []banktypes.Metadata, err error) {
+	for _, denom := range denoms {
+		md, err1 := reader.DenomMetadata(ctx, denom)
+		if err1 != nil {
+			err = errors.Join(err, fmt.Errorf("failed to read metadata of %s: %w", denom, err1))
+			continue
+		}
+		if err1 = r.Register(*md); err1 != nil {
+			err = errors.Join(err, fmt.Errorf("invalid metadata of %s: %w", denom, err1))
+			continue
+		}
+		loaded = append(loaded, *md)
+	}
+	return
+}
+
+// Unit returns the unit called denom, which may be an alias.
+func (r *Registry) Unit(denom string) (Unit, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	u, ok := r.units[denom]
+	return u, ok
+}
+
+// Display returns the display unit of the denomination of denom.
+func (r *Registry) Display(denom string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	u, ok := r.units[denom]
+	if !ok {
+		return "", false
+	}
+	return r.display[u.Base], true
+}
+
+// unitsOf returns the units of src and dst, which must be of the same denomination.
+func (r *Registry) unitsOf(src, dst string) (from, to Unit, err error) {
+	var ok bool
+	if from, ok = r.Unit(src); !ok {
+		return from, to, fmt.Errorf("source denom not registered: %s", src)
+	}
+	if to, ok = r.Unit(dst); !ok {
+		return from, to, fmt.Errorf("destination denom not registered: %s", dst)
+	}
+	if from.Base != to.Base {
+		return from, to, fmt.Errorf("cannot convert %s to %s, which are units of %s and %s", src, dst, from.Base, to.Base)
+	}
+	return from, to, nil
+}
+
+// shift returns amount * 10^exp, scaled by sdk.Precision as in sdk.Dec.BigInt, and whether the result is exact.
+func shift(amount sdk.Dec, exp int) (*big.Int, bool) {
+	i := amount.BigInt()
+	if exp >= 0 {
+		return i.Mul(i, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)), true
+	}
+	_, m := i.QuoRem(i, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exp)), nil), new(big.Int))
+	return i, m.Sign() == 0
+}
+
+// Convert returns coin in the unit denom, which must be of the same denomination.
+// It returns an error instead of losing precision, or if the result is out of range.
+func (r *Registry) Convert(coin sdk.DecCoin, denom string) (sdk.DecCoin, error) {
+	from, to, err := r.unitsOf(coin.Denom, denom)
+	if err != nil {
+		return sdk.DecCoin{}, err
+	}
+	i, exact := shift(coin.Amount, int(from.Exponent)-int(to.Exponent))
+	if !exact {
+		return sdk.DecCoin{}, fmt.Errorf("converting %s to %s would lose precision", coin, denom)
+	}
+	if new(big.Int).Quo(i, sdk.OneDec().BigInt()).BitLen() > maxIntBitLen {
+		return sdk.DecCoin{}, fmt.Errorf("converting %s to %s is out of range", coin, denom)
+	}
+	return sdk.NewDecCoinFromDec(denom, sdk.NewDecFromBigIntWithPrec(i, sdk.Precision)), nil
+}
+
+// ConvertToDenom is like Convert, but returns a whole number of the unit denom, rounded down with the
+// remainder discarded.
+func (r *Registry) ConvertToDenom(coin sdk.DecCoin, denom string) (sdk.Coin, error) {
+	from, to, err := r.unitsOf(coin.Denom, denom)
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+	i, _ := shift(coin.Amount, int(from.Exponent)-int(to.Exponent)-sdk.Precision)
+	if i.BitLen() > maxIntBitLen {
+		return sdk.Coin{}, fmt.Errorf("converting %s to %s is out of range", coin, denom)
+	}
+	return sdk.NewCoin(denom, sdk.NewIntFromBigInt(i)), nil
+}
+
+// Format returns coin in its display unit, without trailing zeros, e.g. 1.5atom for 1500000uatom.
+// Coins of unregistered denominations are formatted in their own unit.
+func (r *Registry) Format(coin sdk.DecCoin) string {
+	display, ok := r.Display(coin.Denom)
+	if !ok {
+		return decimal.NewFromBigInt(coin.Amount.BigInt(), -sdk.Precision).String() + coin.Denom
+	}
+	from, _ := r.Unit(coin.Denom)
+	to, _ := r.Unit(display)
+	exp := int32(from.Exponent) - int32(to.Exponent) - sdk.Precision
+	return decimal.NewFromBigInt(coin.Amount.BigInt(), exp).String() + display
+}
+
+// FormatCoin is like Format, for an sdk.Coin.
+func (r *Registry) FormatCoin(coin sdk.Coin) string {
+	return r.Format(sdk.NewDecCoinFromCoin(coin))
+}
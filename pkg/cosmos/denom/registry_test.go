@@ -0,0 +1,174 @@
+package denom
+
+import (
+	"errors"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goplugin/plugin-common/pkg/utils/tests"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client/mocks"
+)
+
+var (
+	injMetadata = banktypes.Metadata{
+		Base:    "inj",
+		Display: "INJ",
+		DenomUnits: []*banktypes.DenomUnit{
+			{Denom: "inj", Exponent: 0},
+			{Denom: "INJ", Exponent: 18},
+		},
+	}
+	atomMetadata = banktypes.Metadata{
+		Base:    "uatom",
+		Display: "atom",
+		DenomUnits: []*banktypes.DenomUnit{
+			{Denom: "uatom", Exponent: 0, Aliases: []string{"microatom"}},
+			{Denom: "matom", Exponent: 3},
+			{Denom: "atom", Exponent: 6},
+		},
+	}
+)
+
+func newTestRegistry(t *testing.T) *Registry {
+	r := NewRegistry()
+	require.NoError(t, r.Register(injMetadata))
+	require.NoError(t, r.Register(atomMetadata))
+	require.NoError(t, r.Register(banktypes.Metadata{Base: "factory/osmo1abc/token"}))
+	return r
+}
+
+func TestRegistry_Register(t *testing.T) {
+	r := newTestRegistry(t)
+	u, ok := r.Unit("microatom")
+	require.True(t, ok)
+	assert.Equal(t, Unit{Base: "uatom", Exponent: 0}, u)
+	display, ok := r.Display("uatom")
+	require.True(t, ok)
+	assert.Equal(t, "atom", display)
+	display, ok = r.Display("factory/osmo1abc/token")
+	require.True(t, ok)
+	assert.Equal(t, "factory/osmo1abc/token", display, "defaults to the base")
+
+	// replaces the units of the same base
+	require.NoError(t, r.Register(banktypes.Metadata{Base: "uatom", Display: "atom",
+		DenomUnits: []*banktypes.DenomUnit{{Denom: "atom", Exponent: 6}}}))
+	_, ok = r.Unit("matom")
+	assert.False(t, ok)
+
+	for _, tt := range []struct {
+		name   string
+		md     banktypes.Metadata
+		errStr string
+	}{
+		{name: "base", md: banktypes.Metadata{Base: "1inj"}, errStr: "invalid base denom: invalid denom: 1inj"},
+		{name: "base exponent", md: banktypes.Metadata{Base: "uosmo", DenomUnits: []*banktypes.DenomUnit{{Denom: "uosmo", Exponent: 6}}},
+			errStr: "base denom uosmo must have exponent 0, got 6"},
+		{name: "unit exponent", md: banktypes.Metadata{Base: "uosmo", DenomUnits: []*banktypes.DenomUnit{{Denom: "osmo"}}},
+			errStr: "denom osmo must have a positive exponent, since it is not the base denom uosmo"},
+		{name: "display", md: banktypes.Metadata{Base: "uosmo", Display: "osmo"}, errStr: "display denom osmo is not a unit of uosmo"},
+		{name: "other base", md: banktypes.Metadata{Base: "uosmo", DenomUnits: []*banktypes.DenomUnit{{Denom: "atom", Exponent: 6}}},
+			errStr: "denom atom is already a unit of uatom"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.EqualError(t, r.Register(tt.md), tt.errStr)
+		})
+	}
+}
+
+func TestRegistry_Convert(t *testing.T) {
+	r := newTestRegistry(t)
+	for _, tt := range []struct {
+		coin   sdk.DecCoin
+		denom  string
+		exp    string
+		errStr string
+	}{
+		{coin: sdk.NewInt64DecCoin("INJ", 2), denom: "inj", exp: "2000000000000000000.000000000000000000inj"},
+		{coin: sdk.NewInt64DecCoin("inj", 1), denom: "INJ", exp: "0.000000000000000001INJ"},
+		{coin: sdk.NewInt64DecCoin("matom", 1), denom: "microatom", exp: "1000.000000000000000000microatom"},
+		{coin: sdk.NewDecCoinFromDec("uatom", sdk.MustNewDecFromStr("0.5")), denom: "atom", exp: "0.000000500000000000atom"},
+		{coin: sdk.NewDecCoinFromDec("inj", sdk.MustNewDecFromStr("0.5")), denom: "INJ", errStr: "converting 0.500000000000000000inj to INJ would lose precision"},
+		{coin: sdk.NewInt64DecCoin("uatom", 1), denom: "inj", errStr: "cannot convert uatom to inj, which are units of uatom and inj"},
+		{coin: sdk.NewInt64DecCoin("zatom", 1), denom: "atom", errStr: "source denom not registered: zatom"},
+		{coin: sdk.NewInt64DecCoin("atom", 1), denom: "xatom", errStr: "destination denom not registered: xatom"},
+	} {
+		t.Run(tt.coin.String(), func(t *testing.T) {
+			got, err := r.Convert(tt.coin, tt.denom)
+			if tt.errStr != "" {
+				require.EqualError(t, err, tt.errStr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, got.String())
+		})
+	}
+}
+
+func TestRegistry_ConvertToDenom(t *testing.T) {
+	r := newTestRegistry(t)
+	for _, tt := range []struct {
+		coin  sdk.DecCoin
+		denom string
+		exp   string
+	}{
+		{coin: sdk.NewInt64DecCoin("INJ", 1), denom: "inj", exp: "1000000000000000000inj"},
+		{coin: sdk.NewInt64DecCoin("inj", 999), denom: "INJ", exp: "0INJ"},
+		{coin: sdk.NewInt64DecCoin("uatom", 123456789), denom: "atom", exp: "123atom"},
+		{coin: sdk.NewDecCoinFromDec("uatom", sdk.MustNewDecFromStr("1.9")), denom: "uatom", exp: "1uatom"},
+	} {
+		t.Run(tt.coin.String(), func(t *testing.T) {
+			got, err := r.ConvertToDenom(tt.coin, tt.denom)
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, got.String())
+		})
+	}
+}
+
+func TestRegistry_Format(t *testing.T) {
+	r := newTestRegistry(t)
+	assert.Equal(t, "1.5atom", r.FormatCoin(sdk.NewInt64Coin("uatom", 1500000)))
+	assert.Equal(t, "0.000001atom", r.FormatCoin(sdk.NewInt64Coin("uatom", 1)))
+	assert.Equal(t, "2atom", r.FormatCoin(sdk.NewInt64Coin("matom", 2000)))
+	assert.Equal(t, "0.25INJ", r.FormatCoin(sdk.NewInt64Coin("inj", 250_000_000_000_000_000)))
+	assert.Equal(t, "0.0000015atom", r.Format(sdk.NewDecCoinFromDec("uatom", sdk.MustNewDecFromStr("1.5"))))
+	assert.Equal(t, "7factory/osmo1abc/token", r.FormatCoin(sdk.NewInt64Coin("factory/osmo1abc/token", 7)))
+	assert.Equal(t, "7ucosm", r.FormatCoin(sdk.NewInt64Coin("ucosm", 7)), "unregistered")
+}
+
+func TestRegistry_LoadMetadata(t *testing.T) {
+	ctx := tests.Context(t)
+	reader := mocks.NewReaderWriter(t)
+	reader.On("DenomMetadata", mock.Anything, "inj").Return(&injMetadata, nil)
+	reader.On("DenomMetadata", mock.Anything, "ucosm").Return(nil, errors.New("not found"))
+
+	r := NewRegistry()
+	loaded, err := r.LoadMetadata(ctx, reader, "inj", "ucosm")
+	require.EqualError(t, err, "failed to read metadata of ucosm: not found")
+	assert.Equal(t, []banktypes.Metadata{injMetadata}, loaded)
+	_, ok := r.Unit("INJ")
+	assert.True(t, ok)
+}
+
+func TestRegistry_Replace(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.Register(injMetadata))
+
+	src := NewRegistry()
+	require.NoError(t, src.Register(banktypes.Metadata{Base: "ucosm"}))
+	r.Replace(src)
+
+	_, ok := r.Unit("INJ")
+	assert.False(t, ok, "removed")
+	_, ok = r.Unit("ucosm")
+	assert.True(t, ok)
+
+	require.NoError(t, src.Register(injMetadata))
+	_, ok = r.Unit("INJ")
+	assert.False(t, ok, "copied")
+}
@@ -0,0 +1,112 @@
+package ethermint
+
+import (
+	"fmt"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var (
+	_ authtypes.AccountI = &EthAccount{}
+	_ authtypes.AccountI = &InjectiveEthAccount{}
+)
+
+// EthAccount is the account type of Ethermint chains, such as Evmos. Only its BaseAccount is used,
+// so that Client.Account can read the account number and sequence.
+type EthAccount struct {
+	ethAccount
+}
+
+func (a *EthAccount) XXX_MessageName() string { return "ethermint.types.v1.EthAccount" }
+func (a *EthAccount) Reset()                  { *a = EthAccount{} }
+
+// InjectiveEthAccount is the account type of Injective. It only differs from EthAccount in its type URL.
+type InjectiveEthAccount struct {
+	ethAccount
+}
+
+func (a *InjectiveEthAccount) XXX_MessageName() string { return "injective.types.v1beta1.EthAccount" }
+func (a *InjectiveEthAccount) Reset()                  { *a = InjectiveEthAccount{} }
+
+// ethAccount implements the fields of the EthAccount message, which are the same on each chain.
+type ethAccount struct {
+	BaseAccount *authtypes.BaseAccount
+	CodeHash    []byte
+}
+
+func (a *ethAccount) ProtoMessage() {}
+func (a *ethAccount) String() string {
+	return fmt.Sprintf("EthAccount{%s, CodeHash: %X}", a.base(), a.CodeHash)
+}
+
+func (a *ethAccount) base() *authtypes.BaseAccount {
+	if a.BaseAccount == nil {
+		a.BaseAccount = &authtypes.BaseAccount{}
+	}
+	return a.BaseAccount
+}
+
+func (a *ethAccount) GetAddress() sdk.AccAddress           { return a.base().GetAddress() }
+func (a *ethAccount) SetAddress(addr sdk.AccAddress) error { return a.base().SetAddress(addr) }
+func (a *ethAccount) GetPubKey() cryptotypes.PubKey        { return a.base().GetPubKey() }
+func (a *ethAccount) SetPubKey(pk cryptotypes.PubKey) error {
+	return a.base().SetPubKey(pk)
+}
+func (a *ethAccount) GetAccountNumber() uint64        { return a.base().GetAccountNumber() }
+func (a *ethAccount) SetAccountNumber(n uint64) error { return a.base().SetAccountNumber(n) }
+func (a *ethAccount) GetSequence() uint64             { return a.base().GetSequence() }
+func (a *ethAccount) SetSequence(seq uint64) error    { return a.base().SetSequence(seq) }
+
+func (a *ethAccount) UnpackInterfaces(unpacker codectypes.AnyUnpacker) error {
+	return a.base().UnpackInterfaces(unpacker)
+}
+
+func (a *ethAccount) Marshal() ([]byte, error) {
+	base, err := a.base().Marshal()
+	if err != nil {
+		return nil, err
+	}
+	b := protowire.AppendTag(nil, 1, protowire.BytesType)
+	b = protowire.AppendBytes(b, base)
+	if len(a.CodeHash) > 0 {
+		b = protowire.AppendTag(b, 2, protowire.BytesType)
+		b = protowire.AppendBytes(b, a.CodeHash)
+	}
+	return b, nil
+}
+
+func (a *ethAccount) Unmarshal(b []byte) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if (num == 1 || num == 2) && typ == protowire.BytesType {
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+			if num == 1 {
+				a.BaseAccount = &authtypes.BaseAccount{}
+				if err := a.BaseAccount.Unmarshal(v); err != nil {
+					return err
+				}
+			} else {
+				a.CodeHash = append([]byte{}, v...)
+			}
+			continue
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package ethermint
+
+import (
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+// RegisterInterfaces registers the keys and accounts of Ethermint chains, so that their accounts and txs can be decoded.
+func RegisterInterfaces(registry codectypes.InterfaceRegistry) {
+	registry.RegisterImplementations((*cryptotypes.PubKey)(nil),
+		&PubKey{},
+		&InjectivePubKey{},
+	)
+	registry.RegisterImplementations((*authtypes.AccountI)(nil),
+		&EthAccount{},
+		&InjectiveEthAccount{},
+	)
+}
@@ -0,0 +1,201 @@
+// Package ethermint provides the eth_secp256k1 keys and accounts of Ethermint chains, such as Evmos and Injective,
+// without depending on their modules.
+package ethermint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/cometbft/cometbft/crypto"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/sha3"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// KeyType is the type of eth_secp256k1 keys.
+	KeyType = "eth_secp256k1"
+	// PubKeySize is the size of a compressed eth_secp256k1 public key.
+	PubKeySize = secp256k1.PubKeyBytesLenCompressed
+	// SignatureSize is the size of a [R || S || V] signature.
+	SignatureSize = 65
+)
+
+var (
+	_ cryptotypes.PubKey = &PubKey{}
+	_ cryptotypes.PubKey = &InjectivePubKey{}
+)
+
+// PubKey is an eth_secp256k1 public key, as registered by Ethermint chains such as Evmos.
+// Addresses are the last 20 bytes of the keccak256 hash of the uncompressed key, as on Ethereum.
+type PubKey struct {
+	// Key is the compressed public key.
+	Key []byte
+}
+
+func (pk *PubKey) XXX_MessageName() string { return "ethermint.crypto.v1.ethsecp256k1.PubKey" }
+func (pk *PubKey) Reset()                  { *pk = PubKey{} }
+func (pk *PubKey) String() string          { return fmt.Sprintf("EthPubKeySecp256k1{%X}", pk.Key) }
+func (pk *PubKey) ProtoMessage()           {}
+func (pk *PubKey) Type() string            { return KeyType }
+func (pk *PubKey) Bytes() []byte           { return pk.Key }
+func (pk *PubKey) Address() crypto.Address { return address(pk.Key) }
+func (pk *PubKey) Marshal() ([]byte, error) {
+	return marshalKey(pk.Key), nil
+}
+func (pk *PubKey) Unmarshal(b []byte) (err error) {
+	pk.Key, err = unmarshalKey(b)
+	return
+}
+func (pk *PubKey) VerifySignature(msg, sig []byte) bool {
+	return verifySignature(pk.Key, msg, sig)
+}
+func (pk *PubKey) Equals(other cryptotypes.PubKey) bool {
+	return pk.Type() == other.Type() && bytes.Equal(pk.Bytes(), other.Bytes())
+}
+
+// InjectivePubKey is an eth_secp256k1 public key, as registered by Injective.
+// It only differs from PubKey in its type URL.
+type InjectivePubKey struct {
+	// Key is the compressed public key.
+	Key []byte
+}
+
+func (pk *InjectivePubKey) XXX_MessageName() string {
+	return "injective.crypto.v1beta1.ethsecp256k1.PubKey"
+}
+func (pk *InjectivePubKey) Reset()                  { *pk = InjectivePubKey{} }
+func (pk *InjectivePubKey) String() string          { return fmt.Sprintf("EthPubKeySecp256k1{%X}", pk.Key) }
+func (pk *InjectivePubKey) ProtoMessage()           {}
+func (pk *InjectivePubKey) Type() string            { return KeyType }
+func (pk *InjectivePubKey) Bytes() []byte           { return pk.Key }
+func (pk *InjectivePubKey) Address() crypto.Address { return address(pk.Key) }
+func (pk *InjectivePubKey) Marshal() ([]byte, error) {
+	return marshalKey(pk.Key), nil
+}
+func (pk *InjectivePubKey) Unmarshal(b []byte) (err error) {
+	pk.Key, err = unmarshalKey(b)
+	return
+}
+func (pk *InjectivePubKey) VerifySignature(msg, sig []byte) bool {
+	return verifySignature(pk.Key, msg, sig)
+}
+func (pk *InjectivePubKey) Equals(other cryptotypes.PubKey) bool {
+	return pk.Type() == other.Type() && bytes.Equal(pk.Bytes(), other.Bytes())
+}
+
+// CompressPubKey returns key in its compressed form. Both compressed and uncompressed keys are accepted.
+func CompressPubKey(key []byte) ([]byte, error) {
+	pk, err := secp256k1.ParsePubKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pk.SerializeCompressed(), nil
+}
+
+func address(key []byte) crypto.Address {
+	pk, err := secp256k1.ParsePubKey(key)
+	if err != nil {
+		// like the cosmos-sdk keys, an invalid key has no address
+		return nil
+	}
+	return crypto.Address(Keccak256(pk.SerializeUncompressed()[1:])[12:])
+}
+
+// Keccak256 returns the legacy keccak256 hash of b, which eth_secp256k1 keys sign instead of the sha256 hash.
+func Keccak256(b []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(b) // does not error
+	return h.Sum(nil)
+}
+
+func verifySignature(key, msg, sig []byte) bool {
+	if len(sig) == SignatureSize {
+		// the recovery id is not needed to verify
+		sig = sig[:SignatureSize-1]
+	}
+	if len(sig) != SignatureSize-1 {
+		return false
+	}
+	pk, err := secp256k1.ParsePubKey(key)
+	if err != nil {
+		return false
+	}
+	var r, s secp256k1.ModNScalar
+	if r.SetByteSlice(sig[:32]) || s.SetByteSlice(sig[32:]) || r.IsZero() || s.IsZero() {
+		return false
+	}
+	// reject malleable signatures, as Ethereum does
+	if s.IsOverHalfOrder() {
+		return false
+	}
+	return ecdsa.NewSignature(&r, &s).Verify(Keccak256(msg), pk)
+}
+
+// NormalizeSignature converts a signature of digest by key into the [R || S || V] form expected by Ethermint chains,
+// with a low S and a recovery id V of 0 or 1. The signature may be [R || S], or [R || S || V] with V offset by 27.
+// An error is returned if the signature was not made by key.
+func NormalizeSignature(sig, digest, key []byte) ([]byte, error) {
+	if len(sig) != SignatureSize && len(sig) != SignatureSize-1 {
+		return nil, fmt.Errorf("signature must be %d or %d bytes, got %d", SignatureSize-1, SignatureSize, len(sig))
+	}
+	pk, err := secp256k1.ParsePubKey(key)
+	if err != nil {
+		return nil, err
+	}
+	var r, s secp256k1.ModNScalar
+	if r.SetByteSlice(sig[:32]) || s.SetByteSlice(sig[32:64]) || r.IsZero() || s.IsZero() {
+		return nil, errors.New("signature is out of range")
+	}
+	if s.IsOverHalfOrder() {
+		s.Negate()
+	}
+	// recover the id instead of trusting V, which would be flipped by the negation above
+	compact := make([]byte, SignatureSize)
+	r.PutBytesUnchecked(compact[1:33])
+	s.PutBytesUnchecked(compact[33:65])
+	for id := byte(0); id < 4; id++ {
+		compact[0] = 27 + 4 + id
+		recovered, _, err := ecdsa.RecoverCompact(compact, digest)
+		if err == nil && recovered.IsEqual(pk) {
+			return append(compact[1:], id), nil
+		}
+	}
+	return nil, errors.New("signature does not match key")
+}
+
+func marshalKey(key []byte) []byte {
+	if len(key) == 0 {
+		return []byte{}
+	}
+	b := protowire.AppendTag(nil, 1, protowire.BytesType)
+	return protowire.AppendBytes(b, key)
+}
+
+func unmarshalKey(b []byte) (key []byte, err error) {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+		if num == 1 && typ == protowire.BytesType {
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			key = append([]byte{}, v...)
+			b = b[n:]
+			continue
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	return key, nil
+}
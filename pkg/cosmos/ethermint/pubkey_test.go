@@ -0,0 +1,120 @@
+package ethermint
+
+import (
+	"encoding/hex"
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testKey returns the private key 1, whose Ethereum address is well known.
+func testKey() *secp256k1.PrivateKey {
+	var b [32]byte
+	b[31] = 1
+	return secp256k1.PrivKeyFromBytes(b[:])
+}
+
+func TestPubKey_Address(t *testing.T) {
+	key := testKey().PubKey()
+	for _, pk := range []cryptotypes.PubKey{
+		&PubKey{Key: key.SerializeCompressed()},
+		&InjectivePubKey{Key: key.SerializeCompressed()},
+	} {
+		assert.Equal(t, "7e5f4552091a69125d5dfcb7b8c2659029395bdf", hex.EncodeToString(pk.Address()))
+		assert.Equal(t, KeyType, pk.Type())
+	}
+	assert.Nil(t, (&PubKey{}).Address(), "empty keys have no address")
+
+	compressed, err := CompressPubKey(key.SerializeUncompressed())
+	require.NoError(t, err)
+	assert.Equal(t, key.SerializeCompressed(), compressed)
+}
+
+func TestNormalizeSignature(t *testing.T) {
+	priv := testKey()
+	key := priv.PubKey().SerializeCompressed()
+	msg := []byte("sign bytes")
+	digest := Keccak256(msg)
+
+	// <recovery code><R><S>
+	compact := ecdsa.SignCompact(priv, digest, true)
+	rs := compact[1:]
+	// the same signature with a high S, as some signers produce
+	var s secp256k1.ModNScalar
+	s.SetByteSlice(rs[32:])
+	s.Negate()
+	highS := append(append([]byte{}, rs[:32]...), make([]byte, 32)...)
+	s.PutBytesUnchecked(highS[32:])
+
+	for name, sig := range map[string][]byte{
+		"rs":        rs,
+		"rsv":       append(append([]byte{}, rs...), compact[0]-27-4),
+		"rsv 27":    append(append([]byte{}, rs...), compact[0]-4),
+		"high s":    highS,
+		"wrong rsv": append(append([]byte{}, rs...), 1-(compact[0]-27-4)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			normalized, err := NormalizeSignature(sig, digest, key)
+			require.NoError(t, err)
+			require.Len(t, normalized, SignatureSize)
+			assert.Equal(t, rs, normalized[:64])
+			assert.Equal(t, compact[0]-27-4, normalized[64])
+			assert.True(t, (&PubKey{Key: key}).VerifySignature(msg, normalized))
+		})
+	}
+
+	_, err := NormalizeSignature(rs[:63], digest, key)
+	assert.ErrorContains(t, err, "signature must be 64 or 65 bytes, got 63")
+	_, err = NormalizeSignature(rs, Keccak256([]byte("other")), key)
+	assert.ErrorContains(t, err, "signature does not match key")
+
+	assert.False(t, (&PubKey{Key: key}).VerifySignature(msg, highS), "malleable signatures are rejected")
+	assert.False(t, (&PubKey{Key: key}).VerifySignature([]byte("other"), rs))
+}
+
+func TestRegisterInterfaces(t *testing.T) {
+	registry := codectypes.NewInterfaceRegistry()
+	RegisterInterfaces(registry)
+	key := testKey().PubKey().SerializeCompressed()
+
+	t.Run("pubkey", func(t *testing.T) {
+		for typeURL, pk := range map[string]cryptotypes.PubKey{
+			"/ethermint.crypto.v1.ethsecp256k1.PubKey":      &PubKey{Key: key},
+			"/injective.crypto.v1beta1.ethsecp256k1.PubKey": &InjectivePubKey{Key: key},
+		} {
+			any, err := codectypes.NewAnyWithValue(pk)
+			require.NoError(t, err)
+			assert.Equal(t, typeURL, any.TypeUrl)
+
+			var got cryptotypes.PubKey
+			require.NoError(t, registry.UnpackAny(any, &got))
+			assert.True(t, pk.Equals(got))
+			assert.IsType(t, pk, got)
+		}
+	})
+
+	t.Run("account", func(t *testing.T) {
+		base := authtypes.NewBaseAccount(sdk.AccAddress(key[:20]), nil, 7, 42)
+		for typeURL, acc := range map[string]authtypes.AccountI{
+			"/ethermint.types.v1.EthAccount":      &EthAccount{ethAccount{BaseAccount: base, CodeHash: []byte{1, 2}}},
+			"/injective.types.v1beta1.EthAccount": &InjectiveEthAccount{ethAccount{BaseAccount: base, CodeHash: []byte{1, 2}}},
+		} {
+			any, err := codectypes.NewAnyWithValue(acc)
+			require.NoError(t, err)
+			assert.Equal(t, typeURL, any.TypeUrl)
+
+			var got authtypes.AccountI
+			require.NoError(t, registry.UnpackAny(any, &got))
+			assert.Equal(t, uint64(7), got.GetAccountNumber())
+			assert.Equal(t, uint64(42), got.GetSequence())
+			assert.Equal(t, base.GetAddress(), got.GetAddress())
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cometbft/cometbft/light"
+	cmttypes "github.com/cometbft/cometbft/types"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/config"
+)
+
+var _ client.HeaderVerifier = (*lightVerifier)(nil)
+
+// lightVerifier verifies headers with a light client which follows a primary node, and cross-checks it against
+// the other nodes as witnesses. The light client is created on first use, so that the chain starts while nodes
+// are unreachable.
+type lightVerifier struct {
+	chainID string
+	cfg     *config.Reloadable
+
+	mu     sync.Mutex
+	client *light.Client
+}
+
+func (v *lightVerifier) VerifyLightBlockAtHeight(ctx context.Context, height int64, now time.Time) (*cmttypes.LightBlock, error) {
+	lc, err := v.lightClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create light client: %w", err)
+	}
+	return lc.VerifyLightBlockAtHeight(ctx, height, now)
+}
+
+func (v *lightVerifier) lightClient(ctx context.Context) (*light.Client, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.client != nil {
+		return v.client, nil
+	}
+	cfg := v.cfg.Get()
+	trustOptions, ok := cfg.TrustOptions()
+	if !ok {
+		return nil, errors.New("verified reads are disabled")
+	}
+	primary, err := cfg.PickNode()
+	if err != nil {
+		return nil, err
+	}
+	primaryNode, err := lightNode(primary)
+	if err != nil {
+		return nil, err
+	}
+	var witnesses []client.LightNode
+	for _, n := range cfg.Nodes {
+		if n == primary {
+			continue
+		}
+		w, err := lightNode(n)
+		if err != nil {
+			return nil, err
+		}
+		witnesses = append(witnesses, w)
+	}
+	lc, err := client.NewLightClient(ctx, v.chainID, trustOptions, primaryNode, witnesses)
+	if err != nil {
+		return nil, err
+	}
+	v.client = lc
+	return lc, nil
+}
+
+// reset discards the light client, so that the next verification creates one from the current config.
+// Headers verified so far are discarded too, so it starts again from the trusted header.
+func (v *lightVerifier) reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.client = nil
+}
+
+func lightNode(n *config.Node) (client.LightNode, error) {
+	httpOpts, err := n.HTTPOptions()
+	if err != nil {
+		return client.LightNode{}, fmt.Errorf("failed to configure node %s: %w", *n.Name, err)
+	}
+	return client.LightNode{TendermintURL: n.TendermintURL.String(), RequestTimeout: n.GetRequestTimeout(), HTTPOptions: httpOpts}, nil
+}
@@ -0,0 +1,18 @@
+package params
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// ChainParams are the parameters of a chain, as reported by a node.
+type ChainParams struct {
+	ChainID      string
+	Bech32Prefix string
+	BondDenom    string
+	// MinGasPrices are the minimum gas prices accepted by the node. Empty if any price is accepted.
+	MinGasPrices sdk.DecCoins
+	// BlockRate is the average time between recent blocks.
+	BlockRate time.Duration
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sync"
 
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/cosmos/cosmos-sdk/codec/types"
@@ -11,6 +12,10 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth/tx"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/ethermint"
 )
 
 // encodingConfig specifies the concrete encoding types to use for a given app.
@@ -43,7 +48,9 @@ var config = makeEncodingConfig()
 
 var initOnce sync.Once
 
-// Initialize the cosmos sdk at most one time
+// Initialize the cosmos sdk at most one time.
+// The bech32 prefix is global, so only the prefix of the first chain is used, and only by sdk internals,
+// such as Msg.ValidateBasic and Msg.GetSigners. Addresses of each chain are encoded with an AddressCodec instead.
 func InitCosmosSdk(bech32Prefix, token string) {
 	initOnce.Do(func() { initCosmosSdk(bech32Prefix, token) })
 }
@@ -73,6 +80,13 @@ func initCosmosSdk(bech32Prefix, token string) {
 	std.RegisterInterfaces(config.InterfaceRegistry)
 	// needed for Client.Account() to deserialize authtypes.AccountI
 	authtypes.RegisterInterfaces(config.InterfaceRegistry)
+	// needed for the authz MsgExec sent by sender pools
+	authz.RegisterInterfaces(config.InterfaceRegistry)
+	// needed for the accounts and keys of chains with eth_secp256k1 keys
+	ethermint.RegisterInterfaces(config.InterfaceRegistry)
+	// needed to decode the txs exported for offline signing
+	wasmtypes.RegisterInterfaces(config.InterfaceRegistry)
+	banktypes.RegisterInterfaces(config.InterfaceRegistry)
 
 	sdkConfig := sdk.GetConfig()
 	sdkConfig.SetBech32PrefixForAccount(bech32PrefixAccAddr, bech32PrefixAccPub)
@@ -80,20 +94,39 @@ func initCosmosSdk(bech32Prefix, token string) {
 	sdkConfig.SetBech32PrefixForConsensusNode(bech32PrefixConsAddr, bech32PrefixConsPub)
 	sdkConfig.Seal()
 
-	for _, d := range []struct {
-		denom    string
-		decimals int64
-	}{
+	for _, d := range tokenDenoms(token) {
+		dec := sdk.NewDecWithPrec(1, d.decimals)
+		if err := sdk.RegisterDenom(d.denom, dec); err != nil {
+			panic(fmt.Errorf("failed to register denomination %q: %w", d.denom, err))
+		}
+	}
+}
+
+type tokenDenom struct {
+	denom    string
+	decimals int64
+}
+
+// tokenDenoms returns the denominations registered for token.
+func tokenDenoms(token string) []tokenDenom {
+	return []tokenDenom{
 		{token, 0},
 		{"m" + token, 3},
 		{"u" + token, 6},
 		{"n" + token, 9},
-	} {
-		dec := sdk.NewDecWithPrec(1, d.decimals)
-		if err := sdk.RegisterDenom(d.denom, dec); err != nil {
-			panic(fmt.Errorf("failed to register denomination %q: %w", d.denom, err))
+	}
+}
+
+// ValidateGasToken returns an error if InitCosmosSdk cannot register token.
+// The sdk may have been initialized with other denominations, since the units of each chain's
+// denominations are held by its own denom.Registry.
+func ValidateGasToken(token string) error {
+	for _, d := range tokenDenoms(token) {
+		if err := sdk.ValidateDenom(d.denom); err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 func NewClientContext() client.Context {
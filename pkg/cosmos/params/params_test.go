@@ -8,6 +8,9 @@ import (
 )
 
 func TestInitCosmosSdk(t *testing.T) {
+	assert.NoError(t, ValidateGasToken("cosmos"), "any valid denom before the sdk is initialized")
+	assert.Error(t, ValidateGasToken("1atom"))
+
 	// sdk initialized only once
 	assert.NotPanics(t, func() { InitCosmosSdk("wasm", "atom") })
 	assert.NotPanics(t, func() { InitCosmosSdk("notwasm", "cosmos") })
@@ -21,4 +24,31 @@ func TestInitCosmosSdk(t *testing.T) {
 	assert.True(t, ok)
 	_, ok = sdk.GetDenomUnit("cosmos")
 	assert.False(t, ok)
+
+	assert.NoError(t, ValidateGasToken("atom"))
+	assert.NoError(t, ValidateGasToken("uatom"))
+	assert.NoError(t, ValidateGasToken("cosmos"), "other chains may have other denoms")
+}
+
+func TestAddressCodec(t *testing.T) {
+	addr := sdk.AccAddress{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11, 0x12, 0x13, 0x14}
+	wasm, neutron := NewAddressCodec("wasm"), NewAddressCodec("neutron")
+
+	wasmAddr, err := wasm.BytesToString(addr)
+	assert.NoError(t, err)
+	assert.Regexp(t, "^wasm1", wasmAddr)
+	neutronAddr, err := neutron.BytesToString(addr)
+	assert.NoError(t, err)
+	assert.Regexp(t, "^neutron1", neutronAddr)
+
+	got, err := neutron.StringToBytes(neutronAddr)
+	assert.NoError(t, err)
+	assert.Equal(t, addr, got)
+
+	_, err = neutron.StringToBytes(wasmAddr)
+	assert.ErrorContains(t, err, "invalid bech32 prefix: expected neutron, got wasm")
+	_, err = wasm.StringToBytes("")
+	assert.Error(t, err)
+	_, err = wasm.StringToBytes("wasm1invalid")
+	assert.Error(t, err)
 }
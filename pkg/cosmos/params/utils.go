@@ -4,9 +4,12 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/cometbft/cometbft/crypto"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/bech32"
 	"golang.org/x/crypto/ripemd160" //nolint: staticcheck
 )
@@ -33,3 +36,54 @@ func CreateBech32Address(pubKey, accountPrefix string) (string, error) {
 	}
 	return bech32Addr, nil
 }
+
+// AddressCodec converts account addresses to and from bech32 strings with the prefix of a single chain.
+// Unlike sdk.AccAddress.String and sdk.AccAddressFromBech32, it does not depend on the global sdk config,
+// so that chains with different prefixes can be used at the same time.
+type AddressCodec struct {
+	prefix string
+}
+
+func NewAddressCodec(prefix string) AddressCodec {
+	return AddressCodec{prefix: prefix}
+}
+
+// Prefix returns the bech32 prefix of account addresses.
+func (c AddressCodec) Prefix() string {
+	return c.prefix
+}
+
+// StringToBytes decodes a bech32 account address, which must have the prefix of the chain.
+func (c AddressCodec) StringToBytes(text string) (sdk.AccAddress, error) {
+	if len(strings.TrimSpace(text)) == 0 {
+		return nil, errors.New("empty address string is not allowed")
+	}
+	hrp, bz, err := bech32.DecodeAndConvert(text)
+	if err != nil {
+		return nil, err
+	}
+	if hrp != c.prefix {
+		return nil, fmt.Errorf("invalid bech32 prefix: expected %s, got %s", c.prefix, hrp)
+	}
+	if err := sdk.VerifyAddressFormat(bz); err != nil {
+		return nil, err
+	}
+	return bz, nil
+}
+
+// BytesToString encodes an account address as bech32, with the prefix of the chain.
+func (c AddressCodec) BytesToString(addr sdk.AccAddress) (string, error) {
+	if len(addr) == 0 {
+		return "", nil
+	}
+	return bech32.ConvertAndEncode(c.prefix, addr)
+}
+
+// MustBytesToString is like BytesToString, but panics on error, which is only possible for an invalid prefix.
+func (c AddressCodec) MustBytesToString(addr sdk.AccAddress) string {
+	s, err := c.BytesToString(addr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
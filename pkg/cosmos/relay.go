@@ -3,6 +3,7 @@ package cosmos
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/goplugin/plugin-common/pkg/logger"
@@ -20,6 +21,20 @@ const (
 	InjectivePrefix string = "inj"
 )
 
+// Adapters are the adapters available to relayers. Other adapters may be registered before relayers are created.
+var Adapters = adapters.NewRegistry()
+
+func init() {
+	for name, adapter := range map[string]adapters.Adapter{
+		adapters.AdapterCosmWasm:        cosmwasm.Adapter,
+		adapters.AdapterInjectiveModule: injective.Adapter,
+	} {
+		if err := Adapters.Register(name, adapter); err != nil {
+			panic(err)
+		}
+	}
+}
+
 // ErrMsgUnsupported is returned when an unsupported type of message is encountered.
 // Deprecated: use txm.ErrMsgUnsupported
 type ErrMsgUnsupported = txm.ErrMsgUnsupported
@@ -101,27 +116,25 @@ func (r *Relayer) NewFunctionsProvider(ctx context.Context, rargs types.RelayArg
 }
 
 func (r *Relayer) NewConfigProvider(ctx context.Context, args types.RelayArgs) (types.ConfigProvider, error) {
-	var configProvider types.ConfigProvider
-	var err error
-	if r.chain.Config().Bech32Prefix() == InjectivePrefix {
-		configProvider, err = injective.NewConfigProvider(ctx, r.lggr, r.chain, args)
-	} else {
-		// Default to cosmwasm adapter
-		configProvider, err = cosmwasm.NewConfigProvider(ctx, r.lggr, r.chain, args)
-	}
+	name, adapter, err := Adapters.ForRelay(r.chain.Config(), args)
 	if err != nil {
 		return nil, err
 	}
-
-	return configProvider, err
+	if adapter.NewConfigProvider == nil {
+		return nil, fmt.Errorf("config provider is not supported by the %s adapter", name)
+	}
+	return adapter.NewConfigProvider(ctx, r.lggr, r.chain, args)
 }
 
 func (r *Relayer) NewMedianProvider(ctx context.Context, rargs types.RelayArgs, pargs types.PluginArgs) (types.MedianProvider, error) {
-	configProvider, err := cosmwasm.NewMedianProvider(ctx, r.lggr, r.chain, rargs, pargs)
+	name, adapter, err := Adapters.ForRelay(r.chain.Config(), rargs)
 	if err != nil {
 		return nil, err
 	}
-	return configProvider, err
+	if adapter.NewMedianProvider == nil {
+		return nil, fmt.Errorf("median is not supported by the %s adapter", name)
+	}
+	return adapter.NewMedianProvider(ctx, r.lggr, r.chain, rargs, pargs)
 }
 
 func (r *Relayer) NewAutomationProvider(ctx context.Context, rargs types.RelayArgs, pargs types.PluginArgs) (types.AutomationProvider, error) {
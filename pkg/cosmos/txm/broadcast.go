@@ -0,0 +1,147 @@
+package txm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	errorsmod "cosmossdk.io/errors"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
+)
+
+// unhealthyBroadcastPeriod is how long a node whose broadcast failed is picked after the healthy nodes.
+const unhealthyBroadcastPeriod = time.Minute
+
+// BroadcastClients returns a client for each node which txs may be broadcast to, including sendonly nodes,
+// keyed by node name. The map may be reused by later calls, so must not be modified.
+type BroadcastClients func() (map[string]client.Writer, error)
+
+// SetBroadcastClients sets the clients of the nodes which txs are broadcast to when the BroadcastFanOut is not 1.
+// Without them, txs are broadcast to a single primary node. It must be called before Start.
+func (txm *Txm) SetBroadcastClients(clients BroadcastClients) {
+	txm.broadcaster.clients = clients
+}
+
+// broadcaster fans txs out to several nodes, and tracks which of them recently failed to accept them.
+type broadcaster struct {
+	clients BroadcastClients
+
+	mu sync.Mutex
+	// unhealthy are the times until which nodes are picked last.
+	unhealthy map[string]time.Time
+}
+
+func newBroadcaster() *broadcaster {
+	return &broadcaster{unhealthy: map[string]time.Time{}}
+}
+
+// pick returns fanOut of the nodes in random order, healthy nodes first.
+// A fanOut of 0 picks every healthy node, or every node if none are healthy.
+func (b *broadcaster) pick(nodes []string, fanOut int64, now time.Time) []string {
+	nodes = append([]string(nil), nodes...)
+	rand.Shuffle(len(nodes), func(i, j int) { nodes[i], nodes[j] = nodes[j], nodes[i] })
+
+	b.mu.Lock()
+	var healthy, unhealthy []string
+	for _, n := range nodes {
+		if now.Before(b.unhealthy[n]) {
+			unhealthy = append(unhealthy, n)
+		} else {
+			healthy = append(healthy, n)
+		}
+	}
+	b.mu.Unlock()
+
+	if fanOut == 0 {
+		if len(healthy) > 0 {
+			return healthy
+		}
+		return unhealthy
+	}
+	picked := append(healthy, unhealthy...)
+	if int64(len(picked)) > fanOut {
+		picked = picked[:fanOut]
+	}
+	return picked
+}
+
+// report records the result of a broadcast to node. Nodes which could not be reached or have a full mempool
+// are unhealthy for a while.
+func (b *broadcaster) report(node string, resp *txtypes.BroadcastTxResponse, err error, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		delete(b.unhealthy, node)
+		return
+	}
+	if resp == nil || resp.TxResponse == nil || isABCIError(resp, sdkerrors.ErrMempoolIsFull) {
+		b.unhealthy[node] = now.Add(unhealthyBroadcastPeriod)
+	}
+}
+
+// broadcast broadcasts txBytes with tc, or to BroadcastFanOut nodes in parallel if the broadcast clients are set,
+// returning the first response of a node which admitted the tx to its mempool.
+func (txm *Txm) broadcast(ctx context.Context, tc client.Writer, txBytes []byte) (*txtypes.BroadcastTxResponse, error) {
+	fanOut := txm.cfg.BroadcastFanOut()
+	if fanOut == 1 || txm.broadcaster.clients == nil {
+		return inMempool(tc.Broadcast(ctx, txBytes, txtypes.BroadcastMode_BROADCAST_MODE_SYNC))
+	}
+	clients, err := txm.broadcaster.clients()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get broadcast clients: %w", err)
+	}
+	nodes := make([]string, 0, len(clients))
+	for name := range clients {
+		nodes = append(nodes, name)
+	}
+	picked := txm.broadcaster.pick(nodes, fanOut, time.Now())
+	if len(picked) == 0 {
+		return nil, errors.New("no nodes to broadcast to")
+	}
+
+	type result struct {
+		node string
+		resp *txtypes.BroadcastTxResponse
+		err  error
+	}
+	// buffered so that the remaining broadcasts finish after the first success is returned
+	results := make(chan result, len(picked))
+	for _, node := range picked {
+		go func() {
+			resp, err := inMempool(clients[node].Broadcast(ctx, txBytes, txtypes.BroadcastMode_BROADCAST_MODE_SYNC))
+			txm.broadcaster.report(node, resp, err, time.Now())
+			results <- result{node, resp, err}
+		}()
+	}
+	var errs error
+	for range picked {
+		r := <-results
+		if r.err == nil {
+			txm.lggr.Debugw("tx admitted to mempool", "node", r.node, "fanOut", len(picked))
+			return r.resp, nil
+		}
+		errs = errors.Join(errs, fmt.Errorf("node %s: %w", r.node, r.err))
+	}
+	return nil, errs
+}
+
+// inMempool treats a tx which is already in the mempool of the node as successfully broadcast,
+// as happens when it was broadcast to another node first.
+func inMempool(resp *txtypes.BroadcastTxResponse, err error) (*txtypes.BroadcastTxResponse, error) {
+	if err != nil && isABCIError(resp, sdkerrors.ErrTxInMempoolCache) {
+		return resp, nil
+	}
+	return resp, err
+}
+
+// isABCIError returns whether resp is the CheckTx result of abciErr.
+func isABCIError(resp *txtypes.BroadcastTxResponse, abciErr *errorsmod.Error) bool {
+	return resp != nil && resp.TxResponse != nil &&
+		resp.TxResponse.Codespace == abciErr.Codespace() && resp.TxResponse.Code == abciErr.ABCICode()
+}
@@ -0,0 +1,109 @@
+package txm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goplugin/plugin-common/pkg/logger"
+	"github.com/goplugin/plugin-common/pkg/utils/tests"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client/mocks"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/config"
+)
+
+func TestBroadcaster_pick(t *testing.T) {
+	now := time.Now()
+	nodes := []string{"a", "b", "c"}
+
+	b := newBroadcaster()
+	assert.ElementsMatch(t, nodes, b.pick(nodes, 0, now))
+	assert.Len(t, b.pick(nodes, 2, now), 2)
+	assert.ElementsMatch(t, nodes, b.pick(nodes, 5, now))
+
+	b.report("a", nil, errors.New("connection refused"), now)
+	b.report("b", abciResponse(sdkerrors.ErrMempoolIsFull), errors.New("mempool is full"), now)
+	b.report("c", abciResponse(sdkerrors.ErrInvalidSequence), errors.New("invalid sequence"), now)
+	assert.Equal(t, []string{"c"}, b.pick(nodes, 0, now), "unhealthy nodes are skipped")
+	assert.Equal(t, []string{"c"}, b.pick(nodes, 1, now), "healthy nodes are picked first")
+	assert.ElementsMatch(t, nodes, b.pick(nodes, 3, now), "unhealthy nodes fill the fan out")
+
+	b.report("c", nil, errors.New("connection refused"), now)
+	assert.ElementsMatch(t, nodes, b.pick(nodes, 0, now), "all nodes are picked when none are healthy")
+	assert.ElementsMatch(t, nodes, b.pick(nodes, 0, now.Add(unhealthyBroadcastPeriod)), "nodes recover")
+
+	b.report("a", &txtypes.BroadcastTxResponse{TxResponse: &sdk.TxResponse{}}, nil, now)
+	assert.Equal(t, []string{"a"}, b.pick(nodes, 0, now))
+}
+
+func TestTxm_broadcast(t *testing.T) {
+	ctx := tests.Context(t)
+	lggr := logger.Test(t)
+	txBytes := []byte("tx")
+	ok := &txtypes.BroadcastTxResponse{TxResponse: &sdk.TxResponse{TxHash: "hash"}}
+	newTxm := func(fanOut int64, clients map[string]client.Writer) *Txm {
+		cfg := &config.TOMLConfig{Chain: config.Chain{BroadcastFanOut: ptr(fanOut)}}
+		cfg.SetDefaults()
+		txm := NewTxm(nil, nil, *client.NewMustGasPriceEstimator(nil, lggr), RandomChainID(), cfg, nil, lggr)
+		if clients != nil {
+			txm.SetBroadcastClients(func() (map[string]client.Writer, error) { return clients, nil })
+		}
+		return txm
+	}
+
+	t.Run("single node", func(t *testing.T) {
+		tc := mocks.NewReaderWriter(t)
+		other := mocks.NewReaderWriter(t) // not used
+		tc.On("Broadcast", mock.Anything, txBytes, txtypes.BroadcastMode_BROADCAST_MODE_SYNC).Return(ok, nil).Once()
+		resp, err := newTxm(1, map[string]client.Writer{"other": other}).broadcast(ctx, tc, txBytes)
+		require.NoError(t, err)
+		assert.Equal(t, ok, resp)
+	})
+
+	t.Run("already in mempool", func(t *testing.T) {
+		tc := mocks.NewReaderWriter(t)
+		inCache := abciResponse(sdkerrors.ErrTxInMempoolCache)
+		tc.On("Broadcast", mock.Anything, txBytes, mock.Anything).Return(inCache, errors.New("tx failed with error code: 19")).Once()
+		resp, err := newTxm(1, nil).broadcast(ctx, tc, txBytes)
+		require.NoError(t, err)
+		assert.Equal(t, inCache, resp)
+	})
+
+	t.Run("every node", func(t *testing.T) {
+		down, full, admitted := mocks.NewReaderWriter(t), mocks.NewReaderWriter(t), mocks.NewReaderWriter(t)
+		down.On("Broadcast", mock.Anything, txBytes, mock.Anything).Return(nil, errors.New("connection refused")).Once()
+		full.On("Broadcast", mock.Anything, txBytes, mock.Anything).Return(abciResponse(sdkerrors.ErrMempoolIsFull), errors.New("tx failed with error code: 20")).Once()
+		admitted.On("Broadcast", mock.Anything, txBytes, mock.Anything).Return(ok, nil).Once()
+		txm := newTxm(0, map[string]client.Writer{"down": down, "full": full, "admitted": admitted})
+		resp, err := txm.broadcast(ctx, nil, txBytes)
+		require.NoError(t, err)
+		assert.Equal(t, ok, resp)
+
+		// wait for the remaining broadcasts
+		require.Eventually(t, func() bool {
+			return len(txm.broadcaster.pick([]string{"down", "full", "admitted"}, 0, time.Now())) == 1
+		}, tests.WaitTimeout(t), 10*time.Millisecond)
+	})
+
+	t.Run("all fail", func(t *testing.T) {
+		a, b := mocks.NewReaderWriter(t), mocks.NewReaderWriter(t)
+		a.On("Broadcast", mock.Anything, txBytes, mock.Anything).Return(nil, errors.New("connection refused")).Once()
+		b.On("Broadcast", mock.Anything, txBytes, mock.Anything).Return(abciResponse(sdkerrors.ErrInvalidSequence), errors.New("tx failed with error code: 3")).Once()
+		_, err := newTxm(2, map[string]client.Writer{"a": a, "b": b}).broadcast(ctx, nil, txBytes)
+		require.ErrorContains(t, err, "node a: connection refused")
+		require.ErrorContains(t, err, "node b: tx failed with error code: 3")
+	})
+}
+
+func abciResponse(err *errorsmod.Error) *txtypes.BroadcastTxResponse {
+	return &txtypes.BroadcastTxResponse{TxResponse: &sdk.TxResponse{Codespace: err.Codespace(), Code: err.ABCICode()}}
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 
-	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 )
 
@@ -36,7 +35,7 @@ func (a *KeyWrapper) PubKey() cryptotypes.PubKey {
 	pubKey, err := a.adapter.PubKey(context.Background(), a.account)
 	if err != nil {
 		// return an empty pubkey if it's not found.
-		return &secp256k1.PubKey{Key: []byte{}}
+		return a.adapter.algorithm.PubKey([]byte{})
 	}
 	return pubKey
 }
@@ -46,7 +45,7 @@ func (a *KeyWrapper) Equals(other cryptotypes.LedgerPrivKey) bool {
 }
 
 func (a *KeyWrapper) Type() string {
-	return "secp256k1"
+	return a.adapter.algorithm.PubKey(nil).Type()
 }
 
 func (a *KeyWrapper) Reset() {
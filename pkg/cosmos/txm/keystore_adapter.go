@@ -2,37 +2,53 @@ package txm
 
 import (
 	"context"
-	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"sync"
 
-	"github.com/cometbft/cometbft/crypto"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
-	"github.com/cosmos/cosmos-sdk/types/bech32"
-	"golang.org/x/crypto/ripemd160" //nolint: staticcheck
 
 	"github.com/goplugin/plugin-common/pkg/loop"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/config"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/ethermint"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
 )
 
+var errNoSuchID = errors.New("No such id")
+
+// DigestKeystore is a loop.Keystore which can also sign digests as they are. loop.Keystore.Sign of cosmos keys
+// hashes the data it signs with sha256, as secp256k1 keys do, but eth_secp256k1 keys sign the keccak256 digest
+// of their sign bytes, so their keystore must implement DigestKeystore.
+type DigestKeystore interface {
+	loop.Keystore
+	// SignDigest signs digest, which is 32 bytes, with the key of account, without hashing it. The signature is
+	// [R || S], or [R || S || V] with a recovery id V of 0 or 1, which may be offset by 27.
+	SignDigest(ctx context.Context, account string, digest []byte) ([]byte, error)
+}
+
 type accountInfo struct {
 	Account string
-	PubKey  *secp256k1.PubKey
+	PubKey  cryptotypes.PubKey
 }
 
 // keystoreAdapter adapts a Cosmos loop.Keystore to translate public keys into bech32-prefixed account addresses.
+// The keys are of a single algorithm, which determines their addresses and how they sign.
 type keystoreAdapter struct {
 	keystore        loop.Keystore
-	accountPrefix   string
+	addressCodec    params.AddressCodec
+	algorithm       config.KeyAlgorithm
 	mutex           sync.RWMutex
 	addressToPubKey map[string]*accountInfo
 }
 
-func newKeystoreAdapter(keystore loop.Keystore, accountPrefix string) *keystoreAdapter {
+func newKeystoreAdapter(keystore loop.Keystore, addressCodec params.AddressCodec, algorithm config.KeyAlgorithm) *keystoreAdapter {
 	return &keystoreAdapter{
 		keystore:        keystore,
-		accountPrefix:   accountPrefix,
+		addressCodec:    addressCodec,
+		algorithm:       algorithm,
 		addressToPubKey: make(map[string]*accountInfo),
 	}
 }
@@ -66,23 +82,25 @@ func (ka *keystoreAdapter) updateMappingLocked(ctx context.Context) error {
 			return err
 		}
 
-		if len(pubKeyBytes) != secp256k1.PubKeySize {
+		if ka.algorithm.IsEthereum() {
+			// the keystore may export eth keys uncompressed
+			pubKeyBytes, err = ethermint.CompressPubKey(pubKeyBytes)
+			if err != nil {
+				return err
+			}
+		} else if len(pubKeyBytes) != secp256k1.PubKeySize {
 			return errors.New("length of pubkey is incorrect")
 		}
+		pubKey := ka.algorithm.PubKey(pubKeyBytes)
 
-		sha := sha256.Sum256(pubKeyBytes)
-		hasherRIPEMD160 := ripemd160.New()
-		_, _ = hasherRIPEMD160.Write(sha[:])
-		address := crypto.Address(hasherRIPEMD160.Sum(nil))
-
-		bech32Addr, err := bech32.ConvertAndEncode(ka.accountPrefix, address)
+		bech32Addr, err := ka.addressCodec.BytesToString(pubKey.Address().Bytes())
 		if err != nil {
 			return err
 		}
 
 		addressToPubKey[bech32Addr] = &accountInfo{
 			Account: account,
-			PubKey:  &secp256k1.PubKey{Key: pubKeyBytes},
+			PubKey:  pubKey,
 		}
 	}
 
@@ -105,18 +123,33 @@ func (ka *keystoreAdapter) lookup(ctx context.Context, id string) (*accountInfo,
 		ai, ok = ka.addressToPubKey[id]
 		ka.mutex.Unlock()
 		if !ok {
-			return nil, errors.New("No such id")
+			return nil, errNoSuchID
 		}
 	}
 	return ai, nil
 }
 
-func (ka *keystoreAdapter) Sign(ctx context.Context, id string, hash []byte) ([]byte, error) {
+// Sign signs msg with the key of id. The keystore signs secp256k1 msgs with Sign, which hashes them, and the
+// keccak256 digest of eth_secp256k1 msgs with DigestKeystore.SignDigest. The signature of the digest is then
+// converted to the [R || S || V] form of Ethermint chains.
+func (ka *keystoreAdapter) Sign(ctx context.Context, id string, msg []byte) ([]byte, error) {
 	accountInfo, err := ka.lookup(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	return ka.keystore.Sign(ctx, accountInfo.Account, hash)
+	if !ka.algorithm.IsEthereum() {
+		return ka.keystore.Sign(ctx, accountInfo.Account, msg)
+	}
+	ks, ok := ka.keystore.(DigestKeystore)
+	if !ok {
+		return nil, fmt.Errorf("keystore must implement DigestKeystore to sign with %s keys", ka.algorithm)
+	}
+	digest := ethermint.Keccak256(msg)
+	sig, err := ks.SignDigest(ctx, accountInfo.Account, digest)
+	if err != nil {
+		return nil, err
+	}
+	return ethermint.NormalizeSignature(sig, digest, accountInfo.PubKey.Bytes())
 }
 
 // Returns the cosmos PubKey associated with the prefixed address.
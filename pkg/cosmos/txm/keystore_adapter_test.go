@@ -0,0 +1,92 @@
+package txm
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	sdksecp256k1 "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goplugin/plugin-common/pkg/utils/tests"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/config"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/ethermint"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
+)
+
+// cosmosKeystore is a loop.Keystore like that of the cosmos keys of a node, whose Sign hashes data with sha256.
+type cosmosKeystore struct {
+	key *sdksecp256k1.PrivKey
+}
+
+func (k *cosmosKeystore) Accounts(ctx context.Context) ([]string, error) {
+	pubKey, err := secp256k1.ParsePubKey(k.key.PubKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	// uncompressed, as exported by some keystores
+	return []string{hex.EncodeToString(pubKey.SerializeUncompressed())}, nil
+}
+
+func (k *cosmosKeystore) Sign(ctx context.Context, account string, data []byte) ([]byte, error) {
+	return k.key.Sign(data)
+}
+
+// digestKeystore is a DigestKeystore, whose SignDigest returns [R || S || V] like the Sign of go-ethereum.
+type digestKeystore struct {
+	cosmosKeystore
+}
+
+func (k *digestKeystore) SignDigest(ctx context.Context, account string, digest []byte) ([]byte, error) {
+	compact := ecdsa.SignCompact(secp256k1.PrivKeyFromBytes(k.key.Key), digest, false)
+	return append(compact[1:], compact[0]-27), nil
+}
+
+func TestKeystoreAdapter_EthSecp256k1(t *testing.T) {
+	ctx := tests.Context(t)
+	var b [32]byte
+	b[31] = 1
+	key := &sdksecp256k1.PrivKey{Key: b[:]}
+	ks := &digestKeystore{cosmosKeystore{key: key}}
+
+	for _, algorithm := range []config.KeyAlgorithm{config.KeyAlgorithmEthSecp256k1, config.KeyAlgorithmInjectiveEthSecp256k1} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			codec := params.NewAddressCodec("inj")
+			adapter := newKeystoreAdapter(ks, codec, algorithm)
+			accounts, err := adapter.Accounts(ctx)
+			require.NoError(t, err)
+			require.Len(t, accounts, 1)
+			addr, err := codec.StringToBytes(accounts[0])
+			require.NoError(t, err)
+			assert.Equal(t, "7e5f4552091a69125d5dfcb7b8c2659029395bdf", hex.EncodeToString(addr), "ethereum address")
+
+			signer := NewKeyWrapper(adapter, accounts[0])
+			assert.Equal(t, ethermint.KeyType, signer.Type())
+			pubKey := signer.PubKey()
+			assert.Equal(t, algorithm.PubKey(nil).Type(), pubKey.Type())
+			assert.IsType(t, algorithm.PubKey(nil), pubKey)
+			assert.Equal(t, key.PubKey().Bytes(), pubKey.Bytes())
+
+			msg := []byte("sign bytes")
+			sig, err := signer.Sign(msg)
+			require.NoError(t, err)
+			require.Len(t, sig, ethermint.SignatureSize)
+			assert.Less(t, sig[64], byte(2), "recovery id is not offset")
+			assert.True(t, pubKey.VerifySignature(msg, sig))
+		})
+	}
+
+	t.Run("without DigestKeystore", func(t *testing.T) {
+		codec := params.NewAddressCodec("inj")
+		adapter := newKeystoreAdapter(&ks.cosmosKeystore, codec, config.KeyAlgorithmEthSecp256k1)
+		accounts, err := adapter.Accounts(ctx)
+		require.NoError(t, err)
+		require.Len(t, accounts, 1)
+		_, err = NewKeyWrapper(adapter, accounts[0]).Sign([]byte("sign bytes"))
+		require.EqualError(t, err, "keystore must implement DigestKeystore to sign with eth_secp256k1 keys")
+	})
+}
@@ -0,0 +1,92 @@
+package txm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
+)
+
+// Cosigner collects the signatures of the cosigners of a multisig account whose keys are not in the keystore,
+// such as those held by other operators.
+type Cosigner interface {
+	// Cosign returns the signature of signBytes by each of pubKeys, or nil for each which did not sign.
+	// The signBytes are the legacy amino JSON sign bytes of a tx sent by the multisig account.
+	Cosign(ctx context.Context, multisig string, signBytes []byte, pubKeys []cryptotypes.PubKey) ([][]byte, error)
+}
+
+// SetCosigner sets the Cosigner of the multisig accounts. It must be called before Start.
+func (txm *Txm) SetCosigner(cosigner Cosigner) {
+	txm.cosigner = cosigner
+}
+
+// multisigSigner signs for a multisig account with the keys of its cosigners in the keystore,
+// and asks the Cosigner for the rest, until the threshold is reached.
+type multisigSigner struct {
+	ctx     context.Context
+	txm     *Txm
+	address string
+	pubKey  *kmultisig.LegacyAminoPubKey
+}
+
+var _ client.MultisigSigner = &multisigSigner{}
+
+func (s *multisigSigner) PubKey() *kmultisig.LegacyAminoPubKey {
+	return s.pubKey
+}
+
+func (s *multisigSigner) Sign(signBytes []byte) ([][]byte, error) {
+	keys := s.pubKey.GetPubKeys()
+	sigs := make([][]byte, len(keys))
+	var signed uint32
+	var missing []int
+	for i, key := range keys {
+		if signed == s.pubKey.Threshold {
+			break
+		}
+		id, err := s.txm.addressCodec.BytesToString(key.Address().Bytes())
+		if err != nil {
+			return nil, err
+		}
+		sig, err := s.txm.keystoreAdapter.Sign(s.ctx, id, signBytes)
+		if errors.Is(err, errNoSuchID) {
+			missing = append(missing, i)
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to sign with cosigner %s: %w", id, err)
+		}
+		sigs[i] = sig
+		signed++
+	}
+	if signed == s.pubKey.Threshold || len(missing) == 0 || s.txm.cosigner == nil {
+		return sigs, nil
+	}
+
+	pubKeys := make([]cryptotypes.PubKey, len(missing))
+	for j, i := range missing {
+		pubKeys[j] = keys[i]
+	}
+	cosigned, err := s.txm.cosigner.Cosign(s.ctx, s.address, signBytes, pubKeys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cosign: %w", err)
+	}
+	if len(cosigned) != len(pubKeys) {
+		return nil, fmt.Errorf("cosigner returned %d signatures for %d keys", len(cosigned), len(pubKeys))
+	}
+	for j, sig := range cosigned {
+		if sig == nil {
+			continue
+		}
+		if !pubKeys[j].VerifySignature(signBytes, sig) {
+			// the other signatures may still reach the threshold
+			s.txm.lggr.Warnw("Ignoring invalid signature from cosigner", "multisig", s.address, "pubKey", pubKeys[j].String())
+			continue
+		}
+		sigs[missing[j]] = sig
+	}
+	return sigs, nil
+}
@@ -0,0 +1,107 @@
+package txm
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goplugin/plugin-common/pkg/logger"
+	"github.com/goplugin/plugin-common/pkg/utils/tests"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/config"
+)
+
+// secpKeystore is a loop.Keystore of secp256k1 keys.
+type secpKeystore struct {
+	keys []*secp256k1.PrivKey
+}
+
+func (k *secpKeystore) Accounts(ctx context.Context) (accounts []string, err error) {
+	for _, key := range k.keys {
+		accounts = append(accounts, hex.EncodeToString(key.PubKey().Bytes()))
+	}
+	return
+}
+
+func (k *secpKeystore) Sign(ctx context.Context, account string, data []byte) ([]byte, error) {
+	for _, key := range k.keys {
+		if hex.EncodeToString(key.PubKey().Bytes()) == account {
+			return key.Sign(data)
+		}
+	}
+	return nil, errNoSuchID
+}
+
+type testCosigner struct {
+	sigs  map[string][]byte
+	calls int
+}
+
+func (c *testCosigner) Cosign(ctx context.Context, multisig string, signBytes []byte, pubKeys []cryptotypes.PubKey) ([][]byte, error) {
+	c.calls++
+	sigs := make([][]byte, len(pubKeys))
+	for i, pk := range pubKeys {
+		sigs[i] = c.sigs[pk.String()]
+	}
+	return sigs, nil
+}
+
+func TestMultisigSigner(t *testing.T) {
+	ctx := tests.Context(t)
+	lggr := logger.Test(t)
+	keys := []*secp256k1.PrivKey{secp256k1.GenPrivKey(), secp256k1.GenPrivKey(), secp256k1.GenPrivKey()}
+	pubKey := kmultisig.NewLegacyAminoPubKey(2, []cryptotypes.PubKey{keys[0].PubKey(), keys[1].PubKey(), keys[2].PubKey()})
+	signBytes := []byte("sign bytes")
+	cosignerSig, err := keys[2].Sign(signBytes)
+	require.NoError(t, err)
+
+	cfg := &config.TOMLConfig{}
+	cfg.SetDefaults()
+	gpe := client.NewMustGasPriceEstimator(nil, lggr)
+	newSigner := func(ks *secpKeystore, cosigner Cosigner) *multisigSigner {
+		txm := NewTxm(nil, nil, *gpe, RandomChainID(), cfg, ks, lggr)
+		if cosigner != nil {
+			txm.SetCosigner(cosigner)
+		}
+		return &multisigSigner{ctx: ctx, txm: txm, address: "multisig", pubKey: pubKey}
+	}
+
+	t.Run("keystore and cosigner", func(t *testing.T) {
+		cosigner := &testCosigner{sigs: map[string][]byte{
+			keys[1].PubKey().String(): []byte("invalid"),
+			keys[2].PubKey().String(): cosignerSig,
+		}}
+		sigs, err := newSigner(&secpKeystore{keys: keys[:1]}, cosigner).Sign(signBytes)
+		require.NoError(t, err)
+		require.Len(t, sigs, 3)
+		assert.True(t, keys[0].PubKey().VerifySignature(signBytes, sigs[0]))
+		assert.Nil(t, sigs[1], "invalid signatures are ignored")
+		assert.Equal(t, cosignerSig, sigs[2])
+		assert.Equal(t, 1, cosigner.calls)
+	})
+
+	t.Run("keystore only", func(t *testing.T) {
+		cosigner := &testCosigner{}
+		sigs, err := newSigner(&secpKeystore{keys: keys[1:]}, cosigner).Sign(signBytes)
+		require.NoError(t, err)
+		assert.Nil(t, sigs[0])
+		assert.True(t, keys[1].PubKey().VerifySignature(signBytes, sigs[1]))
+		assert.True(t, keys[2].PubKey().VerifySignature(signBytes, sigs[2]))
+		assert.Zero(t, cosigner.calls, "threshold reached without the cosigner")
+	})
+
+	t.Run("no cosigner", func(t *testing.T) {
+		sigs, err := newSigner(&secpKeystore{keys: keys[:1]}, nil).Sign(signBytes)
+		require.NoError(t, err)
+		assert.NotNil(t, sigs[0])
+		assert.Nil(t, sigs[1])
+		assert.Nil(t, sigs[2])
+	})
+}
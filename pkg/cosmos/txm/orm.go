@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/goplugin/plugin-common/pkg/sqlutil"
 
@@ -12,6 +13,9 @@ import (
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/db"
 )
 
+// ErrDuplicateIdempotencyKey is returned by InsertMsg if a msg with the same idempotency key has not errored.
+var ErrDuplicateIdempotencyKey = errors.New("a msg with the same idempotency key has not errored")
+
 // ORM manages the data model for cosmos tx management.
 type ORM struct {
 	chainID string
@@ -33,35 +37,78 @@ func (o *ORM) Transaction(ctx context.Context, fn func(*ORM) error) (err error)
 // new returns a NewORM like o, but backed by q.
 func (o *ORM) new(q sqlutil.Queryer) *ORM { return NewORM(o.chainID, q) }
 
-// InsertMsg inserts a cosmos msg, assumed to be a serialized cosmos ExecuteContractMsg.
-func (o *ORM) InsertMsg(ctx context.Context, contractID, typeURL string, msg []byte) (int64, error) {
+// InsertMsg inserts a cosmos msg, assumed to be a serialized cosmos ExecuteContractMsg, with its options.
+// It returns ErrDuplicateIdempotencyKey instead of inserting a msg with the idempotency key of a msg which has not
+// errored, as enforced by a unique index, so that concurrent inserts of the same msg cannot both succeed.
+// The columns of the options are added by the db.Migrations.
+func (o *ORM) InsertMsg(ctx context.Context, contractID, typeURL string, msg []byte, opts adapters.EnqueueOptions) (int64, error) {
 	var tm adapters.Msg
 
-	err := o.ds.GetContext(ctx, &tm, `INSERT INTO cosmos_msgs (contract_id, type, raw, state, cosmos_chain_id, created_at, updated_at)
-	VALUES ($1, $2, $3, $4, $5, NOW(), NOW()) RETURNING *`, contractID, typeURL, msg, db.Unstarted, o.chainID)
+	var memo, idempotencyKey, coalesceKey *string
+	var deadline *time.Time
+	if opts.Memo != "" {
+		memo = &opts.Memo
+	}
+	if !opts.Deadline.IsZero() {
+		deadline = &opts.Deadline
+	}
+	if opts.IdempotencyKey != "" {
+		idempotencyKey = &opts.IdempotencyKey
+	}
+	if opts.CoalesceKey != "" {
+		coalesceKey = &opts.CoalesceKey
+	}
+	var senderPool *string
+	if len(opts.SenderPool) > 0 {
+		pool := adapters.JoinSenderPool(opts.SenderPool)
+		senderPool = &pool
+	}
+	err := o.ds.GetContext(ctx, &tm, `INSERT INTO cosmos_msgs (contract_id, type, raw, state, cosmos_chain_id, memo, deadline, priority, idempotency_key, coalesce_key, allow_large_send, sender_pool, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW(), NOW())
+	ON CONFLICT (cosmos_chain_id, idempotency_key) WHERE idempotency_key IS NOT NULL AND state <> 'errored' DO NOTHING
+	RETURNING *`, contractID, typeURL, msg, db.Unstarted, o.chainID, memo, deadline, opts.Priority, idempotencyKey, coalesceKey, opts.AllowLargeSend, senderPool)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrDuplicateIdempotencyKey
+	}
 	if err != nil {
 		return 0, err
 	}
 	return tm.ID, nil
 }
 
-// UpdateMsgsContract updates messages for the given contract.
-func (o *ORM) UpdateMsgsContract(ctx context.Context, contractID string, from, to db.State) error {
-	_, err := o.ds.ExecContext(ctx, `UPDATE cosmos_msgs SET state = $1, updated_at = NOW()
-	WHERE cosmos_chain_id = $2 AND contract_id = $3 AND state = $4`, to, o.chainID, contractID, from)
+// GetMsgByIdempotencyKey returns the msg with the given idempotency key which has not errored, or nil if there is none.
+func (o *ORM) GetMsgByIdempotencyKey(ctx context.Context, key string) (*adapters.Msg, error) {
+	var msg adapters.Msg
+	err := o.ds.GetContext(ctx, &msg, `SELECT * FROM cosmos_msgs WHERE cosmos_chain_id = $1 AND idempotency_key = $2 AND state != $3
+	ORDER BY id DESC LIMIT 1`, o.chainID, key, db.Errored)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// SupersedeMsgs marks the Unstarted messages for the given contract as Errored, superseded by the message with id by.
+// If coalesceKey is not nil, only messages with the same coalesce key are superseded.
+func (o *ORM) SupersedeMsgs(ctx context.Context, contractID string, coalesceKey *string, by int64) error {
+	_, err := o.ds.ExecContext(ctx, `UPDATE cosmos_msgs SET state = $1, superseded_by = $2, updated_at = NOW()
+	WHERE cosmos_chain_id = $3 AND contract_id = $4 AND state = $5 AND id != $2
+	AND ($6::text IS NULL OR coalesce_key = $6)`, db.Errored, by, o.chainID, contractID, db.Unstarted, coalesceKey)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-// GetMsgsState returns the oldest messages with a given state up to limit.
+// GetMsgsState returns the messages with a given state up to limit, with the highest priority first, then the oldest.
 func (o *ORM) GetMsgsState(ctx context.Context, state db.State, limit int64) (adapters.Msgs, error) {
 	if limit < 1 {
 		return adapters.Msgs{}, errors.New("limit must be greater than 0")
 	}
 	var msgs adapters.Msgs
-	if err := o.ds.SelectContext(ctx, &msgs, `SELECT * FROM cosmos_msgs WHERE state = $1 AND cosmos_chain_id = $2 ORDER BY id ASC LIMIT $3`, state, o.chainID, limit); err != nil {
+	if err := o.ds.SelectContext(ctx, &msgs, `SELECT * FROM cosmos_msgs WHERE state = $1 AND cosmos_chain_id = $2 ORDER BY priority DESC, id ASC LIMIT $3`, state, o.chainID, limit); err != nil {
 		return nil, err
 	}
 	return msgs, nil
@@ -76,6 +123,32 @@ func (o *ORM) GetMsgs(ctx context.Context, ids ...int64) (adapters.Msgs, error)
 	return msgs, nil
 }
 
+// UpdateMsgsGas sets the estimated gas of each msg in gasByID, by id.
+func (o *ORM) UpdateMsgsGas(ctx context.Context, gasByID map[int64]uint64) error {
+	if len(gasByID) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(gasByID))
+	gas := make([]int64, 0, len(gasByID))
+	for id, g := range gasByID {
+		ids = append(ids, id)
+		gas = append(gas, int64(g))
+	}
+	res, err := o.ds.ExecContext(ctx, `UPDATE cosmos_msgs SET estimated_gas = v.gas, updated_at = NOW()
+	FROM unnest($1::bigint[], $2::bigint[]) AS v(id, gas) WHERE cosmos_msgs.id = v.id`, ids, gas)
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if int(count) != len(ids) {
+		return fmt.Errorf("expected %d records updated, got %d", len(ids), count)
+	}
+	return nil
+}
+
 // UpdateMsgs updates msgs with the given ids.
 // Note state transitions are validated at the db level.
 func (o *ORM) UpdateMsgs(ctx context.Context, ids []int64, state db.State, txHash *string) error {
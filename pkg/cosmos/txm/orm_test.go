@@ -1,14 +1,22 @@
 package txm
 
 import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // postgres driver
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/goplugin/plugin-common/pkg/utils/tests"
 
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/adapters"
 	cosmosdb "github.com/goplugin/plugin-cosmos/pkg/cosmos/db"
 )
 
@@ -19,7 +27,7 @@ func TestORM(t *testing.T) {
 	o := NewORM(chainID, db)
 
 	// Create
-	mid, err := o.InsertMsg(ctx, "0x123", "", []byte("hello"))
+	mid, err := o.InsertMsg(ctx, "0x123", "", []byte("hello"), adapters.EnqueueOptions{})
 	require.NoError(t, err)
 	assert.NotEqual(t, 0, int(mid))
 
@@ -38,7 +46,7 @@ func TestORM(t *testing.T) {
 	unstarted, err = o.GetMsgsState(ctx, cosmosdb.Unstarted, -1)
 	assert.Error(t, err)
 	assert.Empty(t, unstarted)
-	mid2, err := o.InsertMsg(ctx, "0xabc", "", []byte("test"))
+	mid2, err := o.InsertMsg(ctx, "0xabc", "", []byte("test"), adapters.EnqueueOptions{})
 	require.NoError(t, err)
 	assert.NotEqual(t, 0, int(mid2))
 	unstarted, err = o.GetMsgsState(ctx, cosmosdb.Unstarted, 1)
@@ -71,10 +79,130 @@ func TestORM(t *testing.T) {
 	confirmed, err := o.GetMsgsState(ctx, cosmosdb.Confirmed, 5)
 	require.NoError(t, err)
 	require.Equal(t, 1, len(confirmed))
+	assert.Nil(t, confirmed[0].EstimatedGas)
+
+	// Gas
+	require.NoError(t, o.UpdateMsgsGas(ctx, map[int64]uint64{mid: 1000}))
+	msgs, err := o.GetMsgs(ctx, mid)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	require.NotNil(t, msgs[0].EstimatedGas)
+	assert.Equal(t, int64(1000), *msgs[0].EstimatedGas)
+
+	// Options
+	mid3, err := o.InsertMsg(ctx, "0xdef", "", []byte("options"), adapters.NewEnqueueOptions(
+		adapters.WithMemo("memo"), adapters.WithPriority(1), adapters.WithIdempotencyKey("key")))
+	require.NoError(t, err)
+	unstarted, err = o.GetMsgsState(ctx, cosmosdb.Unstarted, 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(unstarted))
+	assert.Equal(t, mid3, unstarted[0].ID, "highest priority first")
+	require.NotNil(t, unstarted[0].Memo)
+	assert.Equal(t, "memo", *unstarted[0].Memo)
+	assert.Nil(t, unstarted[0].Deadline)
+	byKey, err := o.GetMsgByIdempotencyKey(ctx, "key")
+	require.NoError(t, err)
+	require.NotNil(t, byKey)
+	assert.Equal(t, mid3, byKey.ID)
+	byKey, err = o.GetMsgByIdempotencyKey(ctx, "other")
+	require.NoError(t, err)
+	assert.Nil(t, byKey)
+
+	// Idempotency key
+	_, err = o.InsertMsg(ctx, "0xdef", "", []byte("again"), adapters.NewEnqueueOptions(adapters.WithIdempotencyKey("key")))
+	require.ErrorIs(t, err, ErrDuplicateIdempotencyKey)
+	require.NoError(t, o.UpdateMsgs(ctx, []int64{mid3}, cosmosdb.Errored, nil))
+	mid4, err := o.InsertMsg(ctx, "0xdef", "", []byte("again"), adapters.NewEnqueueOptions(adapters.WithIdempotencyKey("key")))
+	require.NoError(t, err, "errored msgs do not conflict")
+	byKey, err = o.GetMsgByIdempotencyKey(ctx, "key")
+	require.NoError(t, err)
+	require.NotNil(t, byKey)
+	assert.Equal(t, mid4, byKey.ID)
 }
 
+func TestORM_concurrentIdempotencyKey(t *testing.T) {
+	ctx := tests.Context(t)
+	o := NewORM(RandomChainID(), NewDB(t))
+	opts := adapters.NewEnqueueOptions(adapters.WithIdempotencyKey("key"))
+
+	inserted, commit := make(chan struct{}), make(chan struct{})
+	errs := make(chan error, 1)
+	go func() {
+		errs <- o.Transaction(ctx, func(orm *ORM) error {
+			if _, err := orm.InsertMsg(ctx, "0x123", "", []byte("first"), opts); err != nil {
+				return err
+			}
+			close(inserted)
+			<-commit
+			return nil
+		})
+	}()
+	<-inserted
+	second := make(chan error, 1)
+	go func() {
+		// waits for the first insert to commit
+		_, err := o.InsertMsg(ctx, "0x123", "", []byte("second"), opts)
+		second <- err
+	}()
+	close(commit)
+	require.NoError(t, <-errs)
+	require.ErrorIs(t, <-second, ErrDuplicateIdempotencyKey)
+}
+
+// coreSchema is the cosmos_msgs table as created by the core node, before the db.Migrations.
+const coreSchema = `CREATE TABLE cosmos_msgs (
+	id BIGSERIAL PRIMARY KEY,
+	cosmos_chain_id text NOT NULL,
+	contract_id text NOT NULL,
+	type text NOT NULL DEFAULT '/cosmwasm.wasm.v1.MsgExecuteContract',
+	raw bytea NOT NULL,
+	state text NOT NULL,
+	tx_hash text,
+	created_at timestamptz NOT NULL,
+	updated_at timestamptz NOT NULL,
+	CONSTRAINT cosmos_msgs_check CHECK (tx_hash IS NOT NULL OR (state <> 'broadcasted' AND state <> 'confirmed'))
+)`
+
+// NewDB returns a db with the cosmos_msgs table and the db.Migrations, in a schema of its own in the Postgres
+// at CL_DATABASE_URL. The test is skipped if it is not set.
 func NewDB(t *testing.T) *sqlx.DB {
-	t.Skip("DB unimplemented")
-	//TODO testcontainer?
-	return nil
+	dbURL := os.Getenv("CL_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("CL_DATABASE_URL is not set")
+	}
+	ctx := tests.Context(t)
+	admin, err := sqlx.Open("postgres", dbURL)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, admin.Close()) })
+	schema := fmt.Sprintf("cosmos_test_%d", time.Now().UnixNano())
+	_, err = admin.ExecContext(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		assert.NoError(t, err)
+	})
+
+	// lib/pq sends unknown parameters to the server, so that each connection uses the schema.
+	u, err := url.Parse(dbURL)
+	require.NoError(t, err)
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+	db, err := sqlx.Open("postgres", u.String())
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, db.Close()) })
+
+	_, err = db.ExecContext(ctx, coreSchema)
+	require.NoError(t, err)
+	migrations, err := fs.Glob(cosmosdb.Migrations, "migrations/*.sql")
+	require.NoError(t, err)
+	for _, name := range migrations {
+		b, err := cosmosdb.Migrations.ReadFile(name)
+		require.NoError(t, err)
+		up, _, ok := strings.Cut(string(b), "-- +goose Down")
+		require.True(t, ok, name)
+		_, err = db.ExecContext(ctx, up)
+		require.NoError(t, err, name)
+	}
+	return db
 }
@@ -0,0 +1,132 @@
+package txm
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	"github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/adapters"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/config"
+)
+
+// checkPolicy returns an error if msg, which must be decoded, violates the TxPolicy of sender.
+// Coins of denoms without a limit may not be sent at all. The msgs of a MsgExec are checked as if sent by sender.
+func checkPolicy(policy config.SenderPolicy, sender string, msg adapters.Msg) error {
+	switch m := msg.DecodedMsg.(type) {
+	case *authz.MsgExec:
+		for _, anyMsg := range m.Msgs {
+			inner, ok := anyMsg.GetCachedValue().(sdk.Msg)
+			if !ok {
+				return fmt.Errorf("undecoded msg %s in MsgExec of sender %s", anyMsg.TypeUrl, sender)
+			}
+			execMsg := msg
+			execMsg.DecodedMsg = inner
+			if err := checkPolicy(policy, sender, execMsg); err != nil {
+				return err
+			}
+		}
+	case *wasmtypes.MsgExecuteContract:
+		if !policy.AllowsContract(m.Contract) {
+			return fmt.Errorf("contract %s is not allowed for sender %s", m.Contract, sender)
+		}
+		if policy.MaxFunds != nil && !m.Funds.IsAllLTE(policy.MaxFunds) {
+			return fmt.Errorf("funds %s exceed MaxFunds %s of sender %s", m.Funds, policy.MaxFunds, sender)
+		}
+	case *types.MsgSend:
+		if !policy.AllowsRecipient(m.ToAddress) {
+			return fmt.Errorf("recipient %s is not allowed for sender %s", m.ToAddress, sender)
+		}
+		if policy.MaxSend != nil && !msg.AllowLargeSend && !m.Amount.IsAllLTE(policy.MaxSend) {
+			return fmt.Errorf("amount %s exceeds MaxSend %s of sender %s", m.Amount, policy.MaxSend, sender)
+		}
+	}
+	return nil
+}
+
+// senderPolicy returns the TxPolicy of sender, with its coin limits converted to base units, so that they may be
+// configured in any unit of the chain, e.g. 1atom for 1000000uatom.
+func (txm *Txm) senderPolicy(sender string) (config.SenderPolicy, bool) {
+	policy, ok := txm.cfg.SenderPolicy(sender)
+	if !ok {
+		return policy, false
+	}
+	policy.MaxFunds = txm.baseCoins(policy.MaxFunds)
+	policy.MaxSend = txm.baseCoins(policy.MaxSend)
+	policy.MaxFees = txm.baseCoins(policy.MaxFees)
+	return policy, true
+}
+
+// baseCoins returns coins in the base units of their denominations. Coins of unregistered denominations are
+// left as they are.
+func (txm *Txm) baseCoins(coins sdk.Coins) sdk.Coins {
+	if coins == nil {
+		return nil
+	}
+	var base sdk.Coins
+	for _, c := range coins {
+		if u, ok := txm.denoms.Unit(c.Denom); ok && u.Base != c.Denom {
+			converted, err := txm.denoms.ConvertToDenom(sdk.NewDecCoinFromCoin(c), u.Base)
+			if err != nil {
+				// Should be impossible, since the base unit is the smallest
+				txm.lggr.Errorw("Failed to convert tx policy limit to base units", "err", err, "coin", c)
+			} else {
+				c = converted
+			}
+		}
+		base = base.Add(c)
+	}
+	return base
+}
+
+type paidFee struct {
+	at  time.Time
+	fee sdk.Coin
+}
+
+// feeLedger records the fees paid by each sender since startup, to enforce the MaxFees of their policies.
+type feeLedger struct {
+	mu   sync.Mutex
+	fees map[string][]paidFee // by sender
+}
+
+func newFeeLedger() *feeLedger {
+	return &feeLedger{fees: map[string][]paidFee{}}
+}
+
+// check returns an error if paying fee at now would exceed the MaxFees of sender's policy.
+func (l *feeLedger) check(policy config.SenderPolicy, sender string, fee sdk.Coin, now time.Time) error {
+	if policy.MaxFees == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	cutoff := now.Add(-policy.FeeWindow)
+	paid := l.fees[sender]
+	for len(paid) > 0 && !paid[0].at.After(cutoff) {
+		paid = paid[1:]
+	}
+	l.fees[sender] = paid
+	total := sdk.NewCoins(fee)
+	for _, p := range paid {
+		total = total.Add(p.fee)
+	}
+	if !total.IsAllLTE(policy.MaxFees) {
+		return fmt.Errorf("fees %s in the last %s would exceed MaxFees %s of sender %s", total, policy.FeeWindow, policy.MaxFees, sender)
+	}
+	return nil
+}
+
+// record adds fee to the fees paid by sender, if its policy has MaxFees.
+func (l *feeLedger) record(policy config.SenderPolicy, sender string, fee sdk.Coin, now time.Time) {
+	if policy.MaxFees == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.fees[sender] = append(l.fees[sender], paidFee{at: now, fee: fee})
+}
@@ -0,0 +1,112 @@
+package txm
+
+import (
+	"testing"
+	"time"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	commonconfig "github.com/goplugin/plugin-common/pkg/config"
+	"github.com/goplugin/plugin-common/pkg/logger"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/adapters"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/config"
+	cosmosdb "github.com/goplugin/plugin-cosmos/pkg/cosmos/db"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/denom"
+)
+
+func TestCheckPolicy(t *testing.T) {
+	policy := config.SenderPolicy{
+		AllowedContracts:  []string{"contract"},
+		AllowedRecipients: []string{"recipient"},
+		MaxFunds:          sdk.NewCoins(sdk.NewInt64Coin("ucosm", 10)),
+		MaxSend:           sdk.NewCoins(sdk.NewInt64Coin("ucosm", 100)),
+	}
+	execute := func(contract string, funds sdk.Coins) adapters.Msg {
+		return adapters.Msg{DecodedMsg: &wasmtypes.MsgExecuteContract{Contract: contract, Funds: funds}}
+	}
+	send := func(to string, amount int64, allowLarge bool) adapters.Msg {
+		return adapters.Msg{
+			Msg:        cosmosdb.Msg{AllowLargeSend: allowLarge},
+			DecodedMsg: &banktypes.MsgSend{ToAddress: to, Amount: sdk.NewCoins(sdk.NewInt64Coin("ucosm", amount))},
+		}
+	}
+	exec := func(msg adapters.Msg) adapters.Msg {
+		m, err := wrapExec("grantee", msg.DecodedMsg)
+		require.NoError(t, err)
+		msg.DecodedMsg = m
+		return msg
+	}
+	for _, tt := range []struct {
+		name   string
+		msg    adapters.Msg
+		errStr string
+	}{
+		{name: "execute", msg: execute("contract", sdk.NewCoins(sdk.NewInt64Coin("ucosm", 10)))},
+		{name: "contract", msg: execute("other", nil), errStr: "contract other is not allowed for sender sender"},
+		{name: "funds", msg: execute("contract", sdk.NewCoins(sdk.NewInt64Coin("ucosm", 11))), errStr: "funds 11ucosm exceed MaxFunds 10ucosm of sender sender"},
+		{name: "funds denom", msg: execute("contract", sdk.NewCoins(sdk.NewInt64Coin("uatom", 1))), errStr: "funds 1uatom exceed MaxFunds 10ucosm of sender sender"},
+		{name: "send", msg: send("recipient", 100, false)},
+		{name: "recipient", msg: send("other", 1, false), errStr: "recipient other is not allowed for sender sender"},
+		{name: "large send", msg: send("recipient", 101, false), errStr: "amount 101ucosm exceeds MaxSend 100ucosm of sender sender"},
+		{name: "allowed large send", msg: send("recipient", 101, true)},
+		{name: "exec", msg: exec(execute("contract", nil))},
+		{name: "exec contract", msg: exec(execute("other", nil)), errStr: "contract other is not allowed for sender sender"},
+		{name: "exec large send", msg: exec(send("recipient", 101, false)), errStr: "amount 101ucosm exceeds MaxSend 100ucosm of sender sender"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPolicy(policy, "sender", tt.msg)
+			if tt.errStr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.errStr)
+		})
+	}
+}
+
+func TestFeeLedger(t *testing.T) {
+	policy := config.SenderPolicy{MaxFees: sdk.NewCoins(sdk.NewInt64Coin("ucosm", 100)), FeeWindow: time.Hour}
+	fee := sdk.NewInt64Coin("ucosm", 40)
+	l := newFeeLedger()
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		require.NoError(t, l.check(policy, "sender", fee, now))
+		l.record(policy, "sender", fee, now)
+	}
+	require.EqualError(t, l.check(policy, "sender", fee, now), "fees 120ucosm in the last 1h0m0s would exceed MaxFees 100ucosm of sender sender")
+	require.NoError(t, l.check(policy, "other", fee, now), "fees are per sender")
+	require.EqualError(t, l.check(policy, "sender", sdk.NewInt64Coin("uatom", 1), now), "fees 1uatom,80ucosm in the last 1h0m0s would exceed MaxFees 100ucosm of sender sender")
+	require.NoError(t, l.check(policy, "sender", fee, now.Add(time.Hour)), "fees outside of the window")
+
+	l.record(config.SenderPolicy{}, "unlimited", fee, now)
+	require.Empty(t, l.fees["unlimited"], "only recorded with MaxFees")
+}
+
+func TestTxm_senderPolicy(t *testing.T) {
+	lggr := logger.Test(t)
+	cfg := &config.TOMLConfig{Chain: config.Chain{GasToken: ptr("uatom")}, TxPolicies: config.TxPolicies{
+		{MaxFunds: ptr("2atom,5ufoo"), MaxFees: ptr("1atom,500uatom"), FeeWindow: commonconfig.MustNewDuration(time.Hour)},
+	}}
+	cfg.SetDefaults()
+	gpe := client.NewMustGasPriceEstimator(nil, lggr)
+	txm := NewTxm(nil, nil, *gpe, RandomChainID(), cfg, newKeystore(1), lggr)
+	denoms := denom.NewRegistry()
+	require.NoError(t, denoms.Register(banktypes.Metadata{
+		Base:       "uatom",
+		DenomUnits: []*banktypes.DenomUnit{{Denom: "uatom"}, {Denom: "atom", Exponent: 6}},
+		Display:    "atom",
+	}))
+	txm.SetDenoms(denoms)
+
+	policy, ok := txm.senderPolicy("sender")
+	require.True(t, ok)
+	assert.Equal(t, "2000000uatom,5ufoo", policy.MaxFunds.String())
+	assert.Equal(t, "1000500uatom", policy.MaxFees.String())
+	assert.Nil(t, policy.MaxSend)
+}
@@ -0,0 +1,95 @@
+package txm
+
+import (
+	"context"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
+)
+
+// poolTxGas is the gas which an account of a sender pool must be able to pay for to be picked. Msgs are picked
+// before they are simulated, so it estimates the gas of a small batch, such as an OCR2 transmission.
+const poolTxGas = 500_000
+
+// senderPicker picks the account of a sender pool which sends each msg of a batch. Accounts are picked in turn
+// for each contract, skipping those which cannot pay the fee of poolTxGas in any fee token, and preferring those
+// with fewer msgs in the batch.
+type senderPicker struct {
+	txm       *Txm
+	gasPrices []sdk.DecCoin
+	load      map[string]int  // msgs picked per account
+	funded    map[string]bool // by account, checked at most once per batch
+}
+
+func (txm *Txm) newSenderPicker(gasPrices []sdk.DecCoin) *senderPicker {
+	return &senderPicker{txm: txm, gasPrices: gasPrices, load: map[string]int{}, funded: map[string]bool{}}
+}
+
+// pick returns the account of pool which sends the next msg of contractID, or false if none are funded.
+func (p *senderPicker) pick(ctx context.Context, contractID string, pool []string) (string, bool) {
+	start := p.txm.poolCursors[contractID]
+	best := -1
+	for i := range pool {
+		j := (start + i) % len(pool)
+		if !p.isFunded(ctx, pool[j]) {
+			continue
+		}
+		if best == -1 || p.load[pool[j]] < p.load[pool[best]] {
+			best = j
+		}
+	}
+	if best == -1 {
+		return "", false
+	}
+	p.txm.poolCursors[contractID] = (best + 1) % len(pool)
+	p.load[pool[best]]++
+	return pool[best], true
+}
+
+func (p *senderPicker) isFunded(ctx context.Context, account string) bool {
+	if funded, ok := p.funded[account]; ok {
+		return funded
+	}
+	p.funded[account] = p.checkFunded(ctx, account)
+	return p.funded[account]
+}
+
+func (p *senderPicker) checkFunded(ctx context.Context, account string) bool {
+	addr, err := p.txm.addressCodec.StringToBytes(account)
+	if err != nil {
+		p.txm.lggr.Errorw("Invalid sender pool account", "err", err, "account", account)
+		return false
+	}
+	tc, err := p.txm.tc()
+	if err != nil {
+		p.txm.lggr.Warnw("Unable to get client to check sender pool balance", "err", err)
+		return false
+	}
+	// Like selectGasPrice, but before simulation.
+	for _, gasPrice := range p.gasPrices {
+		_, fee := client.GasFee(poolTxGas, p.txm.cfg.GasLimitMultiplier(), gasPrice)
+		balance, err := tc.Balance(ctx, addr, gasPrice.Denom)
+		if err != nil {
+			p.txm.lggr.Warnw("Unable to check sender pool balance", "err", err, "account", account, "denom", gasPrice.Denom)
+			continue
+		}
+		if balance.Amount.GTE(fee.Amount) {
+			return true
+		}
+		p.txm.lggr.Debugw("Sender pool balance does not cover the fee", "account", account,
+			"balance", p.txm.denoms.FormatCoin(*balance), "fee", p.txm.denoms.FormatCoin(fee))
+	}
+	return false
+}
+
+// wrapExec returns an authz MsgExec of msg, sent by grantee on behalf of the signer of msg.
+func wrapExec(grantee string, msg sdk.Msg) (*authz.MsgExec, error) {
+	anyMsg, err := codectypes.NewAnyWithValue(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &authz.MsgExec{Grantee: grantee, Msgs: []*codectypes.Any{anyMsg}}, nil
+}
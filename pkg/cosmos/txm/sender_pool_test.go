@@ -0,0 +1,80 @@
+package txm
+
+import (
+	"testing"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	cosmostypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goplugin/plugin-common/pkg/logger"
+	"github.com/goplugin/plugin-common/pkg/utils/tests"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client/mocks"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/config"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
+)
+
+func TestSenderPicker(t *testing.T) {
+	ctx := tests.Context(t)
+	lggr := logger.Test(t)
+	codec := params.NewAddressCodec("wasm")
+	var pool []string
+	for _, name := range []string{"pool-a", "pool-b", "pool-c"} {
+		pool = append(pool, codec.MustBytesToString(cosmostypes.AccAddress(name)))
+	}
+	unfunded := pool[2]
+
+	// the fee of poolTxGas, with the default GasLimitMultiplier
+	gasPrices := []cosmostypes.DecCoin{cosmostypes.NewDecCoinFromDec("ucosm", cosmostypes.MustNewDecFromStr("0.01"))}
+	fee := int64(7500)
+
+	tc := mocks.NewReaderWriter(t)
+	for _, account := range pool {
+		balance := cosmostypes.NewInt64Coin("ucosm", fee)
+		if account == unfunded {
+			balance = cosmostypes.NewInt64Coin("ucosm", fee-1)
+		}
+		tc.On("Balance", mock.Anything, mock.MatchedBy(func(addr cosmostypes.AccAddress) bool {
+			return codec.MustBytesToString(addr) == account
+		}), "ucosm").Return(&balance, nil).Once()
+	}
+	cfg := &config.TOMLConfig{Chain: config.Chain{GasToken: ptr("ucosm")}}
+	cfg.SetDefaults()
+	gpe := client.NewMustGasPriceEstimator(nil, lggr)
+	txm := NewTxm(nil, func() (client.ReaderWriter, error) { return tc, nil }, *gpe, RandomChainID(), cfg, newKeystore(1), lggr)
+
+	picker := txm.newSenderPicker(gasPrices)
+	var picked []string
+	for i := 0; i < 4; i++ {
+		account, ok := picker.pick(ctx, "contract", pool)
+		require.True(t, ok)
+		picked = append(picked, account)
+	}
+	assert.Equal(t, []string{pool[0], pool[1], pool[0], pool[1]}, picked, "in turn, skipping the unfunded account")
+
+	// the next batch continues in turn, and balances are checked again
+	for _, account := range pool[:2] {
+		balance := cosmostypes.NewInt64Coin("ucosm", 0)
+		tc.On("Balance", mock.Anything, mock.MatchedBy(func(addr cosmostypes.AccAddress) bool {
+			return codec.MustBytesToString(addr) == account
+		}), "ucosm").Return(&balance, nil).Once()
+	}
+	balance := cosmostypes.NewInt64Coin("ucosm", 0)
+	tc.On("Balance", mock.Anything, mock.Anything, "ucosm").Return(&balance, nil).Once()
+	_, ok := txm.newSenderPicker(gasPrices).pick(ctx, "contract", pool)
+	assert.False(t, ok, "no funded accounts")
+}
+
+func TestWrapExec(t *testing.T) {
+	msg := &wasmtypes.MsgExecuteContract{Sender: "sender", Contract: "contract", Msg: []byte(`{}`)}
+	exec, err := wrapExec("grantee", msg)
+	require.NoError(t, err)
+	assert.Equal(t, "grantee", exec.Grantee)
+	require.Len(t, exec.Msgs, 1)
+	assert.Equal(t, cosmostypes.MsgTypeURL(msg), exec.Msgs[0].TypeUrl)
+	assert.Equal(t, msg, exec.Msgs[0].GetCachedValue())
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
@@ -15,7 +16,6 @@ import (
 	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
 	"github.com/cometbft/cometbft/crypto/tmhash"
 	sdk "github.com/cosmos/cosmos-sdk/types"
-	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/cosmos/cosmos-sdk/x/bank/types"
 
 	"github.com/goplugin/plugin-common/pkg/logger"
@@ -28,6 +28,8 @@ import (
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/config"
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/db"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/denom"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
 )
 
 var (
@@ -43,27 +45,64 @@ type Txm struct {
 	lggr            logger.SugaredLogger
 	tc              func() (client.ReaderWriter, error)
 	keystoreAdapter *keystoreAdapter
+	addressCodec    params.AddressCodec
 	stop, done      chan struct{}
 	cfg             config.Config
 	gpe             client.ComposedGasPriceEstimator
+	fees            *feeLedger
+	// denoms formats amounts in logs, and converts the limits of TxPolicies to base units.
+	denoms *denom.Registry
+	// poolCursors are the index of the next account of the sender pool of each contract.
+	// Only used by sendMsgBatch.
+	poolCursors map[string]int
+	// cosigner is optional.
+	cosigner Cosigner
+	// unordered is only used by sendMsgBatch.
+	unordered *unorderedTxs
+	// confirming waits for the confirmation of unordered txs.
+	confirming  sync.WaitGroup
+	broadcaster *broadcaster
 }
 
-// NewTxm creates a txm. Uses simulation so should only be used to send txes to trusted contracts i.e. OCR.
+// NewTxm creates a txm. Uses simulation so should only be used to send txes to trusted contracts i.e. OCR,
+// which can be enforced with the TxPolicies of the config.
 func NewTxm(ds sqlutil.DataSource, tc func() (client.ReaderWriter, error), gpe client.ComposedGasPriceEstimator, chainID string, cfg config.Config, ks loop.Keystore, lggr logger.Logger) *Txm {
-	keystoreAdapter := newKeystoreAdapter(ks, cfg.Bech32Prefix())
+	// The prefix and key algorithm cannot be changed by a reload, so they are safe to keep. An unset prefix
+	// filled in at startup is set with SetAddressCodec.
+	addressCodec := params.NewAddressCodec(cfg.Bech32Prefix())
+	keystoreAdapter := newKeystoreAdapter(ks, addressCodec, cfg.KeyAlgorithm())
 	return &Txm{
 		newMsgs:         make(chan struct{}, 1), // buffered to hold one pending request while unblocking callers
 		orm:             NewORM(chainID, ds),
 		lggr:            logger.Sugared(lggr).Named("Txm"),
 		tc:              tc,
 		keystoreAdapter: keystoreAdapter,
+		addressCodec:    addressCodec,
 		stop:            make(chan struct{}),
 		done:            make(chan struct{}),
 		cfg:             cfg,
 		gpe:             gpe,
+		fees:            newFeeLedger(),
+		denoms:          denom.NewRegistry(),
+		poolCursors:     map[string]int{},
+		unordered:       newUnorderedTxs(),
+		broadcaster:     newBroadcaster(),
 	}
 }
 
+// SetDenoms sets the units of the denominations of the chain. Without them, amounts are logged in the units
+// they are in, and the limits of TxPolicies must be in base units. It must be called before Start.
+func (txm *Txm) SetDenoms(denoms *denom.Registry) {
+	txm.denoms = denoms
+}
+
+// SetAddressCodec sets the codec of the bech32 addresses of the chain, in place of the one for the Bech32Prefix
+// of the config passed to NewTxm. It must be called before Start.
+func (txm *Txm) SetAddressCodec(addressCodec params.AddressCodec) {
+	txm.addressCodec = addressCodec
+	txm.keystoreAdapter = newKeystoreAdapter(txm.keystoreAdapter.keystore, addressCodec, txm.keystoreAdapter.algorithm)
+}
+
 // Start subscribes to pg notifications about cosmos msg inserts and processes them.
 func (txm *Txm) Start(context.Context) error {
 	return txm.StartOnce("Txm", func() error {
@@ -113,6 +152,7 @@ func (txm *Txm) confirmAnyUnconfirmed(ctx context.Context) {
 
 func (txm *Txm) run() {
 	defer close(txm.done)
+	defer txm.confirming.Wait()
 	ctx, cancel := utils.ContextFromChan(txm.stop)
 	defer cancel()
 	txm.confirmAnyUnconfirmed(ctx)
@@ -157,12 +197,18 @@ func unmarshalMsg(msgType string, raw []byte) (sdk.Msg, string, error) {
 }
 
 type msgValidator struct {
-	cutoff         time.Time
+	now, cutoff    time.Time
 	expired, valid adapters.Msgs
 }
 
 func (e *msgValidator) add(msg adapters.Msg) {
-	if msg.CreatedAt.Before(e.cutoff) {
+	if msg.Deadline != nil {
+		if msg.Deadline.Before(e.now) {
+			e.expired = append(e.expired, msg)
+		} else {
+			e.valid = append(e.valid, msg)
+		}
+	} else if msg.CreatedAt.Before(e.cutoff) {
 		e.expired = append(e.expired, msg)
 	} else {
 		e.valid = append(e.valid, msg)
@@ -171,6 +217,9 @@ func (e *msgValidator) add(msg adapters.Msg) {
 
 func (e *msgValidator) sortValid() {
 	slices.SortFunc(e.valid, func(a, b adapters.Msg) int {
+		if a.Priority != b.Priority {
+			return cmp.Compare(b.Priority, a.Priority)
+		}
 		ac, bc := a.CreatedAt, b.CreatedAt
 		if ac.Equal(bc) {
 			return cmp.Compare(a.ID, b.ID)
@@ -183,7 +232,8 @@ func (e *msgValidator) sortValid() {
 }
 
 func (txm *Txm) sendMsgBatch(ctx context.Context) {
-	msgs := msgValidator{cutoff: time.Now().Add(-txm.cfg.TxMsgTimeout())}
+	now := time.Now()
+	msgs := msgValidator{now: now, cutoff: now.Add(-txm.cfg.TxMsgTimeout())}
 	err := txm.orm.Transaction(ctx, func(orm *ORM) error {
 		// There may be leftover Started messages after a crash or failed send attempt.
 		started, err := orm.GetMsgsState(ctx, db.Started, txm.cfg.MaxMsgsPerBatch())
@@ -229,7 +279,15 @@ func (txm *Txm) sendMsgBatch(ctx context.Context) {
 	}
 	msgs.sortValid()
 	txm.lggr.Debugw("building a batch", "not expired", msgs.valid, "marked expired", msgs.expired)
+	gasPrices, err := txm.gasPrices()
+	if err != nil {
+		// Should be impossible
+		txm.lggr.Criticalw("Failed to get gas price", "err", err)
+		return
+	}
 	var msgsByFrom = make(map[string]adapters.Msgs)
+	var rejected []int64
+	var picker *senderPicker
 	for _, m := range msgs.valid {
 		msg, sender, err2 := unmarshalMsg(m.Type, m.Raw)
 		if err2 != nil {
@@ -238,27 +296,63 @@ func (txm *Txm) sendMsgBatch(ctx context.Context) {
 			continue
 		}
 		m.DecodedMsg = msg
-		_, err2 = sdk.AccAddressFromBech32(sender)
+		_, err2 = txm.addressCodec.StringToBytes(sender)
 		if err2 != nil {
 			// Should never happen, we parse sender on Enqueue
 			txm.lggr.Criticalw("Unable to parse sender", "err", err2, "sender", sender)
 			continue
 		}
+		if policy, ok := txm.senderPolicy(sender); ok {
+			if err2 = checkPolicy(policy, sender, m); err2 != nil {
+				txm.lggr.Errorw("Msg violates tx policy, marking errored", "err", err2, "id", m.ID)
+				rejected = append(rejected, m.ID)
+				continue
+			}
+		}
+		if m.SenderPool != nil {
+			if picker == nil {
+				picker = txm.newSenderPicker(gasPrices)
+			}
+			if grantee, ok := picker.pick(ctx, m.ContractID, adapters.SplitSenderPool(*m.SenderPool)); ok {
+				exec, err2 := wrapExec(grantee, msg)
+				if err2 != nil {
+					// Should be impossible, since msg was unmarshalled
+					txm.lggr.Criticalw("Failed to wrap msg for sender pool, skipping", "err", err2, "id", m.ID)
+					continue
+				}
+				m.DecodedMsg, sender = exec, grantee
+				// The grantee signs the MsgExec, so its policy applies too.
+				if policy, ok := txm.senderPolicy(grantee); ok {
+					if err2 = checkPolicy(policy, grantee, m); err2 != nil {
+						txm.lggr.Errorw("Msg violates tx policy of sender pool account, marking errored", "err", err2, "id", m.ID)
+						rejected = append(rejected, m.ID)
+						continue
+					}
+				}
+			} else {
+				txm.lggr.Warnw("No funded account in sender pool, sending from the sender", "id", m.ID, "sender", sender)
+			}
+		}
+		// A tx has a single memo, so msgs with a different memo than the first from the sender are left Started
+		// for the next batch.
+		if prev, ok := msgsByFrom[sender]; ok && !equalMemos(prev[0].Memo, m.Memo) {
+			continue
+		}
 		msgsByFrom[sender] = append(msgsByFrom[sender], m)
 	}
+	if len(rejected) > 0 {
+		if err := txm.orm.UpdateMsgs(ctx, rejected, db.Errored, nil); err != nil {
+			// Assume transient db error, they will be checked again on next poll
+			txm.lggr.Errorw("unable to mark rejected msgs as errored", "err", err)
+		}
+	}
 
 	txm.lggr.Debugw("msgsByFrom", "msgsByFrom", msgsByFrom)
-	gasPrice, err := txm.GasPrice()
-	if err != nil {
-		// Should be impossible
-		txm.lggr.Criticalw("Failed to get gas price", "err", err)
-		return
-	}
 	for s, msgs := range msgsByFrom {
-		sender, _ := sdk.AccAddressFromBech32(s) // Already checked validity above
-		err := txm.sendMsgBatchFromAddress(ctx, gasPrice, sender, msgs)
+		sender, _ := txm.addressCodec.StringToBytes(s) // Already checked validity above
+		err := txm.sendMsgBatchFromAddress(ctx, gasPrices, sender, msgs)
 		if err != nil {
-			txm.lggr.Errorw("Could not send message batch", "err", err, "from", sender.String())
+			txm.lggr.Errorw("Could not send message batch", "err", err, "from", s)
 			continue
 		}
 		if ctx.Err() != nil {
@@ -267,69 +361,142 @@ func (txm *Txm) sendMsgBatch(ctx context.Context) {
 	}
 }
 
-func (txm *Txm) sendMsgBatchFromAddress(ctx context.Context, gasPrice sdk.DecCoin, sender sdk.AccAddress, msgs adapters.Msgs) error {
+func (txm *Txm) sendMsgBatchFromAddress(ctx context.Context, gasPrices []sdk.DecCoin, sender sdk.AccAddress, msgs adapters.Msgs) error {
+	from, err := txm.addressCodec.BytesToString(sender)
+	if err != nil {
+		return err
+	}
 	tc, err := txm.tc()
 	if err != nil {
 		txm.lggr.Criticalw("unable to get client", "err", err)
 		return err
 	}
-	an, sn, err := tc.Account(ctx, sender)
+	multisigPubKey, isMultisig := txm.cfg.MultisigPubKey(from)
+	unordered := txm.cfg.UnorderedTxs() && !isMultisig && txm.unordered.isSupported(ctx, tc, txm.lggr)
+	var an, sn uint64
+	var timeoutTimestamp time.Time
+	if unordered {
+		an, err = txm.unordered.accountNumber(ctx, tc, sender)
+		// Unordered txs time out when sequenced txs would, unless that is longer than the chain accepts,
+		// so that they are confirmed by the same polls.
+		timeoutTimestamp = unorderedTimeout(time.Now(), txm.cfg.BlocksUntilTxTimeout(), txm.cfg.BlockRate())
+	} else {
+		an, sn, err = tc.Account(ctx, sender)
+	}
 	if err != nil {
-		txm.lggr.Warnw("unable to read account", "err", err, "from", sender.String())
+		txm.lggr.Warnw("unable to read account", "err", err, "from", from)
 		// If we can't read the account, assume transient api issues and leave msgs unstarted
 		// to retry on next poll.
 		return err
 	}
 
-	txm.lggr.Debugw("simulating batch", "from", sender, "msgs", msgs, "seqnum", sn)
-	simResults, err := tc.BatchSimulateUnsigned(ctx, msgs.GetSimMsgs(), sn)
+	if unordered {
+		sent, unsent := txm.unordered.partitionSent(msgs, time.Now())
+		for txHash, ids := range sent {
+			// The msgs were broadcast, but could not be marked as broadcast. The chain may still execute their tx,
+			// so it is confirmed instead of sending them again in a new tx, with a new timeout and hash.
+			txm.lggr.Infow("unordered msgs were already broadcast, not broadcasting again", "from", from, "msgs", ids, "hash", txHash)
+			if err = txm.orm.UpdateMsgs(ctx, ids, db.Broadcasted, &txHash); err != nil {
+				txm.lggr.Errorw("unable to mark msgs as broadcasted", "err", err, "from", from, "hash", txHash)
+				return err
+			}
+			txm.confirmUnordered(ctx, tc, txHash, ids)
+		}
+		if len(unsent) == 0 {
+			return nil
+		}
+		msgs = unsent
+	}
+
+	var simResults *client.BatchSimResults
+	if unordered {
+		txm.lggr.Debugw("simulating unordered batch", "from", from, "msgs", msgs, "timeout", timeoutTimestamp)
+		simResults, err = tc.BatchSimulateUnordered(ctx, msgs.GetSimMsgs(), timeoutTimestamp)
+	} else {
+		txm.lggr.Debugw("simulating batch", "from", from, "msgs", msgs, "seqnum", sn)
+		simResults, err = tc.BatchSimulateUnsigned(ctx, msgs.GetSimMsgs(), sn)
+	}
 	if err != nil {
-		txm.lggr.Warnw("unable to simulate", "err", err, "from", sender.String())
+		txm.lggr.Warnw("unable to simulate", "err", err, "from", from)
 		// If we can't simulate assume transient api issue and retry on next poll.
 		// Note one rare scenario in which this can happen: the cosmos node misbehaves
 		// in that it confirms a txhash is present but still gives an old seq num.
 		// This is benign as the next retry will succeeds.
 		return err
 	}
-	txm.lggr.Debugw("simulation results", "from", sender, "succeeded", simResults.Succeeded, "failed", simResults.Failed)
+	gasByID := make(map[int64]uint64, len(simResults.Succeeded))
+	for _, msg := range simResults.Succeeded {
+		gasByID[msg.ID] = msg.GasUsed
+	}
+	txm.lggr.Debugw("simulation results", "from", from, "succeeded", simResults.Succeeded, "failed", simResults.Failed,
+		"gasUsed", simResults.GasUsed, "gasUsedByMsg", gasByID)
+	if err = txm.orm.UpdateMsgsGas(ctx, gasByID); err != nil {
+		// The estimates are informational, so the batch is still sent.
+		txm.lggr.Warnw("unable to record estimated gas of msgs", "err", err, "from", from)
+	}
 	err = txm.orm.UpdateMsgs(ctx, simResults.Failed.GetSimMsgsIDs(), db.Errored, nil)
 	if err != nil {
-		txm.lggr.Errorw("unable to mark failed sim txes as errored", "err", err, "from", sender.String())
+		txm.lggr.Errorw("unable to mark failed sim txes as errored", "err", err, "from", from)
 		// If we can't mark them as failed retry on next poll. Presumably same ones will fail.
 		return err
 	}
 
 	// Continue if there are no successful txes
 	if len(simResults.Succeeded) == 0 {
-		txm.lggr.Warnw("all sim msgs errored, not sending tx", "from", sender.String())
+		txm.lggr.Warnw("all sim msgs errored, not sending tx", "from", from)
 		return errors.New("all sim msgs errored")
 	}
-	// Get the gas limit for the successful batch
-	s, err := tc.SimulateUnsigned(ctx, simResults.Succeeded.GetMsgs(), sn)
+	// The gas limit of the successful batch, which was simulated together
+	gasLimit := simResults.GasUsed
+	gasPrice, err := txm.selectGasPrice(ctx, tc, sender, gasLimit, gasPrices)
 	if err != nil {
-		// In the OCR context this should only happen upon stale report
-		txm.lggr.Warnw("unexpected failure after successful simulation", "err", err)
+		txm.lggr.Warnw("unable to pay fee", "err", err, "from", from)
+		// The balance may be topped up, so retry on next poll.
 		return err
 	}
-	gasLimit := s.GasInfo.GasUsed
-
-	lb, err := tc.LatestBlock(ctx)
-	if err != nil {
-		txm.lggr.Warnw("unable to get latest block", "err", err, "from", sender.String())
-		// Assume transient api issue and retry.
+	_, fee := client.GasFee(gasLimit, txm.cfg.GasLimitMultiplier(), gasPrice)
+	policy, _ := txm.senderPolicy(from)
+	if err = txm.fees.check(policy, from, fee, time.Now()); err != nil {
+		txm.lggr.Errorw("fee violates tx policy", "err", err, "from", from, "fee", txm.denoms.FormatCoin(fee))
+		// Retry on next poll, when older fees may be outside of the window.
 		return err
 	}
-	header, timeout := lb.SdkBlock.Header.Height, txm.cfg.BlocksUntilTxTimeout()
-	if header < 0 {
-		return fmt.Errorf("invalid negative header height: %d", header)
-	} else if timeout < 0 {
-		return fmt.Errorf("invalid negative blocks until tx timeout: %d", timeout)
+
+	var timeoutHeight uint64
+	if !unordered {
+		lb, err := tc.LatestBlock(ctx)
+		if err != nil {
+			txm.lggr.Warnw("unable to get latest block", "err", err, "from", from)
+			// Assume transient api issue and retry.
+			return err
+		}
+		header, timeout := lb.SdkBlock.Header.Height, txm.cfg.BlocksUntilTxTimeout()
+		if header < 0 {
+			return fmt.Errorf("invalid negative header height: %d", header)
+		} else if timeout < 0 {
+			return fmt.Errorf("invalid negative blocks until tx timeout: %d", timeout)
+		}
+		timeoutHeight = uint64(header) + uint64(timeout)
+	}
+	var memo string
+	if msgs[0].Memo != nil {
+		memo = *msgs[0].Memo
+	}
+	var signedTx []byte
+	switch {
+	case isMultisig:
+		signer := &multisigSigner{ctx: ctx, txm: txm, address: from, pubKey: multisigPubKey}
+		signedTx, err = tc.CreateAndSignMultisig(simResults.Succeeded.GetMsgs(), an, sn, gasLimit, txm.cfg.GasLimitMultiplier(),
+			gasPrice, signer, timeoutHeight, memo)
+	case unordered:
+		signedTx, err = tc.CreateAndSignUnordered(simResults.Succeeded.GetMsgs(), an, gasLimit, txm.cfg.GasLimitMultiplier(),
+			gasPrice, NewKeyWrapper(txm.keystoreAdapter, from), timeoutTimestamp, memo)
+	default:
+		signedTx, err = tc.CreateAndSign(simResults.Succeeded.GetMsgs(), an, sn, gasLimit, txm.cfg.GasLimitMultiplier(),
+			gasPrice, NewKeyWrapper(txm.keystoreAdapter, from), timeoutHeight, memo)
 	}
-	timeoutHeight := uint64(header) + uint64(timeout)
-	signedTx, err := tc.CreateAndSign(simResults.Succeeded.GetMsgs(), an, sn, gasLimit, txm.cfg.GasLimitMultiplier(),
-		gasPrice, NewKeyWrapper(txm.keystoreAdapter, sender.String()), timeoutHeight)
 	if err != nil {
-		txm.lggr.Errorw("unable to sign tx", "err", err, "from", sender.String())
+		txm.lggr.Errorw("unable to sign tx", "err", err, "from", from)
 		return err
 	}
 
@@ -338,25 +505,30 @@ func (txm *Txm) sendMsgBatchFromAddress(ctx context.Context, gasPrice sdk.DecCoi
 	// We do this by first marking it broadcasted then rolling back if the broadcast api call fails.
 	// There is still a small chance of network failure or node/db crash after broadcasting but before committing the tx,
 	// in which case the msgs would be picked up again and re-broadcast, ensuring at-least once delivery.
-	var resp *txtypes.BroadcastTxResponse
+	txHash := strings.ToUpper(hex.EncodeToString(tmhash.Sum(signedTx)))
 	err = txm.orm.Transaction(ctx, func(orm *ORM) error {
-		txHash := strings.ToUpper(hex.EncodeToString(tmhash.Sum(signedTx)))
 		err = orm.UpdateMsgs(ctx, simResults.Succeeded.GetSimMsgsIDs(), db.Broadcasted, &txHash)
 		if err != nil {
 			return err
 		}
 
-		txm.lggr.Infow("broadcasting tx", "from", sender, "msgs", simResults.Succeeded, "gasLimit", gasLimit, "gasPrice", gasPrice.String(), "timeoutHeight", timeoutHeight, "hash", txHash)
-		resp, err = tc.Broadcast(ctx, signedTx, txtypes.BroadcastMode_BROADCAST_MODE_SYNC)
+		txm.lggr.Infow("broadcasting tx", "from", from, "msgs", simResults.Succeeded, "gasLimit", gasLimit,
+			"gasPrice", txm.denoms.Format(gasPrice), "fee", txm.denoms.FormatCoin(fee),
+			"timeoutHeight", timeoutHeight, "timeoutTimestamp", timeoutTimestamp, "hash", txHash)
+		resp, err := txm.broadcast(ctx, tc, signedTx)
 		if err != nil {
 			// Rollback marking as broadcasted
-			// Note can happen if the node's mempool is full, where we expect errCode 20.
+			// Note can happen if the mempool of the nodes is full, where we expect errCode 20.
 			return err
 		}
 		if resp.TxResponse == nil {
 			// Rollback marking as broadcasted
 			return errors.New("unexpected nil tx response")
 		}
+		if unordered {
+			// Recorded before committing, so that the msgs are not sent again if the commit fails.
+			txm.unordered.markSent(simResults.Succeeded.GetSimMsgsIDs(), txHash, timeoutTimestamp)
+		}
 		if resp.TxResponse.TxHash != txHash {
 			// Should never happen
 			txm.lggr.Criticalw("txhash mismatch", "got", resp.TxResponse.TxHash, "want", txHash)
@@ -364,20 +536,38 @@ func (txm *Txm) sendMsgBatchFromAddress(ctx context.Context, gasPrice sdk.DecCoi
 		return nil
 	})
 	if err != nil {
-		txm.lggr.Errorw("error broadcasting tx", "err", err, "from", sender.String())
+		txm.lggr.Errorw("error broadcasting tx", "err", err, "from", from)
 		// Was unable to broadcast, retry on next poll
 		return err
 	}
+	txm.fees.record(policy, from, fee, time.Now())
 
+	if unordered {
+		txm.confirmUnordered(ctx, tc, txHash, simResults.Succeeded.GetSimMsgsIDs())
+		return nil
+	}
 	maxPolls, pollPeriod := txm.confirmPollConfig()
-	if err := txm.confirmTx(ctx, tc, resp.TxResponse.TxHash, simResults.Succeeded.GetSimMsgsIDs(), maxPolls, pollPeriod); err != nil {
-		txm.lggr.Errorw("error confirming tx", "err", err, "hash", resp.TxResponse.TxHash)
+	if err := txm.confirmTx(ctx, tc, txHash, simResults.Succeeded.GetSimMsgsIDs(), maxPolls, pollPeriod); err != nil {
+		txm.lggr.Errorw("error confirming tx", "err", err, "hash", txHash)
 		return err
 	}
 
 	return nil
 }
 
+// confirmUnordered confirms an unordered tx in the background. Unordered txs do not change the sequence of later
+// txs, so the next batch need not wait for confirmation.
+func (txm *Txm) confirmUnordered(ctx context.Context, tc client.Reader, txHash string, broadcasted []int64) {
+	maxPolls, pollPeriod := txm.confirmPollConfig()
+	txm.confirming.Add(1)
+	go func() {
+		defer txm.confirming.Done()
+		if err := txm.confirmTx(ctx, tc, txHash, broadcasted, maxPolls, pollPeriod); err != nil {
+			txm.lggr.Errorw("error confirming tx", "err", err, "hash", txHash)
+		}
+	}()
+}
+
 func (txm *Txm) confirmPollConfig() (maxPolls int, pollPeriod time.Duration) {
 	blocks := txm.cfg.BlocksUntilTxTimeout()
 	blockPeriod := txm.cfg.BlockRate()
@@ -442,7 +632,16 @@ func (txm *Txm) confirmTx(ctx context.Context, tc client.Reader, txHash string,
 }
 
 // Enqueue enqueue a msg destined for the cosmos chain.
-func (txm *Txm) Enqueue(ctx context.Context, contractID string, msg sdk.Msg) (int64, error) {
+func (txm *Txm) Enqueue(ctx context.Context, contractID string, msg sdk.Msg, opts ...adapters.EnqueueOption) (int64, error) {
+	options := adapters.NewEnqueueOptions(opts...)
+	if err := options.Validate(); err != nil {
+		return 0, err
+	}
+	for _, s := range options.SenderPool {
+		if _, err := txm.addressCodec.StringToBytes(s); err != nil {
+			return 0, fmt.Errorf("invalid sender pool account %s: %w", s, err)
+		}
+	}
 	typeURL, raw, err := txm.marshalMsg(msg)
 	if err != nil {
 		return 0, err
@@ -455,13 +654,33 @@ func (txm *Txm) Enqueue(ctx context.Context, contractID string, msg sdk.Msg) (in
 
 	var id int64
 	err = txm.orm.Transaction(ctx, func(orm *ORM) (err error) {
-		// cancel any unstarted msgs (normally just one)
-		err = txm.orm.UpdateMsgsContract(ctx, contractID, db.Unstarted, db.Errored)
+		// The msg is inserted before looking for an existing one, since the unique index of the idempotency key
+		// makes a concurrent insert of the same msg wait for this one to commit, and then conflict with it.
+		id, err = orm.InsertMsg(ctx, contractID, typeURL, raw, options)
+		if errors.Is(err, ErrDuplicateIdempotencyKey) {
+			existing, err := orm.GetMsgByIdempotencyKey(ctx, options.IdempotencyKey)
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				// The existing msg errored since the insert, so it is no longer a duplicate.
+				return fmt.Errorf("msg with idempotency key %s errored while enqueueing, retry", options.IdempotencyKey)
+			}
+			txm.lggr.Debugw("msg already enqueued", "id", existing.ID, "idempotencyKey", options.IdempotencyKey)
+			id = existing.ID
+			return nil
+		}
 		if err != nil {
 			return err
 		}
-		id, err = txm.orm.InsertMsg(ctx, contractID, typeURL, raw)
-		return err
+		switch options.SupersedePolicy {
+		case adapters.SupersedePolicySupersede:
+			// cancel any unstarted msgs (normally just one)
+			return orm.SupersedeMsgs(ctx, contractID, nil, id)
+		case adapters.SupersedePolicyCoalesce:
+			return orm.SupersedeMsgs(ctx, contractID, &options.CoalesceKey, id)
+		}
+		return nil
 	})
 
 	txm.triggerNewMsg()
@@ -469,6 +688,13 @@ func (txm *Txm) Enqueue(ctx context.Context, contractID string, msg sdk.Msg) (in
 	return id, err
 }
 
+func equalMemos(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 func (txm *Txm) triggerNewMsg() {
 	select {
 	case <-txm.newMsgs:
@@ -489,14 +715,14 @@ func (e *ErrMsgUnsupported) Error() string {
 func (txm *Txm) marshalMsg(msg sdk.Msg) (string, []byte, error) {
 	switch ms := msg.(type) {
 	case *wasmtypes.MsgExecuteContract:
-		_, err := sdk.AccAddressFromBech32(ms.Sender)
+		_, err := txm.addressCodec.StringToBytes(ms.Sender)
 		if err != nil {
 			txm.lggr.Errorw("failed to parse sender, skipping", "err", err, "sender", ms.Sender)
 			return "", nil, err
 		}
 
 	case *types.MsgSend:
-		_, err := sdk.AccAddressFromBech32(ms.FromAddress)
+		_, err := txm.addressCodec.StringToBytes(ms.FromAddress)
 		if err != nil {
 			txm.lggr.Errorw("failed to parse sender, skipping", "err", err, "sender", ms.FromAddress)
 			return "", nil, err
@@ -519,14 +745,60 @@ func (txm *Txm) GetMsgs(ctx context.Context, ids ...int64) (adapters.Msgs, error
 	return txm.orm.GetMsgs(ctx, ids...)
 }
 
-// GasPrice returns the gas price from the estimator in the configured fee token.
+// selectGasPrice returns the first of gasPrices in which sender's balance covers the fee for gasLimit.
+// With a single fee token, the balance is left for the node to check.
+func (txm *Txm) selectGasPrice(ctx context.Context, tc client.Reader, sender sdk.AccAddress, gasLimit uint64, gasPrices []sdk.DecCoin) (sdk.DecCoin, error) {
+	if len(gasPrices) == 1 {
+		return gasPrices[0], nil
+	}
+	var fees []string
+	for _, gasPrice := range gasPrices {
+		_, fee := client.GasFee(gasLimit, txm.cfg.GasLimitMultiplier(), gasPrice)
+		balance, err := tc.Balance(ctx, sender, gasPrice.Denom)
+		if err != nil {
+			return sdk.DecCoin{}, fmt.Errorf("failed to get balance of %s: %w", gasPrice.Denom, err)
+		}
+		if balance.Amount.GTE(fee.Amount) {
+			return gasPrice, nil
+		}
+		fees = append(fees, fmt.Sprintf("%s (balance %s)", txm.denoms.FormatCoin(fee), txm.denoms.FormatCoin(*balance)))
+	}
+	return sdk.DecCoin{}, fmt.Errorf("balance does not cover the fee in any fee denom: %s", strings.Join(fees, ", "))
+}
+
+// GasPrice returns the gas price from the estimator in the GasToken,
+// bounded by the configured minimum and maximum gas prices.
 func (txm *Txm) GasPrice() (sdk.DecCoin, error) {
+	gasPrices, err := txm.gasPrices()
+	if err != nil {
+		return sdk.DecCoin{}, err
+	}
+	return gasPrices[0], nil
+}
+
+// gasPrices returns the gas prices from the estimator in each of the configured fee tokens, in order,
+// bounded by their minimum and maximum gas prices. Fee denoms without a gas price are skipped.
+func (txm *Txm) gasPrices() ([]sdk.DecCoin, error) {
 	prices := txm.gpe.GasPrices()
-	gasPrice, ok := prices[txm.cfg.GasToken()]
-	if !ok {
-		return sdk.DecCoin{}, errors.New("unexpected empty gas price")
+	var gasPrices []sdk.DecCoin
+	for i, t := range txm.cfg.FeeTokens() {
+		gasPrice, ok := prices[t.Denom]
+		if !ok {
+			if i == 0 {
+				return nil, errors.New("unexpected empty gas price")
+			}
+			txm.lggr.Warnw("Missing gas price for fee denom, skipping", "denom", t.Denom)
+			continue
+		}
+		if gasPrice.Amount.LT(t.MinGasPrice) {
+			gasPrice.Amount = t.MinGasPrice
+		}
+		if !t.MaxGasPrice.IsNil() && gasPrice.Amount.GT(t.MaxGasPrice) {
+			gasPrice.Amount = t.MaxGasPrice
+		}
+		gasPrices = append(gasPrices, gasPrice)
 	}
-	return gasPrice, nil
+	return gasPrices, nil
 }
 
 func (txm *Txm) Close() error {
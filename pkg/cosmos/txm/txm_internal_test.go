@@ -14,6 +14,7 @@ import (
 	cosmostypes "github.com/cosmos/cosmos-sdk/types"
 	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -22,10 +23,12 @@ import (
 	"github.com/goplugin/plugin-common/pkg/logger"
 	"github.com/goplugin/plugin-common/pkg/utils/tests"
 
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/adapters"
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client/mocks"
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/config"
 	cosmosdb "github.com/goplugin/plugin-cosmos/pkg/cosmos/db"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/params"
 )
 
 func generateExecuteMsg(msg []byte, from, to cosmostypes.AccAddress) cosmostypes.Msg {
@@ -43,7 +46,7 @@ func TestTxm(t *testing.T) {
 	db := NewDB(t)
 	ks := newKeystore(4)
 
-	adapter := newKeystoreAdapter(ks, "wasm")
+	adapter := newKeystoreAdapter(ks, params.NewAddressCodec("wasm"), config.KeyAlgorithmSecp256k1)
 	accounts, err := adapter.Accounts(ctx)
 	require.NoError(t, err)
 	require.Equal(t, len(accounts), 4)
@@ -85,19 +88,17 @@ func TestTxm(t *testing.T) {
 		require.NoError(t, err)
 		tc.On("Account", mock.Anything).Return(uint64(0), uint64(0), nil)
 		tc.On("BatchSimulateUnsigned", mock.Anything, mock.Anything).Return(&client.BatchSimResults{
-			Failed: nil,
+			Failed:  nil,
+			GasUsed: 1_000_000,
 			Succeeded: client.SimMsgs{{ID: id1, Msg: &wasmtypes.MsgExecuteContract{
 				Sender: sender1.String(),
 				Msg:    []byte(`1`),
 			}}},
 		}, nil)
-		tc.On("SimulateUnsigned", mock.Anything, mock.Anything).Return(&txtypes.SimulateResponse{GasInfo: &cosmostypes.GasInfo{
-			GasUsed: 1_000_000,
-		}}, nil)
 		tc.On("LatestBlock").Return(&tmservicetypes.GetLatestBlockResponse{SdkBlock: &tmservicetypes.Block{
 			Header: tmservicetypes.Header{Height: 1},
 		}}, nil)
-		tc.On("CreateAndSign", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]byte{0x01}, nil)
+		tc.On("CreateAndSign", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]byte{0x01}, nil)
 
 		txResp := &cosmostypes.TxResponse{TxHash: "4BF5122F344554C53BDE2EBB8CD2B7E3D1600AD631C385A5D7CCE23C7785459A"}
 		tc.On("Broadcast", mock.Anything, mock.Anything).Return(&txtypes.BroadcastTxResponse{TxResponse: txResp}, nil)
@@ -111,6 +112,50 @@ func TestTxm(t *testing.T) {
 		assert.Equal(t, completed[0].State, cosmosdb.Confirmed)
 	})
 
+	t.Run("unordered msg", func(t *testing.T) {
+		ctx := tests.Context(t)
+		tc := mocks.NewReaderWriter(t)
+		tcFn := func() (client.ReaderWriter, error) { return tc, nil }
+		loopKs := newKeystore(1)
+		unorderedCfg := *cfg
+		unorderedCfg.Chain.UnorderedTxs = ptr(true)
+		txm := NewTxm(db, tcFn, *gpe, chainID, &unorderedCfg, loopKs, lggr)
+
+		id1, err := txm.Enqueue(ctx, contract.String(), generateExecuteMsg([]byte(`1`), sender1, contract))
+		require.NoError(t, err)
+		tc.On("NodeInfo", mock.Anything).Return(&tmservicetypes.GetNodeInfoResponse{
+			ApplicationVersion: &tmservicetypes.VersionInfo{CosmosSdkVersion: "v0.53.0"},
+		}, nil).Once()
+		// only the account number is read, and no latest block
+		tc.On("Account", mock.Anything, sender1).Return(uint64(7), uint64(0), nil).Once()
+		tc.On("BatchSimulateUnordered", mock.Anything, mock.Anything, mock.Anything).Return(
+			func(_ context.Context, msgs client.SimMsgs, _ time.Time) (*client.BatchSimResults, error) {
+				return &client.BatchSimResults{Succeeded: msgs, GasUsed: 1_000_000}, nil
+			})
+		tc.On("CreateAndSignUnordered", mock.Anything, uint64(7), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]byte{0x01}, nil)
+		txResp := &cosmostypes.TxResponse{TxHash: "4BF5122F344554C53BDE2EBB8CD2B7E3D1600AD631C385A5D7CCE23C7785459A"}
+		tc.On("Tx", mock.Anything, mock.Anything).Return(&txtypes.GetTxResponse{Tx: &txtypes.Tx{}, TxResponse: txResp}, nil)
+
+		// the tx is broadcast, but marking its msgs as broadcasted fails to commit
+		sendCtx, cancel := context.WithCancel(ctx)
+		tc.On("Broadcast", mock.Anything, mock.Anything, mock.Anything).Run(func(mock.Arguments) { cancel() }).
+			Return(&txtypes.BroadcastTxResponse{TxResponse: txResp}, nil).Once()
+		txm.sendMsgBatch(sendCtx)
+		started, err := txm.orm.GetMsgs(ctx, id1)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(started))
+		assert.Equal(t, cosmosdb.Started, started[0].State)
+
+		// the msg is not sent again in a new tx, but confirmed with the broadcast one in the background
+		txm.sendMsgBatch(ctx)
+		txm.confirming.Wait()
+		completed, err := txm.orm.GetMsgs(ctx, id1)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(completed))
+		assert.Equal(t, cosmosdb.Confirmed, completed[0].State)
+		assert.Equal(t, txResp.TxHash, *completed[0].TxHash)
+	})
+
 	t.Run("two msgs different accounts", func(t *testing.T) {
 		ctx := tests.Context(t)
 		tc := mocks.NewReaderWriter(t)
@@ -136,7 +181,8 @@ func TestTxm(t *testing.T) {
 				},
 			},
 		}, mock.Anything).Return(&client.BatchSimResults{
-			Failed: nil,
+			Failed:  nil,
+			GasUsed: 1_000_000,
 			Succeeded: client.SimMsgs{
 				{
 					ID: id2,
@@ -148,13 +194,10 @@ func TestTxm(t *testing.T) {
 				},
 			},
 		}, nil).Once()
-		tc.On("SimulateUnsigned", mock.Anything, mock.Anything).Return(&txtypes.SimulateResponse{GasInfo: &cosmostypes.GasInfo{
-			GasUsed: 1_000_000,
-		}}, nil).Once()
 		tc.On("LatestBlock").Return(&tmservicetypes.GetLatestBlockResponse{SdkBlock: &tmservicetypes.Block{
 			Header: tmservicetypes.Header{Height: 1},
 		}}, nil).Once()
-		tc.On("CreateAndSign", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]byte{0x01}, nil).Once()
+		tc.On("CreateAndSign", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]byte{0x01}, nil).Once()
 		txResp := &cosmostypes.TxResponse{TxHash: "4BF5122F344554C53BDE2EBB8CD2B7E3D1600AD631C385A5D7CCE23C7785459A"}
 		tc.On("Broadcast", mock.Anything, mock.Anything).Return(&txtypes.BroadcastTxResponse{TxResponse: txResp}, nil).Once()
 		tc.On("Tx", mock.Anything).Return(&txtypes.GetTxResponse{Tx: &txtypes.Tx{}, TxResponse: txResp}, nil).Once()
@@ -165,6 +208,7 @@ func TestTxm(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, 2, len(completed))
 		assert.Equal(t, cosmosdb.Errored, completed[0].State) // cancelled
+		assert.Equal(t, &id2, completed[0].SupersededBy)
 		assert.Equal(t, cosmosdb.Confirmed, completed[1].State)
 	})
 
@@ -196,7 +240,8 @@ func TestTxm(t *testing.T) {
 					},
 				},
 			}, mock.Anything).Return(&client.BatchSimResults{
-				Failed: nil,
+				Failed:  nil,
+				GasUsed: 1_000_000,
 				Succeeded: client.SimMsgs{
 					{
 						ID: ids[i],
@@ -208,13 +253,10 @@ func TestTxm(t *testing.T) {
 					},
 				},
 			}, nil).Once()
-			tc.On("SimulateUnsigned", mock.Anything, mock.Anything).Return(&txtypes.SimulateResponse{GasInfo: &cosmostypes.GasInfo{
-				GasUsed: 1_000_000,
-			}}, nil).Once()
 			tc.On("LatestBlock").Return(&tmservicetypes.GetLatestBlockResponse{SdkBlock: &tmservicetypes.Block{
 				Header: tmservicetypes.Header{Height: 1},
 			}}, nil).Once()
-			tc.On("CreateAndSign", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]byte{0x01}, nil).Once()
+			tc.On("CreateAndSign", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]byte{0x01}, nil).Once()
 		}
 		txResp := &cosmostypes.TxResponse{TxHash: "4BF5122F344554C53BDE2EBB8CD2B7E3D1600AD631C385A5D7CCE23C7785459A"}
 		tc.On("Broadcast", mock.Anything, mock.Anything).Return(&txtypes.BroadcastTxResponse{TxResponse: txResp}, nil).Twice()
@@ -239,7 +281,7 @@ func TestTxm(t *testing.T) {
 		tcFn := func() (client.ReaderWriter, error) { return tc, nil }
 		loopKs := newKeystore(1)
 		txm := NewTxm(db, tcFn, *gpe, chainID, cfg, loopKs, lggr)
-		i, err := txm.orm.InsertMsg(ctx, "blah", "", []byte{0x01})
+		i, err := txm.orm.InsertMsg(ctx, "blah", "", []byte{0x01}, adapters.EnqueueOptions{})
 		require.NoError(t, err)
 		txh := "0x123"
 		require.NoError(t, txm.orm.UpdateMsgs(ctx, []int64{i}, cosmosdb.Started, &txh))
@@ -273,11 +315,11 @@ func TestTxm(t *testing.T) {
 		txm := NewTxm(db, tcFn, *gpe, chainID, cfg, loopKs, lggr)
 
 		// Insert and broadcast 3 msgs with different txhashes.
-		id1, err := txm.orm.InsertMsg(ctx, "blah", "", []byte{0x01})
+		id1, err := txm.orm.InsertMsg(ctx, "blah", "", []byte{0x01}, adapters.EnqueueOptions{})
 		require.NoError(t, err)
-		id2, err := txm.orm.InsertMsg(ctx, "blah", "", []byte{0x02})
+		id2, err := txm.orm.InsertMsg(ctx, "blah", "", []byte{0x02}, adapters.EnqueueOptions{})
 		require.NoError(t, err)
-		id3, err := txm.orm.InsertMsg(ctx, "blah", "", []byte{0x03})
+		id3, err := txm.orm.InsertMsg(ctx, "blah", "", []byte{0x03}, adapters.EnqueueOptions{})
 		require.NoError(t, err)
 		err = txm.orm.UpdateMsgs(ctx, []int64{id1}, cosmosdb.Started, &txHash1)
 		require.NoError(t, err)
@@ -318,7 +360,7 @@ func TestTxm(t *testing.T) {
 		txm := NewTxm(db, tcFn, *gpe, chainID, cfgShortExpiry, loopKs, lggr)
 
 		// Send a single one expired
-		id1, err := txm.orm.InsertMsg(ctx, "blah", "", []byte{0x03})
+		id1, err := txm.orm.InsertMsg(ctx, "blah", "", []byte{0x03}, adapters.EnqueueOptions{})
 		require.NoError(t, err)
 		time.Sleep(1 * time.Millisecond)
 		txm.sendMsgBatch(tests.Context(t))
@@ -328,9 +370,9 @@ func TestTxm(t *testing.T) {
 		assert.Equal(t, cosmosdb.Errored, m[0].State)
 
 		// Send a batch which is all expired
-		id2, err := txm.orm.InsertMsg(ctx, "blah", "", []byte{0x03})
+		id2, err := txm.orm.InsertMsg(ctx, "blah", "", []byte{0x03}, adapters.EnqueueOptions{})
 		require.NoError(t, err)
-		id3, err := txm.orm.InsertMsg(ctx, "blah", "", []byte{0x03})
+		id3, err := txm.orm.InsertMsg(ctx, "blah", "", []byte{0x03}, adapters.EnqueueOptions{})
 		require.NoError(t, err)
 		time.Sleep(1 * time.Millisecond)
 		txm.sendMsgBatch(tests.Context(t))
@@ -345,13 +387,10 @@ func TestTxm(t *testing.T) {
 		ctx := tests.Context(t)
 		tc := new(mocks.ReaderWriter)
 		tc.On("Account", mock.Anything).Return(uint64(0), uint64(0), nil)
-		tc.On("SimulateUnsigned", mock.Anything, mock.Anything).Return(&txtypes.SimulateResponse{GasInfo: &cosmostypes.GasInfo{
-			GasUsed: 1_000_000,
-		}}, nil)
 		tc.On("LatestBlock").Return(&tmservicetypes.GetLatestBlockResponse{SdkBlock: &tmservicetypes.Block{
 			Header: tmservicetypes.Header{Height: 1},
 		}}, nil)
-		tc.On("CreateAndSign", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]byte{0x01}, nil)
+		tc.On("CreateAndSign", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]byte{0x01}, nil)
 		txResp := &cosmostypes.TxResponse{TxHash: "4BF5122F344554C53BDE2EBB8CD2B7E3D1600AD631C385A5D7CCE23C7785459A"}
 		tc.On("Broadcast", mock.Anything, mock.Anything).Return(&txtypes.BroadcastTxResponse{TxResponse: txResp}, nil)
 		tc.On("Tx", mock.Anything).Return(&txtypes.GetTxResponse{Tx: &txtypes.Tx{}, TxResponse: txResp}, nil)
@@ -374,7 +413,7 @@ func TestTxm(t *testing.T) {
 			Contract: contract.String(),
 		}}}
 		tc.On("BatchSimulateUnsigned", msgs, mock.Anything).
-			Return(&client.BatchSimResults{Failed: nil, Succeeded: msgs}, nil).Once()
+			Return(&client.BatchSimResults{Succeeded: msgs, GasUsed: 1_000_000}, nil).Once()
 		time.Sleep(1 * time.Millisecond)
 		txm.sendMsgBatch(tests.Context(t))
 		m, err := txm.orm.GetMsgs(ctx, id1)
@@ -398,7 +437,7 @@ func TestTxm(t *testing.T) {
 			Contract: contract.String(),
 		}}}
 		tc.On("BatchSimulateUnsigned", msgs, mock.Anything).
-			Return(&client.BatchSimResults{Failed: nil, Succeeded: msgs}, nil).Once()
+			Return(&client.BatchSimResults{Succeeded: msgs, GasUsed: 1_000_000}, nil).Once()
 		time.Sleep(1 * time.Millisecond)
 		txm.sendMsgBatch(tests.Context(t))
 		require.NoError(t, err)
@@ -409,10 +448,124 @@ func TestTxm(t *testing.T) {
 	})
 }
 
+func TestTxm_GasPrice(t *testing.T) {
+	lggr := logger.Test(t)
+	gpe := client.NewMustGasPriceEstimator([]client.GasPricesEstimator{
+		client.NewFixedGasPriceEstimator(map[string]cosmostypes.DecCoin{
+			"ucosm": cosmostypes.NewDecCoinFromDec("ucosm", cosmostypes.MustNewDecFromStr("0.05")),
+		}, logger.Sugared(lggr)),
+	}, lggr)
+	for _, tt := range []struct {
+		name     string
+		min, max string
+		exp      string
+	}{
+		{name: "unbounded", exp: "0.05"},
+		{name: "within bounds", min: "0.01", max: "0.1", exp: "0.05"},
+		{name: "min", min: "0.06", exp: "0.06"},
+		{name: "max", max: "0.04", exp: "0.04"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.TOMLConfig{Chain: config.Chain{GasToken: ptr("ucosm")}}
+			if tt.min != "" {
+				cfg.Chain.MinGasPrice = ptr(decimal.RequireFromString(tt.min))
+			}
+			if tt.max != "" {
+				cfg.Chain.MaxGasPrice = ptr(decimal.RequireFromString(tt.max))
+			}
+			cfg.SetDefaults()
+			txm := NewTxm(nil, nil, *gpe, RandomChainID(), cfg, newKeystore(1), lggr)
+			gasPrice, err := txm.GasPrice()
+			require.NoError(t, err)
+			assert.Equal(t, cosmostypes.NewDecCoinFromDec("ucosm", cosmostypes.MustNewDecFromStr(tt.exp)), gasPrice)
+		})
+	}
+}
+
+func TestTxm_selectGasPrice(t *testing.T) {
+	lggr := logger.Test(t)
+	gpe := client.NewMustGasPriceEstimator([]client.GasPricesEstimator{
+		client.NewFixedGasPriceEstimator(map[string]cosmostypes.DecCoin{
+			"ucosm": cosmostypes.NewDecCoinFromDec("ucosm", cosmostypes.MustNewDecFromStr("0.05")),
+			"uatom": cosmostypes.NewDecCoinFromDec("uatom", cosmostypes.MustNewDecFromStr("0.01")),
+		}, logger.Sugared(lggr)),
+	}, lggr)
+	cfg := &config.TOMLConfig{
+		Chain: config.Chain{GasToken: ptr("ucosm"), GasLimitMultiplier: ptr(decimal.NewFromInt(1))},
+		FeeDenoms: config.FeeDenoms{
+			{Denom: ptr("uatom"), FallbackGasPrice: ptr(decimal.RequireFromString("0.01")), MinGasPrice: ptr(decimal.RequireFromString("0.02"))},
+			{Denom: ptr("uosmo"), FallbackGasPrice: ptr(decimal.RequireFromString("0.01"))},
+		},
+	}
+	cfg.SetDefaults()
+	txm := NewTxm(nil, nil, *gpe, RandomChainID(), cfg, newKeystore(1), lggr)
+	gasPrices, err := txm.gasPrices()
+	require.NoError(t, err)
+	require.Equal(t, []cosmostypes.DecCoin{
+		cosmostypes.NewDecCoinFromDec("ucosm", cosmostypes.MustNewDecFromStr("0.05")),
+		cosmostypes.NewDecCoinFromDec("uatom", cosmostypes.MustNewDecFromStr("0.02")),
+	}, gasPrices, "uosmo has no gas price")
+
+	sender := cosmostypes.AccAddress("sender")
+	for _, tt := range []struct {
+		name     string
+		balances map[string]int64
+		exp      string
+		errStr   string
+	}{
+		{name: "gas token", balances: map[string]int64{"ucosm": 50}, exp: "ucosm"},
+		{name: "fee denom", balances: map[string]int64{"ucosm": 49, "uatom": 20}, exp: "uatom"},
+		{name: "insufficient", balances: map[string]int64{"ucosm": 49, "uatom": 19},
+			errStr: "balance does not cover the fee in any fee denom: 50ucosm (balance 49ucosm), 20uatom (balance 19uatom)"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := mocks.NewReaderWriter(t)
+			for denom, amount := range tt.balances {
+				balance := cosmostypes.NewInt64Coin(denom, amount)
+				tc.On("Balance", mock.Anything, sender, denom).Return(&balance, nil)
+			}
+			gasPrice, err := txm.selectGasPrice(tests.Context(t), tc, sender, 1000, gasPrices)
+			if tt.errStr != "" {
+				require.EqualError(t, err, tt.errStr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, gasPrice.Denom)
+		})
+	}
+
+	t.Run("single fee token", func(t *testing.T) {
+		tc := mocks.NewReaderWriter(t)
+		gasPrice, err := txm.selectGasPrice(tests.Context(t), tc, sender, 1000, gasPrices[:1])
+		require.NoError(t, err)
+		assert.Equal(t, "ucosm", gasPrice.Denom)
+	})
+}
+
+func TestMsgValidator(t *testing.T) {
+	now := time.Now()
+	msg := func(id int64, created time.Time, deadline *time.Time, priority int32) adapters.Msg {
+		return adapters.Msg{Msg: cosmosdb.Msg{ID: id, CreatedAt: created, Deadline: deadline, Priority: priority}}
+	}
+	v := msgValidator{now: now, cutoff: now.Add(-time.Minute)}
+	v.add(msg(1, now.Add(-time.Hour), nil, 0))
+	v.add(msg(2, now.Add(-time.Hour), ptr(now.Add(time.Minute)), 0))
+	v.add(msg(3, now, ptr(now.Add(-time.Second)), 0))
+	v.add(msg(4, now, nil, 0))
+	v.add(msg(5, now, nil, 1))
+	assert.Equal(t, []int64{1, 3}, v.expired.GetIDs(), "expired by the TxMsgTimeout or their deadline")
+	v.sortValid()
+	assert.Equal(t, []int64{5, 2, 4}, v.valid.GetIDs(), "highest priority, then oldest first")
+}
+
+func ptr[T any](t T) *T {
+	return &t
+}
+
 func mustInsertMsg(t *testing.T, txm *Txm, contractID string, msg cosmostypes.Msg) int64 {
 	typeURL, raw, err := txm.marshalMsg(msg)
 	require.NoError(t, err)
-	id, err := txm.orm.InsertMsg(tests.Context(t), contractID, typeURL, raw)
+	id, err := txm.orm.InsertMsg(tests.Context(t), contractID, typeURL, raw, adapters.EnqueueOptions{})
 	require.NoError(t, err)
 	return id
 }
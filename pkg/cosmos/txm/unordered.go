@@ -0,0 +1,99 @@
+package txm
+
+import (
+	"context"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/goplugin/plugin-common/pkg/logger"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/adapters"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
+)
+
+// maxUnorderedTimeout is the longest timeout of an unordered tx accepted by cosmos-sdk chains by default.
+const maxUnorderedTimeout = 10 * time.Minute
+
+// unorderedTimeout returns the timeout timestamp of an unordered tx sent at now, after blocksUntilTimeout blocks,
+// capped at maxUnorderedTimeout and truncated to a second.
+func unorderedTimeout(now time.Time, blocksUntilTimeout int64, blockRate time.Duration) time.Time {
+	return now.Add(min(time.Duration(blocksUntilTimeout)*blockRate, maxUnorderedTimeout)).Truncate(time.Second)
+}
+
+// unorderedTxs tracks the unordered txs of the Txm, which are sent without reading the account sequence,
+// and confirmed without blocking the next batch. Only used by sendMsgBatch.
+type unorderedTxs struct {
+	// supported is whether the chain accepts unordered txs, once detected.
+	supported *bool
+	// accountNumbers of each sender, which unlike their sequence never change.
+	accountNumbers map[string]uint64
+	// sent are the broadcast txs of msgs by ID, until they time out. They are recorded once broadcast, even if the
+	// msgs could not be marked as broadcast, since the chain may still execute them.
+	sent map[int64]sentTx
+}
+
+// sentTx is a broadcast unordered tx, which may be executed until its timeout.
+type sentTx struct {
+	hash    string
+	timeout time.Time
+}
+
+func newUnorderedTxs() *unorderedTxs {
+	return &unorderedTxs{accountNumbers: map[string]uint64{}, sent: map[int64]sentTx{}}
+}
+
+// isSupported returns whether the chain accepts unordered txs. Detection is retried on the next batch if it fails.
+func (u *unorderedTxs) isSupported(ctx context.Context, tc client.Reader, lggr logger.SugaredLogger) bool {
+	if u.supported == nil {
+		supported, err := client.SupportsUnorderedTxs(ctx, tc)
+		if err != nil {
+			lggr.Warnw("unable to detect support for unordered txs, sending sequenced txs", "err", err)
+			return false
+		}
+		if !supported {
+			lggr.Warn("chain does not support unordered txs, sending sequenced txs")
+		}
+		u.supported = &supported
+	}
+	return *u.supported
+}
+
+// accountNumber returns the account number of sender, reading it once.
+func (u *unorderedTxs) accountNumber(ctx context.Context, tc client.Reader, sender sdk.AccAddress) (uint64, error) {
+	if an, ok := u.accountNumbers[sender.String()]; ok {
+		return an, nil
+	}
+	an, _, err := tc.Account(ctx, sender)
+	if err != nil {
+		return 0, err
+	}
+	u.accountNumbers[sender.String()] = an
+	return an, nil
+}
+
+// partitionSent splits msgs into the IDs of those which were broadcast in txs which have not timed out yet,
+// by tx hash, and the unsent msgs.
+func (u *unorderedTxs) partitionSent(msgs adapters.Msgs, now time.Time) (sent map[string][]int64, unsent adapters.Msgs) {
+	for id, tx := range u.sent {
+		if !now.Before(tx.timeout) {
+			delete(u.sent, id)
+		}
+	}
+	sent = map[string][]int64{}
+	for _, msg := range msgs {
+		if tx, ok := u.sent[msg.ID]; ok {
+			sent[tx.hash] = append(sent[tx.hash], msg.ID)
+		} else {
+			unsent = append(unsent, msg)
+		}
+	}
+	return
+}
+
+// markSent records that the msgs with ids were broadcast in the tx with txHash, which times out at timeout.
+func (u *unorderedTxs) markSent(ids []int64, txHash string, timeout time.Time) {
+	for _, id := range ids {
+		u.sent[id] = sentTx{hash: txHash, timeout: timeout}
+	}
+}
@@ -0,0 +1,89 @@
+package txm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tmservicetypes "github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goplugin/plugin-common/pkg/logger"
+	"github.com/goplugin/plugin-common/pkg/utils/tests"
+
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/adapters"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client/mocks"
+	cosmosdb "github.com/goplugin/plugin-cosmos/pkg/cosmos/db"
+)
+
+func TestUnorderedTxs(t *testing.T) {
+	ctx := tests.Context(t)
+	lggr := logger.Sugared(logger.Test(t))
+
+	t.Run("supported", func(t *testing.T) {
+		tc := mocks.NewReaderWriter(t)
+		u := newUnorderedTxs()
+		tc.On("NodeInfo", mock.Anything).Return(nil, errors.New("unavailable")).Once()
+		assert.False(t, u.isSupported(ctx, tc, lggr))
+		// detected once
+		tc.On("NodeInfo", mock.Anything).Return(&tmservicetypes.GetNodeInfoResponse{
+			ApplicationVersion: &tmservicetypes.VersionInfo{CosmosSdkVersion: "v0.53.2"},
+		}, nil).Once()
+		assert.True(t, u.isSupported(ctx, tc, lggr))
+		assert.True(t, u.isSupported(ctx, tc, lggr))
+
+		u = newUnorderedTxs()
+		tc.On("NodeInfo", mock.Anything).Return(&tmservicetypes.GetNodeInfoResponse{
+			ApplicationVersion: &tmservicetypes.VersionInfo{CosmosSdkVersion: "v0.50.9"},
+		}, nil).Once()
+		assert.False(t, u.isSupported(ctx, tc, lggr))
+		assert.False(t, u.isSupported(ctx, tc, lggr))
+	})
+
+	t.Run("account number", func(t *testing.T) {
+		tc := mocks.NewReaderWriter(t)
+		u := newUnorderedTxs()
+		sender := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+		tc.On("Account", mock.Anything, sender).Return(uint64(0), uint64(0), errors.New("unavailable")).Once()
+		_, err := u.accountNumber(ctx, tc, sender)
+		require.Error(t, err)
+		tc.On("Account", mock.Anything, sender).Return(uint64(7), uint64(42), nil).Once()
+		for range 2 {
+			an, err := u.accountNumber(ctx, tc, sender)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(7), an)
+		}
+	})
+
+	t.Run("sent", func(t *testing.T) {
+		u := newUnorderedTxs()
+		now := time.Now()
+		u.markSent([]int64{1, 2}, "A", now.Add(time.Minute))
+		u.markSent([]int64{3}, "B", now.Add(time.Second))
+		msgs := func(ids ...int64) (msgs adapters.Msgs) {
+			for _, id := range ids {
+				msgs = append(msgs, adapters.Msg{Msg: cosmosdb.Msg{ID: id}})
+			}
+			return
+		}
+		sent, unsent := u.partitionSent(msgs(1, 2, 3, 4), now)
+		assert.Equal(t, map[string][]int64{"A": {1, 2}, "B": {3}}, sent)
+		assert.Equal(t, msgs(4), unsent)
+
+		later := now.Add(time.Second)
+		sent, unsent = u.partitionSent(msgs(2, 3), later)
+		assert.Equal(t, map[string][]int64{"A": {2}}, sent)
+		assert.Equal(t, msgs(3), unsent, "timed out")
+		assert.NotContains(t, u.sent, int64(3))
+	})
+}
+
+func TestUnorderedTimeout(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 500_000_000, time.UTC)
+	assert.Equal(t, now.Add(3*time.Minute).Truncate(time.Second), unorderedTimeout(now, 30, 6*time.Second))
+	assert.Equal(t, now.Add(maxUnorderedTimeout).Truncate(time.Second), unorderedTimeout(now, 300, 6*time.Second), "capped")
+}
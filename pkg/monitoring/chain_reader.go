@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	tmtypes "github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/query"
 	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
@@ -20,6 +21,8 @@ import (
 type ChainReader interface {
 	TxsEvents(ctx context.Context, events []string, paginationParams *query.PageRequest) (*txtypes.GetTxsEventResponse, error)
 	ContractState(ctx context.Context, contractAddress sdk.AccAddress, queryMsg []byte) ([]byte, error)
+	ContractStateAtHeight(ctx context.Context, contractAddress sdk.AccAddress, queryMsg []byte, height int64) ([]byte, error)
+	LatestBlock(ctx context.Context) (*tmtypes.GetLatestBlockResponse, error)
 }
 
 // NewChainReader produces a ChainReader that issues requests to the Cosmos RPC
@@ -77,3 +80,35 @@ func (c *chainReader) ContractState(ctx context.Context, contractAddress sdk.Acc
 	_ = c.rateLimiter.Take()
 	return client.ContractState(ctx, contractAddress, queryMsg)
 }
+
+func (c *chainReader) ContractStateAtHeight(ctx context.Context, contractAddress sdk.AccAddress, queryMsg []byte, height int64) ([]byte, error) {
+	c.globalSequencer.Lock()
+	defer c.globalSequencer.Unlock()
+	client, err := pkgClient.NewClient(
+		c.cosmosConfig.ChainID,
+		c.cosmosConfig.TendermintURL,
+		c.cosmosConfig.ReadTimeout,
+		c.coreLog,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a cosmos client: %w", err)
+	}
+	_ = c.rateLimiter.Take()
+	return client.ContractStateAtHeight(ctx, contractAddress, queryMsg, height)
+}
+
+func (c *chainReader) LatestBlock(ctx context.Context) (*tmtypes.GetLatestBlockResponse, error) {
+	c.globalSequencer.Lock()
+	defer c.globalSequencer.Unlock()
+	client, err := pkgClient.NewClient(
+		c.cosmosConfig.ChainID,
+		c.cosmosConfig.TendermintURL,
+		c.cosmosConfig.ReadTimeout,
+		c.coreLog,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a cosmos client: %w", err)
+	}
+	_ = c.rateLimiter.Take()
+	return client.LatestBlock(ctx)
+}
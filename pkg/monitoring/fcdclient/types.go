@@ -16,6 +16,8 @@ type Tx struct {
 	Code   int    `json:"code"` // Error code if present
 	Logs   []Log  `json:"logs"`
 	RawLog string `json:"raw_log"`
+	// Events is only set by chains on cosmos-sdk v0.50 or later, which leave Logs empty.
+	Events []Event `json:"events"`
 }
 
 type Log struct {
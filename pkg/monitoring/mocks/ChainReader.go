@@ -9,6 +9,8 @@ import (
 
 	query "github.com/cosmos/cosmos-sdk/types/query"
 
+	tmservice "github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+
 	tx "github.com/cosmos/cosmos-sdk/types/tx"
 
 	types "github.com/cosmos/cosmos-sdk/types"
@@ -49,6 +51,66 @@ func (_m *ChainReader) ContractState(ctx context.Context, contractAddress types.
 	return r0, r1
 }
 
+// ContractStateAtHeight provides a mock function with given fields: ctx, contractAddress, queryMsg, height
+func (_m *ChainReader) ContractStateAtHeight(ctx context.Context, contractAddress types.AccAddress, queryMsg []byte, height int64) ([]byte, error) {
+	ret := _m.Called(ctx, contractAddress, queryMsg, height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContractStateAtHeight")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.AccAddress, []byte, int64) ([]byte, error)); ok {
+		return rf(ctx, contractAddress, queryMsg, height)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.AccAddress, []byte, int64) []byte); ok {
+		r0 = rf(ctx, contractAddress, queryMsg, height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.AccAddress, []byte, int64) error); ok {
+		r1 = rf(ctx, contractAddress, queryMsg, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LatestBlock provides a mock function with given fields: ctx
+func (_m *ChainReader) LatestBlock(ctx context.Context) (*tmservice.GetLatestBlockResponse, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LatestBlock")
+	}
+
+	var r0 *tmservice.GetLatestBlockResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*tmservice.GetLatestBlockResponse, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *tmservice.GetLatestBlockResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*tmservice.GetLatestBlockResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // TxsEvents provides a mock function with given fields: ctx, events, paginationParams
 func (_m *ChainReader) TxsEvents(ctx context.Context, events []string, paginationParams *query.PageRequest) (*tx.GetTxsEventResponse, error) {
 	ret := _m.Called(ctx, events, paginationParams)
@@ -9,15 +9,18 @@ import (
 	"math/big"
 	"sort"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
+	abci "github.com/cometbft/cometbft/abci/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
 	"github.com/goplugin/plugin-libocr/offchainreporting2/types"
 
 	relayMonitoring "github.com/goplugin/plugin-common/pkg/monitoring"
 
 	"github.com/goplugin/plugin-cosmos/pkg/cosmos/adapters/cosmwasm"
+	"github.com/goplugin/plugin-cosmos/pkg/cosmos/client"
 	"github.com/goplugin/plugin-cosmos/pkg/monitoring/fcdclient"
 )
 
@@ -79,6 +82,12 @@ type envelopeSource struct {
 }
 
 func (e *envelopeSource) Fetch(ctx context.Context) (interface{}, error) {
+	// Pin all contract state queries to the same block, so that the envelope
+	// is a consistent snapshot even though the queries run concurrently.
+	height, err := e.fetchLatestHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest block height: %w", err)
+	}
 	envelope := relayMonitoring.Envelope{}
 	var envelopeErr error
 	envelopeMu := &sync.Mutex{}
@@ -106,7 +115,7 @@ func (e *envelopeSource) Fetch(ctx context.Context) (interface{}, error) {
 	}()
 	go func() {
 		defer wg.Done()
-		contractConfig, err := e.fetchLatestConfig(ctx)
+		contractConfig, err := e.fetchLatestConfig(ctx, height)
 		envelopeMu.Lock()
 		defer envelopeMu.Unlock()
 		if err != nil {
@@ -117,7 +126,7 @@ func (e *envelopeSource) Fetch(ctx context.Context) (interface{}, error) {
 	}()
 	go func() {
 		defer wg.Done()
-		balance, err := e.fetchLinkBalance(ctx)
+		balance, err := e.fetchLinkBalance(ctx, height)
 		envelopeMu.Lock()
 		defer envelopeMu.Unlock()
 		if err != nil {
@@ -128,7 +137,7 @@ func (e *envelopeSource) Fetch(ctx context.Context) (interface{}, error) {
 	}()
 	go func() {
 		defer wg.Done()
-		amount, err := e.fetchLinkAvailableForPayment(ctx)
+		amount, err := e.fetchLinkAvailableForPayment(ctx, height)
 		envelopeMu.Lock()
 		defer envelopeMu.Unlock()
 		if err != nil {
@@ -142,6 +151,17 @@ func (e *envelopeSource) Fetch(ctx context.Context) (interface{}, error) {
 	return envelope, envelopeErr
 }
 
+func (e *envelopeSource) fetchLatestHeight(ctx context.Context) (int64, error) {
+	block, err := e.rpcClient.LatestBlock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if block.SdkBlock == nil {
+		return 0, errors.New("latest block response is missing the block")
+	}
+	return block.SdkBlock.Header.Height, nil
+}
+
 type transmissionData struct {
 	configDigest      types.ConfigDigest
 	epoch             uint32
@@ -163,7 +183,7 @@ func (e *envelopeSource) fetchLatestTransmission(ctx context.Context) (transmiss
 		return transmissionData{}, fmt.Errorf("failed to fetch latest 'new_transmission' event: %w", err)
 	}
 	data := transmissionData{}
-	err = e.extractDataFromTxResponse("wasm-new_transmission", e.cosmosFeedConfig.ContractAddressBech32, res, map[string]func(string) error{
+	err = e.extractDataFromTxResponse("new_transmission", e.cosmosFeedConfig.ContractAddressBech32, res, map[string]func(string) error{
 		"config_digest": func(value string) error {
 			return cosmwasm.HexToConfigDigest(value, &data.configDigest)
 		},
@@ -218,16 +238,12 @@ func (e *envelopeSource) fetchLatestTransmission(ctx context.Context) (transmiss
 	return data, nil
 }
 
-func (e *envelopeSource) fetchLatestConfig(ctx context.Context) (types.ContractConfig, error) {
-	var cachedConfig types.ContractConfig
-	var cachedConfigBlock uint64
-	go func() {
-		e.cachedConfigMu.Lock()
-		defer e.cachedConfigMu.Unlock()
-		cachedConfig = e.cachedConfig
-		cachedConfigBlock = e.cachedConfigBlock
-	}()
-	latestConfigBlock, err := e.fetchLatestConfigBlock(ctx)
+func (e *envelopeSource) fetchLatestConfig(ctx context.Context, height int64) (types.ContractConfig, error) {
+	e.cachedConfigMu.Lock()
+	cachedConfig := e.cachedConfig
+	cachedConfigBlock := e.cachedConfigBlock
+	e.cachedConfigMu.Unlock()
+	latestConfigBlock, err := e.fetchLatestConfigBlock(ctx, height)
 	if err != nil {
 		return types.ContractConfig{}, err
 	}
@@ -246,11 +262,12 @@ func (e *envelopeSource) fetchLatestConfig(ctx context.Context) (types.ContractC
 	return latestConfig, nil
 }
 
-func (e *envelopeSource) fetchLatestConfigBlock(ctx context.Context) (uint64, error) {
-	resp, err := e.rpcClient.ContractState(
+func (e *envelopeSource) fetchLatestConfigBlock(ctx context.Context, height int64) (uint64, error) {
+	resp, err := e.rpcClient.ContractStateAtHeight(
 		ctx,
 		e.cosmosFeedConfig.ContractAddress,
 		[]byte(`{"latest_config_details":{}}`),
+		height,
 	)
 	var details cosmwasm.ConfigDetails
 	if err != nil {
@@ -268,7 +285,7 @@ func (e *envelopeSource) fetchLatestConfigFromLogs(ctx context.Context, blockHei
 		return types.ContractConfig{}, fmt.Errorf("failed to fetch block at height: %w", err)
 	}
 	output := types.ContractConfig{}
-	err = e.extractDataFromTxResponse("wasm-set_config", e.cosmosFeedConfig.ContractAddressBech32, res, map[string]func(string) error{
+	err = e.extractDataFromTxResponse("set_config", e.cosmosFeedConfig.ContractAddressBech32, res, map[string]func(string) error{
 		"latest_config_digest": func(value string) error {
 			// parse byte array encoded as hex string
 			return cosmwasm.HexToConfigDigest(value, &output.ConfigDigest)
@@ -324,12 +341,13 @@ type linkBalanceResponse struct {
 	Balance string `json:"balance"`
 }
 
-func (e *envelopeSource) fetchLinkBalance(ctx context.Context) (*big.Int, error) {
+func (e *envelopeSource) fetchLinkBalance(ctx context.Context, height int64) (*big.Int, error) {
 	query := fmt.Sprintf(`{"balance":{"address":"%s"}}`, e.cosmosFeedConfig.ContractAddressBech32)
-	res, err := e.rpcClient.ContractState(
+	res, err := e.rpcClient.ContractStateAtHeight(
 		ctx,
 		e.cosmosConfig.LinkTokenAddress,
 		[]byte(query),
+		height,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch balance: %w", err)
@@ -349,11 +367,12 @@ type linkAvailableForPaymentRes struct {
 	Amount string `json:"amount,omitempty"`
 }
 
-func (e *envelopeSource) fetchLinkAvailableForPayment(ctx context.Context) (*big.Int, error) {
-	res, err := e.rpcClient.ContractState(
+func (e *envelopeSource) fetchLinkAvailableForPayment(ctx context.Context, height int64) (*big.Int, error) {
+	res, err := e.rpcClient.ContractStateAtHeight(
 		ctx,
 		e.cosmosFeedConfig.ContractAddress,
 		[]byte(`{"link_available_for_payment":{}}`),
+		height,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read link_available_for_payment from the contract: %w", err)
@@ -372,22 +391,22 @@ func (e *envelopeSource) fetchLinkAvailableForPayment(ctx context.Context) (*big
 // Helpers
 
 func (e *envelopeSource) extractDataFromTxResponse(
-	eventType string,
+	wasmEventType string,
 	contractAddressBech32 string,
 	res fcdclient.Response,
 	extractors map[string]func(string) error,
 ) error {
 	// Extract matching events
-	events := extractMatchingEvents(res, eventType, contractAddressBech32)
+	events := extractMatchingEvents(res, wasmEventType, contractAddressBech32)
 	if len(events) == 0 {
-		return fmt.Errorf("no event found with type='%s' and contract_address='%s'", eventType, contractAddressBech32)
+		return fmt.Errorf("no event found with type='wasm-%s' and contract_address='%s'", wasmEventType, contractAddressBech32)
 	}
 	if len(events) != 1 {
-		e.log.Debugw("multiple matching events found, selecting the most recent one which is the first", "type", eventType, "contract_address", contractAddressBech32)
+		e.log.Debugw("multiple matching events found, selecting the most recent one which is the first", "type", wasmEventType, "contract_address", contractAddressBech32)
 	}
 	event := events[0]
 	if err := checkEventAttributes(event, extractors); err != nil {
-		return fmt.Errorf("received incorrect event with type='%s' and contract_address='%s': %w", eventType, contractAddressBech32, err)
+		return fmt.Errorf("received incorrect event with type='wasm-%s' and contract_address='%s': %w", wasmEventType, contractAddressBech32, err)
 	}
 	// Apply extractors.
 	// Note! If multiple attributes with the same key are present, the corresponding
@@ -405,28 +424,15 @@ func (e *envelopeSource) extractDataFromTxResponse(
 	return nil
 }
 
-func extractMatchingEvents(res fcdclient.Response, eventType, contractAddressBech32 string) []fcdclient.Event {
-	out := []fcdclient.Event{}
+func extractMatchingEvents(res fcdclient.Response, wasmEventType, contractAddressBech32 string) []client.WasmEvent {
+	out := []client.WasmEvent{}
 	// Sort txs such that the most recent tx is first
 	sort.Slice(res.Txs, func(i, j int) bool {
 		return res.Txs[i].ID > res.Txs[j].ID
 	})
 	for _, tx := range res.Txs {
-		if !strings.Contains(tx.RawLog, fmt.Sprintf(`"type":"%s"`, eventType)) {
-			continue
-		}
-		for _, event := range tx.Logs[0].Events {
-			if event.Typ != eventType {
-				continue
-			}
-			isMatchingContractAddress := false
-			for _, attribute := range event.Attributes {
-				if attribute.Key == "contract_address" && attribute.Value == contractAddressBech32 {
-					isMatchingContractAddress = true
-					break
-				}
-			}
-			if isMatchingContractAddress {
+		for _, event := range client.DecodeWasmEvents(client.TxEvents(fcdTxResponse(tx))) {
+			if event.Type == wasmEventType && event.ContractAddress == contractAddressBech32 {
 				out = append(out, event)
 			}
 		}
@@ -434,8 +440,33 @@ func extractMatchingEvents(res fcdclient.Response, eventType, contractAddressBec
 	return out
 }
 
+// fcdTxResponse converts the logs and events of an FCD tx into a TxResponse,
+// so that both the legacy and the new event formats can be read the same way.
+func fcdTxResponse(tx fcdclient.Tx) *sdk.TxResponse {
+	res := &sdk.TxResponse{}
+	for _, log := range tx.Logs {
+		var events sdk.StringEvents
+		for _, event := range log.Events {
+			stringEvent := sdk.StringEvent{Type: event.Typ}
+			for _, attribute := range event.Attributes {
+				stringEvent.Attributes = append(stringEvent.Attributes, sdk.Attribute{Key: attribute.Key, Value: attribute.Value})
+			}
+			events = append(events, stringEvent)
+		}
+		res.Logs = append(res.Logs, sdk.ABCIMessageLog{Events: events})
+	}
+	for _, event := range tx.Events {
+		abciEvent := abci.Event{Type: event.Typ}
+		for _, attribute := range event.Attributes {
+			abciEvent.Attributes = append(abciEvent.Attributes, abci.EventAttribute{Key: attribute.Key, Value: attribute.Value})
+		}
+		res.Events = append(res.Events, abciEvent)
+	}
+	return res
+}
+
 func checkEventAttributes(
-	event fcdclient.Event,
+	event client.WasmEvent,
 	extractors map[string]func(string) error,
 ) error {
 	// The event should have at least one attribute with the Key in the extractors map.
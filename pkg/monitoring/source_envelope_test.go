@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/cosmos/btcutil/bech32"
+	tmtypes "github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -70,6 +71,10 @@ func TestEnvelopeSource(t *testing.T) {
 	balanceRes := []byte(`{"balance":"1234567890987654321"}`)
 	latestConfigDetailsRes := []byte(`{"block_number": 6805892}`) // See ./fixtures/set_config-block.json
 	linkAvailableForPaymentRes := []byte(`{"amount":"-380431529018756503364"}`)
+	latestHeight := int64(7364950)
+	latestBlockRes := &tmtypes.GetLatestBlockResponse{SdkBlock: &tmtypes.Block{
+		Header: tmtypes.Header{Height: latestHeight},
+	}}
 	getBlockRaw, err := os.ReadFile("./fixtures/set_config-block.json")
 	require.NoError(t, err)
 	getBlockRes := fcdclient.Response{}
@@ -88,32 +93,39 @@ func TestEnvelopeSource(t *testing.T) {
 	// Setup mocks.
 	rpcClient := mocks.NewChainReader(t)
 	fcdClient := fcdclientmocks.NewClient(t)
+	// All contract state queries are pinned to the latest height
+	rpcClient.On("LatestBlock",
+		mock.Anything, // context
+	).Return(latestBlockRes, nil).Once()
 	// Transmission
 	fcdClient.On("GetTxList",
 		mock.Anything, // context
 		fcdclient.GetTxListParams{Account: feedConfig.ContractAddress, Limit: 10},
 	).Return(getTxsRes, nil).Once()
 	// Configuration
-	rpcClient.On("ContractState",
+	rpcClient.On("ContractStateAtHeight",
 		mock.Anything, // context
 		feedConfig.ContractAddress,
 		[]byte(`{"latest_config_details":{}}`),
+		latestHeight,
 	).Return(latestConfigDetailsRes, nil).Once()
 	fcdClient.On("GetBlockAtHeight",
 		mock.Anything,   // context
 		uint64(6805892), // See ./fixtures/set_config-block.json
 	).Return(getBlockRes, nil).Once()
 	// PLI Balance
-	rpcClient.On("ContractState",
+	rpcClient.On("ContractStateAtHeight",
 		mock.Anything, // context
 		chainConfig.LinkTokenAddress,
 		[]byte(fmt.Sprintf(`{"balance":{"address":"%s"}}`, feedConfig.ContractAddressBech32)),
+		latestHeight,
 	).Return(balanceRes, nil).Once()
 	// PLI available for payment.
-	rpcClient.On("ContractState",
+	rpcClient.On("ContractStateAtHeight",
 		mock.Anything, // context
 		feedConfig.ContractAddress,
 		[]byte(`{"link_available_for_payment":{}}`),
+		latestHeight,
 	).Return(linkAvailableForPaymentRes, nil).Once()
 
 	// Execute Fetch()
@@ -191,11 +203,15 @@ func TestEnvelopeSource(t *testing.T) {
 	// Second Fetch() should get the config from the cache.
 
 	// Setup required mocks.
+	rpcClient.On("LatestBlock",
+		mock.Anything, // context
+	).Return(latestBlockRes, nil).Once()
 	// Configuration
-	rpcClient.On("ContractState",
+	rpcClient.On("ContractStateAtHeight",
 		mock.Anything, // context
 		feedConfig.ContractAddress,
 		[]byte(`{"latest_config_details":{}}`),
+		latestHeight,
 	).Return(latestConfigDetailsRes, nil).Once()
 	// Transmission
 	fcdClient.On("GetTxList",
@@ -203,16 +219,18 @@ func TestEnvelopeSource(t *testing.T) {
 		fcdclient.GetTxListParams{Account: feedConfig.ContractAddress, Limit: 10},
 	).Return(getTxsRes, nil).Once()
 	// PLI Balance
-	rpcClient.On("ContractState",
+	rpcClient.On("ContractStateAtHeight",
 		mock.Anything, // context
 		chainConfig.LinkTokenAddress,
 		[]byte(fmt.Sprintf(`{"balance":{"address":"%s"}}`, feedConfig.ContractAddressBech32)),
+		latestHeight,
 	).Return(balanceRes, nil).Once()
 	// PLI available for payment.
-	rpcClient.On("ContractState",
+	rpcClient.On("ContractStateAtHeight",
 		mock.Anything, // context
 		feedConfig.ContractAddress,
 		[]byte(`{"link_available_for_payment":{}}`),
+		latestHeight,
 	).Return(linkAvailableForPaymentRes, nil).Once()
 
 	// Execute second Fetch()
@@ -220,6 +238,26 @@ func TestEnvelopeSource(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestExtractMatchingEvents(t *testing.T) {
+	contract := "wasm10kc4n52rk4xqny3hdew3ggjfk9r420pqxs9ylf"
+	res := fcdclient.Response{Txs: []fcdclient.Tx{
+		// legacy format, with events in the abci logs
+		{ID: 1, Logs: []fcdclient.Log{{Events: []fcdclient.Event{
+			{Typ: "wasm-transmitted", Attributes: []fcdclient.Attribute{{Key: "contract_address", Value: contract}, {Key: "epoch", Value: "1"}}},
+		}}}},
+		// cosmos-sdk v0.50 format, with no logs
+		{ID: 2, Events: []fcdclient.Event{
+			{Typ: "wasm-transmitted", Attributes: []fcdclient.Attribute{{Key: "_contract_address", Value: contract}, {Key: "epoch", Value: "2"}, {Key: "msg_index", Value: "0"}}},
+			{Typ: "wasm-transmitted", Attributes: []fcdclient.Attribute{{Key: "_contract_address", Value: "wasm1other"}, {Key: "epoch", Value: "3"}}},
+		}},
+	}}
+	events := extractMatchingEvents(res, "transmitted", contract)
+	require.Len(t, events, 2)
+	// most recent first
+	require.Equal(t, []sdk.Attribute{{Key: "epoch", Value: "2"}}, events[0].Attributes)
+	require.Equal(t, []sdk.Attribute{{Key: "epoch", Value: "1"}}, events[1].Attributes)
+}
+
 func mustHexaToByteArr(encoded string) []byte {
 	decoded, err := hex.DecodeString(encoded)
 	if err != nil {